@@ -0,0 +1,131 @@
+package autocomplete
+
+import (
+	"fmt"
+	"strings"
+)
+
+// the maximum number of values retained for each column
+const maxQualifierValues = 20
+
+// QualifierValues :: the values which have been used to qualify the key columns of tables,
+// e.g. the regions used in "where region = 'us-east-1'"
+// values are collected from previously executed queries, most recently used first
+type QualifierValues struct {
+	// map of '<table>.<column>' to values
+	values map[string][]string
+}
+
+// NewQualifierValues creates a QualifierValues populated from the given queries (oldest first)
+func NewQualifierValues(queries []string) *QualifierValues {
+	v := &QualifierValues{values: make(map[string][]string)}
+	for _, query := range queries {
+		v.Add(query)
+	}
+	return v
+}
+
+// Add records the qualifier values of the query
+// these are the string values compared to a column using '=' or 'in', where the column table is known
+func (v *QualifierValues) Add(query string) {
+	for _, statement := range splitStatements(tokenizeSQL(query)) {
+		refs := getTableRefs(statement)
+		for i, t := range statement {
+			if !t.isIdentifier() || i+1 >= len(statement) {
+				continue
+			}
+			values := comparedValues(statement[i+1:])
+			if len(values) == 0 {
+				continue
+			}
+			if ref := getColumnTableRef(refs, t); ref != nil {
+				v.add(ref.table, t.parts[len(t.parts)-1], values)
+			}
+		}
+	}
+}
+
+// Get returns the values which have been used to qualify the column of the table, most recently used first
+func (v *QualifierValues) Get(table, column string) []string {
+	return v.values[qualifierValuesKey(table, column)]
+}
+
+func (v *QualifierValues) add(table, column string, values []string) {
+	key := qualifierValuesKey(table, column)
+	existing := v.values[key]
+	for _, value := range values {
+		// move the value to the front
+		updated := []string{value}
+		for _, e := range existing {
+			if e != value {
+				updated = append(updated, e)
+			}
+		}
+		if len(updated) > maxQualifierValues {
+			updated = updated[:maxQualifierValues]
+		}
+		existing = updated
+	}
+	v.values[key] = existing
+}
+
+func qualifierValuesKey(table, column string) string {
+	return fmt.Sprintf("%s.%s", table, column)
+}
+
+// if the tokens start with a comparison to string values, e.g. "= 'us-east-1'" or "in ('a', 'b')", return the values
+func comparedValues(tokens []*sqlToken) []string {
+	if len(tokens) >= 2 && tokens[0].is("=") && tokens[1].tokenType == tokenString && !tokens[1].unterminated {
+		return []string{tokens[1].text}
+	}
+	if len(tokens) < 2 || !tokens[0].isKeyword("in") || !tokens[1].is("(") {
+		return nil
+	}
+	var values []string
+	for _, t := range tokens[2:] {
+		switch {
+		case t.is(")"):
+			return values
+		case t.tokenType == tokenString && !t.unterminated:
+			values = append(values, t.text)
+		case !t.is(","):
+			// not a list of string values
+			return nil
+		}
+	}
+	return nil
+}
+
+// return the table reference a column belongs to - the column must either be qualified,
+// or the statement must reference a single table
+func getColumnTableRef(refs []*tableRef, column *sqlToken) *tableRef {
+	if len(column.parts) == 1 {
+		if len(refs) == 1 {
+			return refs[0]
+		}
+		return nil
+	}
+	qualifier := strings.Join(column.parts[:len(column.parts)-1], ".")
+	for _, ref := range refs {
+		if ref.matchesQualifier(qualifier) {
+			return ref
+		}
+	}
+	return nil
+}
+
+// split the tokens into statements
+func splitStatements(tokens []*sqlToken) [][]*sqlToken {
+	var statements [][]*sqlToken
+	start := 0
+	for i, t := range tokens {
+		if t.is(";") {
+			statements = append(statements, tokens[start:i])
+			start = i + 1
+		}
+	}
+	if start < len(tokens) {
+		statements = append(statements, tokens[start:])
+	}
+	return statements
+}
@@ -0,0 +1,303 @@
+package autocomplete
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/c-bata/go-prompt"
+	"github.com/turbot/go-kit/helpers"
+	"github.com/turbot/steampipe/schema"
+	"github.com/turbot/steampipe/steampipeconfig"
+)
+
+// SQLCompleterInput :: the input for sql statement completion
+type SQLCompleterInput struct {
+	// the query text - this may contain multiple statements
+	Text string
+	// the byte offset of the cursor in the text
+	Cursor      int
+	Schema      *schema.Metadata
+	Connections *steampipeconfig.ConnectionDataMap
+	// the functions installed in the functions schema
+	Functions []schema.SQLFunc
+	// the qualifier values used by previous queries - may be nil
+	QualifierValues *QualifierValues
+}
+
+// keywords which may be followed by a column or function
+var columnKeywords = map[string]bool{
+	"select": true, "where": true, "and": true, "or": true, "not": true, "on": true, "by": true, "having": true,
+	"distinct": true, "when": true, "then": true, "else": true, "case": true, "between": true, "set": true,
+	"returning": true,
+}
+
+// resolvedTable is a table referenced by a statement, along with its schema (if known)
+type resolvedTable struct {
+	ref    *tableRef
+	schema *schema.TableSchema
+}
+
+// GetSQLAutoCompleteSuggestions :: derives and returns suggestions for the sql statement at the cursor
+// - after 'from', 'join' or a comma in the from clause, the tables
+// - after an alias or table name followed by '.', the columns of the table
+// - within an expression, the columns of all tables referenced by the statement, and the functions
+// - after a comparison of a key column, e.g. "region = ", the qualifier values previously used for the column
+func GetSQLAutoCompleteSuggestions(input *SQLCompleterInput) []prompt.Suggest {
+	statement, cursor := statementAt(tokenizeSQL(input.Text), input.Cursor)
+	before := statement[:cursor]
+
+	// if the cursor is within or at the end of a name or string, that is the word being typed
+	var partial *sqlToken
+	if len(before) > 0 {
+		last := before[len(before)-1]
+		if last.end >= input.Cursor && (last.isName() || last.tokenType == tokenString) {
+			partial = last
+			before = before[:len(before)-1]
+		}
+	}
+	if len(before) == 0 {
+		return nil
+	}
+
+	tables := resolveTables(input.Schema, getTableRefs(statement))
+	clauses := newClauseTracker()
+	for _, t := range before {
+		clauses.update(t)
+	}
+	prev := before[len(before)-1]
+	clause := clauses.get(depthAfter(prev))
+
+	// a qualified name, e.g. 'b.na', is either a column of an aliased table or a schema qualified table
+	if partial != nil && partial.isName() && len(partial.parts) > 1 {
+		qualifier := strings.Join(partial.parts[:len(partial.parts)-1], ".")
+		if matching := tablesWithQualifier(tables, qualifier); len(matching) > 0 {
+			return columnSuggestions(matching, qualifier)
+		}
+	}
+
+	switch {
+	case prev.isKeyword("from", "join") || (prev.is(",") && clause == "from"):
+		return GetTableAutoCompleteSuggestions(input.Schema, input.Connections)
+	case comparedColumn(before) != nil:
+		return qualifierValueSuggestions(tables, comparedColumn(before), input.QualifierValues, input.Connections)
+	case partial != nil && partial.tokenType == tokenString:
+		// a string which is not a qualifier value
+		return nil
+	case clause == "from":
+		// the table alias or join condition are being typed
+		return nil
+	case isWildcard(before):
+		return nil
+	case prev.isName() && len(prev.parts) == 1 && columnKeywords[prev.text],
+		prev.tokenType == tokenOperator,
+		prev.is("("),
+		prev.is(","):
+		s := columnSuggestions(tables, "")
+		return append(s, functionSuggestions(input.Functions)...)
+	}
+	return nil
+}
+
+// return the depth of parentheses after the token
+func depthAfter(t *sqlToken) int {
+	if t.is("(") {
+		return t.depth + 1
+	}
+	return t.depth
+}
+
+// does the final token select all columns, e.g. 'select *', 'b.*' or 'count(*'
+func isWildcard(tokens []*sqlToken) bool {
+	n := len(tokens)
+	if n < 2 || !tokens[n-1].is("*") {
+		return false
+	}
+	prev := tokens[n-2]
+	if prev.isKeyword("select", "distinct") || prev.is(",") || prev.is("(") {
+		return true
+	}
+	// a qualified wildcard, e.g. 'b.*' - the name ends with a '.'
+	return prev.isName() && prev.parts[len(prev.parts)-1] == ""
+}
+
+// resolve the schema of each referenced table
+// unqualified tables are resolved using the search path
+func resolveTables(metadata *schema.Metadata, refs []*tableRef) []*resolvedTable {
+	var res []*resolvedTable
+	for _, ref := range refs {
+		res = append(res, &resolvedTable{ref: ref, schema: resolveTable(metadata, ref)})
+	}
+	return res
+}
+
+func resolveTable(metadata *schema.Metadata, ref *tableRef) *schema.TableSchema {
+	if metadata == nil {
+		return nil
+	}
+	if ref.schema != "" {
+		if table, ok := metadata.Schemas[ref.schema][ref.table]; ok {
+			return &table
+		}
+		return nil
+	}
+	// search the schemas in the search path first, then the temporary schema and finally all other schemas
+	schemas := append([]string{}, metadata.SearchPath...)
+	if metadata.TemporarySchemaName != "" {
+		schemas = append(schemas, metadata.TemporarySchemaName)
+	}
+	schemas = append(schemas, metadata.GetSchemas()...)
+	for _, schemaName := range schemas {
+		if table, ok := metadata.Schemas[schemaName][ref.table]; ok {
+			return &table
+		}
+	}
+	return nil
+}
+
+// return the tables which may be referred to by the qualifier - the alias, table name or schema qualified table name
+func tablesWithQualifier(tables []*resolvedTable, qualifier string) []*resolvedTable {
+	var res []*resolvedTable
+	for _, t := range tables {
+		if t.schema == nil {
+			continue
+		}
+		if t.ref.matchesQualifier(qualifier) {
+			res = append(res, t)
+		}
+	}
+	return res
+}
+
+// return suggestions for the columns of the tables
+// if a qualifier is given, all columns are qualified with it
+// otherwise, columns are only qualified (with the table alias or name) if more than one table has a column of that name
+func columnSuggestions(tables []*resolvedTable, qualifier string) []prompt.Suggest {
+	columnCounts := make(map[string]int)
+	for _, t := range tables {
+		if t.schema == nil {
+			continue
+		}
+		for columnName := range t.schema.Columns {
+			columnCounts[columnName]++
+		}
+	}
+
+	var s []prompt.Suggest
+	added := make(map[string]bool)
+	for _, t := range tables {
+		if t.schema == nil {
+			continue
+		}
+		for columnName, column := range t.schema.Columns {
+			text := columnName
+			if qualifier != "" {
+				text = fmt.Sprintf("%s.%s", qualifier, columnName)
+			} else if columnCounts[columnName] > 1 {
+				text = fmt.Sprintf("%s.%s", t.ref.qualifier(), columnName)
+			}
+			if !added[text] {
+				added[text] = true
+				s = append(s, prompt.Suggest{Text: text, Description: fmt.Sprintf("Column (%s)", column.Type)})
+			}
+		}
+	}
+	sort.Slice(s, func(i, j int) bool {
+		return s[i].Text < s[j].Text
+	})
+	return s
+}
+
+func functionSuggestions(functions []schema.SQLFunc) []prompt.Suggest {
+	var s []prompt.Suggest
+	for _, f := range functions {
+		var params []string
+		for name, paramType := range f.Params {
+			params = append(params, fmt.Sprintf("%s %s", name, paramType))
+		}
+		sort.Strings(params)
+		s = append(s, prompt.Suggest{
+			Text:        f.Name,
+			Description: fmt.Sprintf("Function %s(%s) returns %s", f.Name, strings.Join(params, ", "), f.Returns),
+		})
+	}
+	sort.Slice(s, func(i, j int) bool {
+		return s[i].Text < s[j].Text
+	})
+	return s
+}
+
+// if the tokens end with the comparison of a column, e.g. "region =" or "region in ('us-east-1',",
+// return the column token
+func comparedColumn(tokens []*sqlToken) *sqlToken {
+	n := len(tokens)
+	if n >= 2 && (tokens[n-1].is("=") || tokens[n-1].is("<>") || tokens[n-1].is("!=")) {
+		if tokens[n-2].isIdentifier() {
+			return tokens[n-2]
+		}
+		return nil
+	}
+
+	// find the open parenthesis of an in list - the list may only contain strings
+	i := n - 1
+	for i >= 0 && (tokens[i].tokenType == tokenString || tokens[i].is(",")) {
+		i--
+	}
+	if i < 2 || !tokens[i].is("(") || !tokens[i-1].isKeyword("in") {
+		return nil
+	}
+	column := tokens[i-2]
+	if column.isKeyword("not") && i >= 3 {
+		column = tokens[i-3]
+	}
+	if column.isIdentifier() {
+		return column
+	}
+	return nil
+}
+
+// return suggestions for the qualifier values previously used for the column
+// values are only suggested for the key columns of the tables, as only these are used to qualify plugin calls
+func qualifierValueSuggestions(tables []*resolvedTable, column *sqlToken, qualifierValues *QualifierValues, connections *steampipeconfig.ConnectionDataMap) []prompt.Suggest {
+	if qualifierValues == nil {
+		return nil
+	}
+	columnName := column.parts[len(column.parts)-1]
+	candidates := tables
+	if len(column.parts) > 1 {
+		candidates = tablesWithQualifier(tables, strings.Join(column.parts[:len(column.parts)-1], "."))
+	}
+
+	var s []prompt.Suggest
+	added := make(map[string]bool)
+	for _, t := range candidates {
+		// an unqualified column may belong to any table which has a key column of that name
+		if !isKeyColumn(t, columnName, connections) {
+			continue
+		}
+		for _, value := range qualifierValues.Get(t.ref.table, columnName) {
+			text := quoteLiteral(value)
+			if !added[text] {
+				added[text] = true
+				s = append(s, prompt.Suggest{Text: text, Description: fmt.Sprintf("%s value", columnName)})
+			}
+		}
+	}
+	return s
+}
+
+// is the column a key column of the table, according to the plugin schema of the table connection
+func isKeyColumn(t *resolvedTable, columnName string, connections *steampipeconfig.ConnectionDataMap) bool {
+	if t.schema == nil || connections == nil {
+		return false
+	}
+	connection, ok := (*connections)[t.schema.Schema]
+	if !ok {
+		return false
+	}
+	return helpers.StringSliceContains(connection.KeyColumns[t.schema.Name], columnName)
+}
+
+func quoteLiteral(value string) string {
+	return fmt.Sprintf("'%s'", strings.ReplaceAll(value, "'", "''"))
+}
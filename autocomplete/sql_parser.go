@@ -0,0 +1,331 @@
+package autocomplete
+
+import (
+	"fmt"
+	"strings"
+)
+
+type tokenType int
+
+const (
+	// an identifier or keyword, possibly qualified, e.g. 'select', 'aws_s3_bucket' or 'b.name'
+	tokenName tokenType = iota
+	// a quoted string literal
+	tokenString
+	tokenNumber
+	// an operator, e.g. '=' or '<>'
+	tokenOperator
+	// a single punctuation character, e.g. '(' or ','
+	tokenPunctuation
+)
+
+// sqlToken is a single token of a sql statement
+type sqlToken struct {
+	tokenType tokenType
+	// the text of the token
+	// for names, the lower-cased name parts (quoted parts retain their case) joined with '.'
+	// for strings, the unquoted value
+	text string
+	// the name parts of a name token - the final part is empty if the name ends with a '.'
+	parts []string
+	// the byte offsets of the token in the text
+	start, end int
+	// the depth of parentheses containing the token
+	depth int
+	// is this an unterminated string
+	unterminated bool
+}
+
+func (t *sqlToken) isName() bool {
+	return t.tokenType == tokenName
+}
+
+// is the token one of the given (lower case) keywords
+func (t *sqlToken) isKeyword(keywords ...string) bool {
+	if t.tokenType != tokenName || len(t.parts) != 1 {
+		return false
+	}
+	for _, k := range keywords {
+		if t.text == k {
+			return true
+		}
+	}
+	return false
+}
+
+// is the token an identifier, i.e. a name which is not a reserved keyword
+func (t *sqlToken) isIdentifier() bool {
+	return t.isName() && !(len(t.parts) == 1 && sqlKeywords[t.text])
+}
+
+func (t *sqlToken) is(text string) bool {
+	return (t.tokenType == tokenPunctuation || t.tokenType == tokenOperator) && t.text == text
+}
+
+// keywords which may not be used as an unquoted table alias
+var sqlKeywords = map[string]bool{
+	"all": true, "and": true, "as": true, "asc": true, "between": true, "by": true, "case": true, "cross": true,
+	"delete": true, "desc": true, "distinct": true, "else": true, "end": true, "except": true, "exists": true,
+	"false": true, "fetch": true, "for": true, "from": true, "full": true, "group": true, "having": true, "ilike": true,
+	"in": true, "inner": true, "insert": true, "intersect": true, "into": true, "is": true, "join": true,
+	"lateral": true, "left": true, "like": true, "limit": true, "natural": true, "not": true, "null": true,
+	"offset": true, "on": true, "only": true, "or": true, "order": true, "outer": true, "returning": true,
+	"right": true, "select": true, "set": true, "then": true, "true": true, "union": true, "update": true,
+	"using": true, "values": true, "when": true, "where": true, "window": true, "with": true,
+}
+
+// keywords which start a clause of a statement
+var sqlClauseKeywords = map[string]bool{
+	"select": true, "from": true, "join": true, "on": true, "using": true, "where": true, "group": true,
+	"having": true, "order": true, "limit": true, "offset": true, "union": true, "intersect": true,
+	"except": true, "returning": true, "set": true, "values": true, "window": true,
+}
+
+const operatorChars = "=<>!~+-*/%|&^#@:"
+
+// tokenize the sql text
+// comments are skipped and names are lower-cased, unless quoted
+func tokenizeSQL(text string) []*sqlToken {
+	var tokens []*sqlToken
+	depth := 0
+	for i := 0; i < len(text); {
+		c := text[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+			continue
+		case strings.HasPrefix(text[i:], "--"):
+			i = skipPast(text, i, "\n")
+			continue
+		case strings.HasPrefix(text[i:], "/*"):
+			i = skipPast(text, i+2, "*/")
+			continue
+		}
+
+		token := &sqlToken{start: i, depth: depth}
+		switch {
+		case c == '\'':
+			token.tokenType = tokenString
+			token.text, i, token.unterminated = readString(text, i+1)
+		case isIdentifierStart(c) || c == '"':
+			token.tokenType = tokenName
+			token.parts, i = readName(text, i)
+			token.text = strings.Join(token.parts, ".")
+		case c >= '0' && c <= '9':
+			token.tokenType = tokenNumber
+			for i < len(text) && (text[i] >= '0' && text[i] <= '9' || text[i] == '.') {
+				i++
+			}
+			token.text = text[token.start:i]
+		case strings.IndexByte(operatorChars, c) != -1:
+			token.tokenType = tokenOperator
+			for i < len(text) && strings.IndexByte(operatorChars, text[i]) != -1 && !strings.HasPrefix(text[i:], "--") {
+				i++
+			}
+			token.text = text[token.start:i]
+		default:
+			token.tokenType = tokenPunctuation
+			token.text = string(c)
+			i++
+			switch c {
+			case '(':
+				depth++
+			case ')':
+				if depth > 0 {
+					depth--
+				}
+				token.depth = depth
+			}
+		}
+		token.end = i
+		tokens = append(tokens, token)
+	}
+	return tokens
+}
+
+func isIdentifierStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isIdentifierChar(c byte) bool {
+	return isIdentifierStart(c) || c == '$' || (c >= '0' && c <= '9')
+}
+
+// read a possibly qualified name starting at the given offset, returning the name parts and the end offset
+// unquoted parts are lower-cased
+func readName(text string, i int) ([]string, int) {
+	var parts []string
+	for {
+		var part string
+		if i < len(text) && text[i] == '"' {
+			part, i, _ = readQuoted(text, i+1, '"')
+		} else {
+			start := i
+			for i < len(text) && isIdentifierChar(text[i]) {
+				i++
+			}
+			part = strings.ToLower(text[start:i])
+		}
+		parts = append(parts, part)
+		if i >= len(text) || text[i] != '.' {
+			return parts, i
+		}
+		// skip the '.' - if the name ends with a '.', the final part is empty
+		i++
+		if i < len(text) && !isIdentifierStart(text[i]) && text[i] != '"' {
+			return append(parts, ""), i
+		}
+	}
+}
+
+func readString(text string, i int) (string, int, bool) {
+	return readQuoted(text, i, '\'')
+}
+
+// read a quoted string or identifier starting after the opening quote
+// a doubled quote is an escaped quote
+// returns the unquoted value, the offset after the closing quote and whether the string is unterminated
+func readQuoted(text string, i int, quote byte) (string, int, bool) {
+	var sb strings.Builder
+	for i < len(text) {
+		if text[i] == quote {
+			if i+1 < len(text) && text[i+1] == quote {
+				sb.WriteByte(quote)
+				i += 2
+				continue
+			}
+			return sb.String(), i + 1, false
+		}
+		sb.WriteByte(text[i])
+		i++
+	}
+	return sb.String(), i, true
+}
+
+// return the offset after the next occurrence of the terminator, or the end of the text
+func skipPast(text string, start int, terminator string) int {
+	idx := strings.Index(text[start:], terminator)
+	if idx == -1 {
+		return len(text)
+	}
+	return start + idx + len(terminator)
+}
+
+// return the tokens of the statement containing the offset, and the index of the first token after the offset
+func statementAt(tokens []*sqlToken, offset int) ([]*sqlToken, int) {
+	start, end := 0, len(tokens)
+	for i, t := range tokens {
+		if !t.is(";") {
+			continue
+		}
+		if t.end <= offset {
+			start = i + 1
+		} else {
+			end = i
+			break
+		}
+	}
+	statement := tokens[start:end]
+	cursor := len(statement)
+	for i, t := range statement {
+		if t.start >= offset {
+			cursor = i
+			break
+		}
+	}
+	return statement, cursor
+}
+
+// tableRef is a table referenced in the from clause of a statement
+type tableRef struct {
+	schema string
+	table  string
+	alias  string
+}
+
+// the name used to qualify columns of the table - the alias if there is one, otherwise the table name
+func (r *tableRef) qualifier() string {
+	if r.alias != "" {
+		return r.alias
+	}
+	return r.table
+}
+
+// can columns of the table be qualified by the given name - the alias, or if there is no alias, the (schema qualified) table name
+func (r *tableRef) matchesQualifier(qualifier string) bool {
+	if r.alias != "" {
+		return r.alias == qualifier
+	}
+	return r.table == qualifier || (r.schema != "" && fmt.Sprintf("%s.%s", r.schema, r.table) == qualifier)
+}
+
+// return the tables referenced by the from and join clauses of the statement, including those of subqueries
+func getTableRefs(tokens []*sqlToken) []*tableRef {
+	var refs []*tableRef
+	clauses := newClauseTracker()
+	for i, t := range tokens {
+		clauses.update(t)
+		startsTable := t.isKeyword("from", "join") || (t.is(",") && clauses.get(t.depth) == "from")
+		if !startsTable {
+			continue
+		}
+		if ref := parseTableRef(tokens[i+1:], t.depth); ref != nil {
+			refs = append(refs, ref)
+		}
+	}
+	return refs
+}
+
+// parse a table reference, e.g. 'aws.aws_s3_bucket as b'
+// returns nil if the tokens do not start with a table name, e.g. for a subquery
+func parseTableRef(tokens []*sqlToken, depth int) *tableRef {
+	i := 0
+	for i < len(tokens) && tokens[i].isKeyword("only", "lateral") {
+		i++
+	}
+	if i >= len(tokens) || !tokens[i].isIdentifier() || tokens[i].depth != depth {
+		return nil
+	}
+	name := tokens[i]
+	if len(name.parts) > 2 || name.parts[len(name.parts)-1] == "" {
+		return nil
+	}
+	ref := &tableRef{table: name.parts[len(name.parts)-1]}
+	if len(name.parts) == 2 {
+		ref.schema = name.parts[0]
+	}
+
+	i++
+	if i < len(tokens) && tokens[i].isKeyword("as") {
+		i++
+	}
+	if i < len(tokens) && tokens[i].isIdentifier() && len(tokens[i].parts) == 1 && tokens[i].depth == depth {
+		ref.alias = tokens[i].text
+	}
+	return ref
+}
+
+// clauseTracker tracks the current clause of a statement at each depth of parentheses
+type clauseTracker struct {
+	clauses map[int]string
+}
+
+func newClauseTracker() *clauseTracker {
+	return &clauseTracker{clauses: make(map[int]string)}
+}
+
+func (c *clauseTracker) update(t *sqlToken) {
+	switch {
+	case t.is("("):
+		// a new depth has no clause until a clause keyword is found
+		delete(c.clauses, t.depth+1)
+	case t.isKeyword("join"):
+		c.clauses[t.depth] = "from"
+	case t.isName() && len(t.parts) == 1 && sqlClauseKeywords[t.text]:
+		c.clauses[t.depth] = t.text
+	}
+}
+
+func (c *clauseTracker) get(depth int) string {
+	return c.clauses[depth]
+}
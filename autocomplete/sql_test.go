@@ -0,0 +1,122 @@
+package autocomplete
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/turbot/steampipe/schema"
+	"github.com/turbot/steampipe/steampipeconfig"
+)
+
+type sqlCompletionTest struct {
+	// the query text, with the cursor position marked by '|'
+	text     string
+	expected []string
+}
+
+var testMetadata = &schema.Metadata{
+	Schemas: map[string]map[string]schema.TableSchema{
+		"aws": {
+			"aws_s3_bucket": {Name: "aws_s3_bucket", Schema: "aws", Columns: map[string]schema.ColumnSchema{
+				"name":   {Name: "name", Type: "text"},
+				"region": {Name: "region", Type: "text"},
+			}},
+			"aws_iam_user": {Name: "aws_iam_user", Schema: "aws", Columns: map[string]schema.ColumnSchema{
+				"name": {Name: "name", Type: "text"},
+				"arn":  {Name: "arn", Type: "text"},
+			}},
+		},
+	},
+	SearchPath: []string{"aws"},
+}
+
+// region is a key column of aws_s3_bucket - name is not
+var testConnections = steampipeconfig.ConnectionDataMap{
+	"aws": {Plugin: "hub.steampipe.io/plugins/turbot/aws@latest", KeyColumns: map[string][]string{
+		"aws_s3_bucket": {"region"},
+	}},
+}
+
+var testFunctions = []schema.SQLFunc{{Name: "glob", Params: map[string]string{"input_glob": "text"}, Returns: "text"}}
+
+var testCasesSQLCompletion = map[string]sqlCompletionTest{
+	"tables": {
+		text:     "select * from |",
+		expected: []string{"aws", "aws_iam_user", "aws_s3_bucket", "aws.aws_iam_user", "aws.aws_s3_bucket"},
+	},
+	"tables after comma": {
+		text:     "select * from aws_s3_bucket b, |",
+		expected: []string{"aws", "aws_iam_user", "aws_s3_bucket", "aws.aws_iam_user", "aws.aws_s3_bucket"},
+	},
+	"alias": {
+		text:     "select * from aws_s3_bucket |",
+		expected: nil,
+	},
+	"wildcard": {
+		text:     "select * |",
+		expected: nil,
+	},
+	"columns of table after cursor": {
+		text:     "select | from aws_s3_bucket",
+		expected: []string{"name", "region", "glob"},
+	},
+	"columns of joined tables": {
+		text:     "select b.name, | from aws_s3_bucket as b join aws.aws_iam_user u on u.name = b.name",
+		expected: []string{"arn", "b.name", "region", "u.name", "glob"},
+	},
+	"columns of alias": {
+		text:     "select u.| from aws_s3_bucket b join aws_iam_user u on u.name = b.name",
+		expected: []string{"u.arn", "u.name"},
+	},
+	"columns of schema qualified table": {
+		text:     "select count(*) from aws.aws_s3_bucket where aws.aws_s3_bucket.r|",
+		expected: []string{"aws.aws_s3_bucket.name", "aws.aws_s3_bucket.region"},
+	},
+	"where clause of previous statement": {
+		text:     "select 1; select * from aws_iam_user where |",
+		expected: []string{"arn", "name", "glob"},
+	},
+	"qualifier values": {
+		text:     "select * from aws_s3_bucket where region = |",
+		expected: []string{"'eu-west-2'", "'us-east-1'"},
+	},
+	"values of column which is not a key column": {
+		text:     "select * from aws_s3_bucket where name = |",
+		expected: nil,
+	},
+	"qualifier values of alias in list": {
+		text:     "select * from aws_iam_user u, aws_s3_bucket b where b.region in ('eu-west-2', |",
+		expected: []string{"'eu-west-2'", "'us-east-1'"},
+	},
+	"comment": {
+		text:     "select * -- from\n from aws_s3_bucket where |",
+		expected: []string{"name", "region", "glob"},
+	},
+}
+
+func TestGetSQLAutoCompleteSuggestions(t *testing.T) {
+	qualifierValues := NewQualifierValues([]string{
+		"select * from aws_s3_bucket where region = 'us-east-1'",
+		"select name from aws.aws_s3_bucket b where b.region in ('eu-west-2')",
+		"select * from aws_s3_bucket where name = 'my-bucket'",
+	})
+	for name, test := range testCasesSQLCompletion {
+		cursor := strings.Index(test.text, "|")
+		suggestions := GetSQLAutoCompleteSuggestions(&SQLCompleterInput{
+			Text:            test.text[:cursor] + test.text[cursor+1:],
+			Cursor:          cursor,
+			Schema:          testMetadata,
+			Connections:     &testConnections,
+			Functions:       testFunctions,
+			QualifierValues: qualifierValues,
+		})
+		var actual []string
+		for _, s := range suggestions {
+			actual = append(actual, s.Text)
+		}
+		if !reflect.DeepEqual(actual, test.expected) {
+			t.Errorf("Test: '%s' FAILED : expected %v, got %v", name, test.expected, actual)
+		}
+	}
+}
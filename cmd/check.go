@@ -81,10 +81,14 @@ You may specify one or more benchmarks or controls to run (separated by a space)
 		AddStringFlag(constants.ArgTheme, "", "dark", "Set the output theme, which determines the color scheme for the 'text' control output. Possible values are light, dark, plain").
 		AddStringSliceFlag(constants.ArgExport, "", nil, "Export output to files - multiple exports are allowed").
 		AddBoolFlag(constants.ArgProgress, "", true, "Display control execution progress").
+		AddBoolFlag(constants.ArgCache, "", true, "Enable the query cache for this session").
+		AddIntFlag(constants.ArgCacheTTL, "", 0, "Set the query cache ttl in seconds for this session (if not set, the configured ttl is used)").
 		AddBoolFlag(constants.ArgDryRun, "", false, "Show which controls will be run without running them").
+		AddBoolFlag(constants.ArgShareResults, "", true, "Execute identical control queries once and share the results between the controls").
 		AddStringFlag(constants.ArgWhere, "", "", "SQL 'where' clause , or named query, used to filter controls. Cannot be used with '--tag'").
 		AddStringSliceFlag(constants.ArgTag, "", nil, "Key-Value pairs to filter controls based on the 'tags' property. To be provided as 'key=value'. Multiple can be given and are merged together. Cannot be used with '--where'").
 		AddStringSliceFlag(constants.ArgVarFile, "", nil, "Specify a file containing variable values").
+		AddStringFlag(constants.ArgProfile, "", "", "Specify the workspace profile, defined in the workspace config, which sets the variable files, search path and connection overrides to use").
 		// NOTE: use StringArrayFlag for ArgVariable, not StringSliceFlag
 		// Cobra will interpret values passed to a StringSliceFlag as CSV,
 		// where args passed to StringArrayFlag are not parsed and used raw
@@ -131,12 +135,29 @@ func runCheckCmd(cmd *cobra.Command, args []string) {
 	exportErrorsLock := sync.Mutex{}
 	exportWaitGroup := sync.WaitGroup{}
 	var durations []time.Duration
+	var sharedResults []int
+
+	// if results are shared, a single cache is used for all args, so that a query is executed once for the whole check
+	var resultCache *controlexecute.QueryResultCache
+	if viper.GetBool(constants.ArgShareResults) {
+		resultCache = controlexecute.NewQueryResultCache()
+	}
+
+	// create the execution trees for all args before executing any of them,
+	// so that the result cache knows every control which uses each query
+	executionTrees := make([]*controlexecute.ExecutionTree, len(args))
+	for idx, arg := range args {
+		executionTree, err := controlexecute.NewExecutionTree(ctx, workspace, client, arg, resultCache)
+		utils.FailOnErrorWithMessage(err, "failed to resolve controls from argument")
+		executionTrees[idx] = executionTree
+	}
 
 	// treat each arg as a separate execution
-	for _, arg := range args {
+	for idx, arg := range args {
 
 		if utils.IsContextCancelled(ctx) {
 			durations = append(durations, 0)
+			sharedResults = append(sharedResults, 0)
 			// skip over this arg, since the execution was cancelled
 			// (do not just quit as we want to populate the durations)
 			continue
@@ -146,9 +167,7 @@ func runCheckCmd(cmd *cobra.Command, args []string) {
 		exportFormats, err := getExportTargets(arg)
 		utils.FailOnError(err)
 
-		// create the execution tree
-		executionTree, err := controlexecute.NewExecutionTree(ctx, workspace, client, arg)
-		utils.FailOnErrorWithMessage(err, "failed to resolve controls from argument")
+		executionTree := executionTrees[idx]
 
 		// execute controls synchronously (execute returns the number of failures)
 		failures += executionTree.Execute(ctx, client)
@@ -161,6 +180,7 @@ func runCheckCmd(cmd *cobra.Command, args []string) {
 		}
 
 		durations = append(durations, executionTree.Root.Duration)
+		sharedResults = append(sharedResults, executionTree.SharedResultCount())
 	}
 
 	// wait for exports to complete
@@ -170,7 +190,7 @@ func runCheckCmd(cmd *cobra.Command, args []string) {
 	}
 
 	if shouldPrintTiming() {
-		printTiming(args, durations)
+		printTiming(args, durations, sharedResults)
 	}
 
 	// set global exit code
@@ -234,6 +254,12 @@ func initialiseCheck() *checkInitData {
 	}
 	initData.client = client
 
+	// apply the cache args to the session
+	if err := setCacheOptions(client); err != nil {
+		initData.result.Error = err
+		return initData
+	}
+
 	refreshResult := initData.client.RefreshConnectionAndSearchPaths()
 	if refreshResult.Error != nil {
 		initData.result.Error = refreshResult.Error
@@ -290,11 +316,20 @@ func exportCheckResult(ctx context.Context, d *exportData) {
 	}()
 }
 
-func printTiming(args []string, durations []time.Duration) {
+func printTiming(args []string, durations []time.Duration, sharedResults []int) {
 	headers := []string{"", "Duration"}
+	shareResults := viper.GetBool(constants.ArgShareResults)
+	if shareResults {
+		// show the number of controls which used the result of an identical query run by another control
+		headers = append(headers, "Shared Results")
+	}
 	var rows [][]string
 	for idx, arg := range args {
-		rows = append(rows, []string{arg, durations[idx].String()})
+		row := []string{arg, durations[idx].String()}
+		if shareResults {
+			row = append(row, fmt.Sprintf("%d", sharedResults[idx]))
+		}
+		rows = append(rows, row)
 	}
 	fmt.Println("Timing:")
 	display.ShowWrappedTable(headers, rows, false)
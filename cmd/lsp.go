@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"context"
+	"os"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/turbot/go-kit/helpers"
+	"github.com/turbot/steampipe/cmdconfig"
+	"github.com/turbot/steampipe/constants"
+	"github.com/turbot/steampipe/lsp"
+	"github.com/turbot/steampipe/utils"
+)
+
+// lspCmd :: Run the language server for mod files
+func lspCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "lsp",
+		Args:  cobra.NoArgs,
+		Run:   runLspCmd,
+		Short: "Run the language server for mod files",
+		Long: `Run the language server for mod files.
+
+The language server speaks the Language Server Protocol over stdio, and is intended to
+be started by an editor. It provides:
+
+  - diagnostics for the workspace mod, whenever a file is saved
+  - completion of resource names, variables, block types and attributes
+  - hover information, showing the title and description of a resource
+  - go to definition for resource references
+
+The workspace is the root folder provided by the editor, or the --workspace directory.
+
+Examples:
+
+  # Run the language server for the current directory
+  steampipe lsp`,
+	}
+
+	cmdconfig.OnCmd(cmd)
+
+	return cmd
+}
+
+func runLspCmd(cmd *cobra.Command, args []string) {
+	utils.LogTime("runLspCmd start")
+	defer func() {
+		utils.LogTime("runLspCmd end")
+		if r := recover(); r != nil {
+			utils.ShowError(helpers.ToError(r))
+			exitCode = 1
+		}
+	}()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	startCancelHandler(cancel)
+
+	// stdout is reserved for the protocol - redirect all other output (e.g. errors displayed
+	// when loading the workspace) to stderr
+	stdout := os.Stdout
+	os.Stdout = os.Stderr
+	color.Output = os.Stderr
+
+	server := lsp.NewServer(viper.GetString(constants.ArgWorkspace))
+	if err := server.Run(ctx, os.Stdin, stdout); err != nil {
+		utils.ShowError(err)
+		exitCode = 1
+	}
+}
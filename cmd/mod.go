@@ -0,0 +1,579 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+	"github.com/turbot/go-kit/helpers"
+	"github.com/turbot/steampipe/cmdconfig"
+	"github.com/turbot/steampipe/constants"
+	"github.com/turbot/steampipe/db/db_client"
+	"github.com/turbot/steampipe/db/db_common"
+	"github.com/turbot/steampipe/db/db_local"
+	"github.com/turbot/steampipe/mod/moddocs"
+	"github.com/turbot/steampipe/mod/modfmt"
+	"github.com/turbot/steampipe/mod/modgraph"
+	"github.com/turbot/steampipe/mod/modinit"
+	"github.com/turbot/steampipe/mod/modtest"
+	"github.com/turbot/steampipe/mod/modvalidate"
+	"github.com/turbot/steampipe/utils"
+	"github.com/turbot/steampipe/workspace"
+)
+
+// modCmd :: Mod management commands
+func modCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "mod [command]",
+		Args:  cobra.NoArgs,
+		Short: "Steampipe mod management",
+		Long: `Steampipe mod management.
+
+Mods are collections of queries, controls and benchmarks, defined in the workspace.
+
+Examples:
+
+  # Create a mod in the current directory
+  steampipe mod init
+
+  # Validate the workspace mod
+  steampipe mod validate
+
+  # Run the tests of the workspace mod
+  steampipe mod test
+
+  # Show the resource dependency graph of the workspace mod
+  steampipe mod graph
+
+  # Generate documentation for the workspace mod
+  steampipe mod docs --output docs
+
+  # Format the mod files of the workspace
+  steampipe mod fmt`,
+	}
+
+	cmd.AddCommand(modInitCmd())
+	cmd.AddCommand(modValidateCmd())
+	cmd.AddCommand(modTestCmd())
+	cmd.AddCommand(modGraphCmd())
+	cmd.AddCommand(modDocsCmd())
+	cmd.AddCommand(modFmtCmd())
+
+	return cmd
+}
+
+// modInitCmd :: Create a mod in the workspace directory
+func modInitCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "init",
+		Args:  cobra.NoArgs,
+		Run:   runModInitCmd,
+		Short: "Create a mod in the workspace directory",
+		Long: `Create a mod in the workspace directory.
+
+The mod.sp file is generated with a requires block for all installed plugins. The
+files of the selected template are added - the 'default' template contains an example
+variable, query, control and benchmark, and the 'empty' template contains no resources.
+A steampipe.spvars file is generated for the variables declared by the mod.
+
+Local templates may be added to the templates directory of the install dir
+(~/.steampipe/templates/<name>), or the path of a template directory may be given.
+.sp, .spvars and .md template files may use {{.ModName}} and {{.Title}}. If a template
+contains a mod.sp file, it is used in place of the generated file.
+
+Existing files are never overwritten.
+
+Examples:
+
+  # Create a mod in the current directory
+  steampipe mod init
+
+  # Create a mod with no example resources
+  steampipe mod init --template empty
+
+  # Create a mod from a local template directory
+  steampipe mod init --template ~/mod-templates/aws`,
+	}
+
+	cmdconfig.
+		OnCmd(cmd).
+		AddStringFlag(constants.ArgTemplate, "", modinit.TemplateDefault, "The name of the template, or the path of a template directory")
+
+	return cmd
+}
+
+func runModInitCmd(cmd *cobra.Command, args []string) {
+	utils.LogTime("runModInitCmd start")
+	defer func() {
+		utils.LogTime("runModInitCmd end")
+		if r := recover(); r != nil {
+			utils.ShowError(helpers.ToError(r))
+			exitCode = 1
+		}
+	}()
+
+	workspacePath := viper.GetString(constants.ArgWorkspace)
+	files, err := modinit.Init(workspacePath, viper.GetString(constants.ArgTemplate))
+	utils.FailOnErrorWithMessage(err, "failed to initialise mod")
+
+	fmt.Printf("Created mod in %s\n", workspacePath)
+	for _, file := range files {
+		fmt.Printf("  %s\n", file)
+	}
+}
+
+// modValidateCmd :: Validate the workspace mod
+func modValidateCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "validate",
+		Args:  cobra.NoArgs,
+		Run:   runModValidateCmd,
+		Short: "Validate the workspace mod",
+		Long: `Validate the workspace mod.
+
+Load the workspace mod and report any errors, along with problems such as unresolved
+references, controls whose SQL does not return the required columns, duplicate titles,
+benchmarks with unknown children, unused variables and queries, and required plugins
+which are not installed.
+
+The command exits with a non-zero exit code if any errors are found.
+
+Examples:
+
+  # Validate the mod in the current directory
+  steampipe mod validate
+
+  # Validate a mod, with JSON output
+  steampipe mod validate --workspace ~/mods/my-mod --output json`,
+	}
+
+	cmdconfig.
+		OnCmd(cmd).
+		AddStringFlag(constants.ArgOutput, "", "text", "Select the output format. Possible values are text, json").
+		AddStringSliceFlag(constants.ArgVarFile, "", nil, "Specify a file containing variable values").
+		// NOTE: use StringArrayFlag for ArgVariable, not StringSliceFlag
+		// Cobra will interpret values passed to a StringSliceFlag as CSV,
+		// where args passed to StringArrayFlag are not parsed and used raw
+		AddStringArrayFlag(constants.ArgVariable, "", nil, "Specify The value of a variable")
+
+	return cmd
+}
+
+func runModValidateCmd(cmd *cobra.Command, args []string) {
+	utils.LogTime("runModValidateCmd start")
+	defer func() {
+		utils.LogTime("runModValidateCmd end")
+		if r := recover(); r != nil {
+			utils.ShowError(helpers.ToError(r))
+			exitCode = 1
+		}
+	}()
+
+	output := viper.GetString(constants.ArgOutput)
+	if output != modvalidate.OutputFormatText && output != modvalidate.OutputFormatJSON {
+		utils.ShowError(fmt.Errorf("invalid output format '%s' - must be one of text, json", output))
+		exitCode = 2
+		return
+	}
+
+	diags := modvalidate.Validate(viper.GetString(constants.ArgWorkspace))
+
+	var err error
+	if output == modvalidate.OutputFormatJSON {
+		err = modvalidate.WriteJSON(os.Stdout, diags)
+	} else {
+		err = modvalidate.WriteText(os.Stdout, diags)
+	}
+	utils.FailOnError(err)
+
+	if diags.HasErrors() {
+		exitCode = 1
+	}
+}
+
+// modTestCmd :: Run the tests of the workspace mod
+func modTestCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "test [test names]",
+		Args:  cobra.ArbitraryArgs,
+		Run:   runModTestCmd,
+		Short: "Run the tests of the workspace mod",
+		Long: `Run the tests of the workspace mod.
+
+A test block runs a control against fixture data and asserts the resource and status
+of the control results. The fixture rows are loaded into temporary tables which shadow
+the connection tables queried by the control, so the control SQL must refer to these
+tables without a schema.
+
+By convention, tests are defined in files named *_test.sp, for example:
+
+  test "bucket_versioning_enabled" {
+    control = control.bucket_versioning_enabled
+
+    fixture "aws_s3_bucket" {
+      rows = [
+        { arn = "arn:aws:s3:::b1", name = "b1", versioning_enabled = true },
+        { arn = "arn:aws:s3:::b2", name = "b2", versioning_enabled = false },
+      ]
+    }
+
+    expect = [
+      { resource = "arn:aws:s3:::b1", status = "ok" },
+      { resource = "arn:aws:s3:::b2", status = "alarm" },
+    ]
+  }
+
+The command exits with a non-zero exit code if any test fails.
+
+Examples:
+
+  # Run all tests of the workspace mod
+  steampipe mod test
+
+  # Run a single test
+  steampipe mod test bucket_versioning_enabled
+
+  # Run all tests, writing a JUnit XML report
+  steampipe mod test --output junit > report.xml`,
+	}
+
+	cmdconfig.
+		OnCmd(cmd).
+		AddStringFlag(constants.ArgOutput, "", "text", "Select the output format. Possible values are text, junit").
+		AddStringSliceFlag(constants.ArgVarFile, "", nil, "Specify a file containing variable values").
+		// NOTE: use StringArrayFlag for ArgVariable, not StringSliceFlag
+		// Cobra will interpret values passed to a StringSliceFlag as CSV,
+		// where args passed to StringArrayFlag are not parsed and used raw
+		AddStringArrayFlag(constants.ArgVariable, "", nil, "Specify The value of a variable")
+
+	return cmd
+}
+
+func runModTestCmd(cmd *cobra.Command, args []string) {
+	utils.LogTime("runModTestCmd start")
+	var client db_common.Client
+	defer func() {
+		utils.LogTime("runModTestCmd end")
+		if r := recover(); r != nil {
+			utils.ShowError(helpers.ToError(r))
+			exitCode = 1
+		}
+		if client != nil {
+			client.Close()
+		}
+	}()
+
+	output := viper.GetString(constants.ArgOutput)
+	if output != modtest.OutputFormatText && output != modtest.OutputFormatJUnit {
+		utils.ShowError(fmt.Errorf("invalid output format '%s' - must be one of text, junit", output))
+		exitCode = 2
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	startCancelHandler(cancel)
+
+	// load workspace
+	w, err := loadWorkspacePromptingForVariables(ctx)
+	utils.FailOnErrorWithMessage(err, "failed to load workspace")
+	defer w.Close()
+
+	if len(w.Mod.Tests) == 0 {
+		utils.ShowWarning("no tests found in current workspace")
+		return
+	}
+
+	// get a client
+	if connectionString := viper.GetString(constants.ArgConnectionString); connectionString != "" {
+		client, err = db_client.NewDbClient(connectionString)
+	} else {
+		client, err = db_local.GetLocalClient(constants.InvokerCheck)
+	}
+	utils.FailOnError(err)
+
+	refreshResult := client.RefreshConnectionAndSearchPaths()
+	utils.FailOnError(refreshResult.Error)
+	refreshResult.ShowWarnings()
+
+	report, err := modtest.Run(ctx, w, client, args)
+	utils.FailOnError(err)
+
+	if output == modtest.OutputFormatJUnit {
+		err = modtest.WriteJUnit(os.Stdout, report)
+	} else {
+		err = modtest.WriteText(os.Stdout, report)
+	}
+	utils.FailOnError(err)
+
+	if report.Failed() > 0 || report.Errors() > 0 {
+		exitCode = 1
+	}
+}
+
+// modGraphCmd :: Show the resource dependency graph of the workspace mod
+func modGraphCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "graph [resource]",
+		Args:  cobra.MaximumNArgs(1),
+		Run:   runModGraphCmd,
+		Short: "Show the resource dependency graph of the workspace mod",
+		Long: `Show the resource dependency graph of the workspace mod.
+
+The graph contains the resources of the workspace mod and the resources of dependency
+mods which they depend on. Edges are resource references (e.g. control to query),
+mod tree children (e.g. benchmark to control) and mod requirements (mod to mod).
+
+If a resource is specified, the graph contains the resources it depends on and the
+resources which depend on it - use this to find the impact of changing a shared resource.
+
+Examples:
+
+  # Show the dependency graph of the workspace mod in DOT format
+  steampipe mod graph
+
+  # Render the graph as an image using graphviz
+  steampipe mod graph | dot -Tsvg > graph.svg
+
+  # Show the resources which depend on a query, as a Mermaid flowchart
+  steampipe mod graph query.s3_bucket_versioning --output mermaid`,
+	}
+
+	cmdconfig.
+		OnCmd(cmd).
+		AddStringFlag(constants.ArgOutput, "", modgraph.OutputFormatDOT, "Select the output format. Possible values are dot, mermaid, json").
+		AddStringSliceFlag(constants.ArgVarFile, "", nil, "Specify a file containing variable values").
+		// NOTE: use StringArrayFlag for ArgVariable, not StringSliceFlag
+		// Cobra will interpret values passed to a StringSliceFlag as CSV,
+		// where args passed to StringArrayFlag are not parsed and used raw
+		AddStringArrayFlag(constants.ArgVariable, "", nil, "Specify The value of a variable")
+
+	return cmd
+}
+
+func runModGraphCmd(cmd *cobra.Command, args []string) {
+	utils.LogTime("runModGraphCmd start")
+	defer func() {
+		utils.LogTime("runModGraphCmd end")
+		if r := recover(); r != nil {
+			utils.ShowError(helpers.ToError(r))
+			exitCode = 1
+		}
+	}()
+
+	var write func(io.Writer, *modgraph.Graph) error
+	switch output := viper.GetString(constants.ArgOutput); output {
+	case modgraph.OutputFormatDOT:
+		write = modgraph.WriteDOT
+	case modgraph.OutputFormatMermaid:
+		write = modgraph.WriteMermaid
+	case modgraph.OutputFormatJSON:
+		write = modgraph.WriteJSON
+	default:
+		utils.ShowError(fmt.Errorf("invalid output format '%s' - must be one of dot, mermaid, json", output))
+		exitCode = 2
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	startCancelHandler(cancel)
+
+	w, err := loadWorkspacePromptingForVariables(ctx)
+	utils.FailOnErrorWithMessage(err, "failed to load workspace")
+	defer w.Close()
+
+	var graph *modgraph.Graph
+	if len(args) == 1 {
+		graph, err = modgraph.BuildForResource(w, args[0])
+		utils.FailOnError(err)
+	} else {
+		graph = modgraph.Build(w)
+	}
+
+	utils.FailOnError(write(os.Stdout, graph))
+}
+
+// modDocsCmd :: Generate documentation for the workspace mod
+func modDocsCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "docs",
+		Args:  cobra.NoArgs,
+		Run:   runModDocsCmd,
+		Short: "Generate documentation for the workspace mod",
+		Long: `Generate documentation for the workspace mod.
+
+A Markdown page is generated for the mod and for each benchmark, control, query and
+variable, using their titles, descriptions, documentation and tags. Pages include the
+SQL and params of controls and queries, the benchmark hierarchy, links to referenced
+and referencing resources, and an index of tags.
+
+Examples:
+
+  # Generate Markdown documentation in the 'docs' directory
+  steampipe mod docs
+
+  # Generate Markdown and HTML documentation in the 'site' directory
+  steampipe mod docs --output site --html`,
+	}
+
+	cmdconfig.
+		OnCmd(cmd).
+		AddStringFlag(constants.ArgOutput, "", "docs", "The directory to write the documentation to").
+		AddBoolFlag(constants.ArgHTML, "", false, "Also generate HTML pages").
+		AddStringSliceFlag(constants.ArgVarFile, "", nil, "Specify a file containing variable values").
+		// NOTE: use StringArrayFlag for ArgVariable, not StringSliceFlag
+		// Cobra will interpret values passed to a StringSliceFlag as CSV,
+		// where args passed to StringArrayFlag are not parsed and used raw
+		AddStringArrayFlag(constants.ArgVariable, "", nil, "Specify The value of a variable")
+
+	// accept --output-dir as an alias of --output
+	cmd.Flags().SetNormalizeFunc(func(f *pflag.FlagSet, name string) pflag.NormalizedName {
+		if name == constants.ArgOutputDir {
+			name = constants.ArgOutput
+		}
+		return pflag.NormalizedName(name)
+	})
+
+	return cmd
+}
+
+func runModDocsCmd(cmd *cobra.Command, args []string) {
+	utils.LogTime("runModDocsCmd start")
+	defer func() {
+		utils.LogTime("runModDocsCmd end")
+		if r := recover(); r != nil {
+			utils.ShowError(helpers.ToError(r))
+			exitCode = 1
+		}
+	}()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	startCancelHandler(cancel)
+
+	w, err := loadWorkspacePromptingForVariables(ctx)
+	utils.FailOnErrorWithMessage(err, "failed to load workspace")
+	defer w.Close()
+
+	outputDir := viper.GetString(constants.ArgOutput)
+	files, err := moddocs.Generate(w, outputDir, viper.GetBool(constants.ArgHTML))
+	utils.FailOnErrorWithMessage(err, "failed to generate documentation")
+
+	fmt.Printf("Generated %d %s in %s\n", len(files), utils.Pluralize("file", len(files)), outputDir)
+}
+
+// modFmtCmd :: Format the mod files of the workspace
+func modFmtCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "fmt [path...]",
+		Run:   runModFmtCmd,
+		Short: "Format the mod files of the workspace",
+		Long: `Format the mod files of the workspace.
+
+Mod files are rewritten in the canonical HCL format. Heredocs are converted to
+indented heredocs (<<-EOQ), with their content (e.g. SQL) indented one level deeper
+than the attribute, keeping its relative indentation. Formatting never changes the
+value of an attribute - a file is not written if it would.
+
+If paths are given, only those files (or the mod files in those directories) are
+formatted. The paths of the files which were changed are listed.
+
+With --check, no files are written and the exit code is 1 if any file needs
+formatting - use this in pre-commit hooks and CI.
+
+Examples:
+
+  # Format all mod files of the workspace
+  steampipe mod fmt
+
+  # List the files which need formatting, without changing them
+  steampipe mod fmt --check
+
+  # Show the changes formatting would make, without changing any files
+  steampipe mod fmt --check --diff`,
+	}
+
+	cmdconfig.
+		OnCmd(cmd).
+		AddBoolFlag(constants.ArgCheck, "", false, "Do not write files - exit with code 1 if any file needs formatting").
+		AddBoolFlag(constants.ArgDiff, "", false, "Show the formatting changes as a diff")
+
+	return cmd
+}
+
+func runModFmtCmd(cmd *cobra.Command, args []string) {
+	utils.LogTime("runModFmtCmd start")
+	defer func() {
+		utils.LogTime("runModFmtCmd end")
+		if r := recover(); r != nil {
+			utils.ShowError(helpers.ToError(r))
+			exitCode = 1
+		}
+	}()
+
+	check := viper.GetBool(constants.ArgCheck)
+	showDiff := viper.GetBool(constants.ArgDiff)
+
+	files, err := getModFmtFiles(args)
+	utils.FailOnError(err)
+
+	var changed int
+	for _, file := range files {
+		result, err := modfmt.FormatFile(file)
+		if err != nil {
+			utils.ShowError(err)
+			exitCode = 1
+			continue
+		}
+		if !result.Changed() {
+			continue
+		}
+		changed++
+		if showDiff {
+			fmt.Print(result.Diff())
+		} else {
+			fmt.Println(file)
+		}
+		if !check {
+			if err := result.Write(); err != nil {
+				utils.ShowError(err)
+				exitCode = 1
+			}
+		}
+	}
+
+	if check && changed > 0 {
+		exitCode = 1
+	}
+}
+
+// return the hcl mod files to format - if no paths are given, all mod files of the workspace
+func getModFmtFiles(paths []string) ([]string, error) {
+	if len(paths) == 0 {
+		paths = []string{viper.GetString(constants.ArgWorkspace)}
+	}
+
+	var files []string
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			files = append(files, path)
+			continue
+		}
+		dirFiles, err := workspace.ListModFiles(path)
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range dirFiles {
+			if modfmt.IsHclFile(file) {
+				files = append(files, file)
+			}
+		}
+	}
+	return files, nil
+}
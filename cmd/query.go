@@ -17,8 +17,10 @@ import (
 	"github.com/turbot/steampipe/db/db_client"
 	"github.com/turbot/steampipe/db/db_common"
 	"github.com/turbot/steampipe/db/db_local"
+	"github.com/turbot/steampipe/display"
 	"github.com/turbot/steampipe/interactive"
 	"github.com/turbot/steampipe/query/queryexecute"
+	"github.com/turbot/steampipe/query/queryhistory"
 	"github.com/turbot/steampipe/steampipeconfig/modconfig"
 	"github.com/turbot/steampipe/utils"
 	"github.com/turbot/steampipe/workspace"
@@ -44,7 +46,16 @@ Examples:
   steampipe query
 
   # Run a specific query directly
-  steampipe query "select * from cloud"`,
+  steampipe query "select * from cloud"
+
+  # Show which connections and tables a query spends its time in
+  steampipe query "select * from aws_s3_bucket" --analyze
+
+  # Run a query, exporting the results to csv and json files as well as displaying them
+  steampipe query "select * from cloud" --export out.csv --export out.json
+
+  # List the query history, filtered by a search string
+  steampipe query --history "aws_s3"`,
 
 		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 			workspace, err := workspace.LoadResourceNames(viper.GetString(constants.ArgWorkspace))
@@ -67,12 +78,21 @@ Examples:
 		OnCmd(cmd).
 		AddBoolFlag(constants.ArgHeader, "", true, "Include column headers csv and table output").
 		AddStringFlag(constants.ArgSeparator, "", ",", "Separator string for csv output").
-		AddStringFlag(constants.ArgOutput, "", "table", "Output format: line, csv, json or table").
+		AddStringFlag(constants.ArgOutput, "", "table", "Output format: line, csv, json, table, markdown, html, yaml or ndjson").
 		AddBoolFlag(constants.ArgTimer, "", false, "Turn on the timer which reports query time.").
+		AddBoolFlag(constants.ArgCache, "", true, "Enable the query cache for this session").
+		AddIntFlag(constants.ArgCacheTTL, "", 0, "Set the query cache ttl in seconds for this session (if not set, the configured ttl is used)").
+		AddBoolFlag(constants.ArgAnalyze, "", false, "Show the time taken and rows returned by each connection and table scanned by the query, rather than the query results").
+		AddBoolFlag(constants.ArgStreaming, "", false, "Stream rows as they are received rather than buffering the full result (enabled automatically for large results)").
 		AddBoolFlag(constants.ArgWatch, "", true, "Watch SQL files in the current workspace (works only in interactive mode)").
+		AddStringSliceFlag(constants.ArgExport, "", nil, "Export query results to files, inferring the format from the file extension - multiple exports are allowed. When running more than one query, the results of each query are written to a separate file").
+		AddBoolFlag(constants.ArgExportCombined, "", false, "When exporting the results of more than one query, write all results to the same file (csv, ndjson, markdown, html and yaml exports only - json exports are always written to a file per query)").
+		AddBoolFlag(constants.ArgHistory, "", false, "List the query history, filtered by any query arguments, and exit").
+		AddBoolFlag(constants.ArgWorkspaceHistory, "", false, "Store the query history in the workspace rather than in the install directory").
 		AddStringSliceFlag(constants.ArgSearchPath, "", nil, "Set a custom search_path for the steampipe user for a query session (comma-separated)").
 		AddStringSliceFlag(constants.ArgSearchPathPrefix, "", nil, "Set a prefix to the current search path for a query session (comma-separated)").
 		AddStringSliceFlag(constants.ArgVarFile, "", nil, "Specify a file containing variable values").
+		AddStringFlag(constants.ArgProfile, "", "", "Specify the workspace profile, defined in the workspace config, which sets the variable files, search path and connection overrides to use").
 		// NOTE: use StringArrayFlag for ArgVariable, not StringSliceFlag
 		// Cobra will interpret values passed to a StringSliceFlag as CSV,
 		// where args passed to StringArrayFlag are not parsed and used raw
@@ -90,6 +110,12 @@ func runQueryCmd(cmd *cobra.Command, args []string) {
 		}
 	}()
 
+	// if the history flag is set, just display the history - the args are used as a filter
+	if viper.GetBool(constants.ArgHistory) {
+		display.ShowQueryHistory(queryhistory.New().Search(strings.Join(args, " ")))
+		return
+	}
+
 	if stdinData := getPipedStdinData(); len(stdinData) > 0 {
 		args = append(args, stdinData)
 	}
@@ -99,6 +125,9 @@ func runQueryCmd(cmd *cobra.Command, args []string) {
 
 	// enable spinner only in interactive mode
 	interactiveMode := len(args) == 0
+	if interactiveMode && len(viper.GetStringSlice(constants.ArgExport)) > 0 {
+		utils.FailOnError(fmt.Errorf("--%s is not supported in interactive mode - use the %s metaquery", constants.ArgExport, constants.CmdExport))
+	}
 	cmdconfig.Viper().Set(constants.ConfigKeyShowInteractiveOutput, interactiveMode)
 	// set config to indicate whether we are running an interactive query
 	viper.Set(constants.ConfigKeyInteractive, interactiveMode)
@@ -228,6 +257,12 @@ func getQueryInitDataAsync(ctx context.Context, w *workspace.Workspace, initData
 		}
 		initData.Client = client
 
+		// apply the cache args to the session
+		if err := setCacheOptions(client); err != nil {
+			initData.Result.Error = err
+			return
+		}
+
 		// check if the required plugins are installed
 		if err := w.CheckRequiredPluginsInstalled(); err != nil {
 			initData.Result.Error = err
@@ -273,6 +308,19 @@ func getQueryInitDataAsync(ctx context.Context, w *workspace.Workspace, initData
 	}()
 }
 
+// apply the --cache and --cache-ttl args to the client session
+func setCacheOptions(client db_common.Client) error {
+	if !viper.GetBool(constants.ArgCache) {
+		if err := client.CacheOff(); err != nil {
+			return err
+		}
+	}
+	if ttl := viper.GetInt(constants.ArgCacheTTL); ttl > 0 {
+		return client.CacheSetTTL(ttl)
+	}
+	return nil
+}
+
 func startCancelHandler(cancel context.CancelFunc) {
 	sigIntChannel := make(chan os.Signal, 1)
 	signal.Notify(sigIntChannel, os.Interrupt)
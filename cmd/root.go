@@ -119,6 +119,8 @@ func createLogger() {
 	if options.Output == nil {
 		options.Output = os.Stderr
 	}
+	// ensure the values of sensitive variables are never logged
+	options.Output = utils.NewRedactingWriter(options.Output)
 	logger := hclog.New(options)
 	log.SetOutput(logger.StandardWriter(&hclog.StandardLoggerOptions{InferLevels: true}))
 	log.SetPrefix("")
@@ -146,6 +148,8 @@ func AddCommands() {
 		queryCmd(),
 		checkCmd(),
 		serviceCmd(),
+		modCmd(),
+		lspCmd(),
 		generateCompletionScriptsCmd(),
 	)
 }
@@ -6,12 +6,19 @@ const (
 	ArgCSV              = "csv"
 	ArgTable            = "table"
 	ArgLine             = "line"
+	ArgMarkdown         = "markdown"
+	ArgHTML             = "html"
+	ArgYAML             = "yaml"
+	ArgNDJSON           = "ndjson"
 	ArgForce            = "force"
 	ArgAll              = "all"
 	ArgTimer            = "timing"
 	ArgOn               = "on"
 	ArgOff              = "off"
 	ArgClear            = "clear"
+	ArgTTL              = "ttl"
+	ArgStatus           = "status"
+	ArgSet              = "set"
 	ArgPort             = "database-port"
 	ArgListenAddress    = "database-listen"
 	ArgServicePassword  = "database-password"
@@ -26,12 +33,25 @@ const (
 	ArgTheme            = "theme"
 	ArgProgress         = "progress"
 	ArgExport           = "export"
+	ArgExportCombined   = "export-combined"
+	ArgStreaming        = "streaming"
+	ArgAnalyze          = "analyze"
+	ArgCache            = "cache"
+	ArgCacheTTL         = "cache-ttl"
 	ArgDryRun           = "dry-run"
 	ArgWhere            = "where"
 	ArgTag              = "tag"
 	ArgVariable         = "var"
 	ArgVarFile          = "var-file"
+	ArgProfile          = "profile"
 	ArgConnectionString = "connection-string"
+	ArgHistory          = "history"
+	ArgWorkspaceHistory = "workspace-history"
+	ArgTemplate         = "template"
+	ArgCheck            = "check"
+	ArgDiff             = "diff"
+	ArgShareResults     = "share-results"
+	ArgOutputDir        = "output-dir"
 )
 
 /// metaquery mode arguments
@@ -40,6 +60,7 @@ var ArgOutput = ArgFromMetaquery(CmdOutput)
 var ArgSeparator = ArgFromMetaquery(CmdSeparator)
 var ArgHeader = ArgFromMetaquery(CmdHeaders)
 var ArgMultiLine = ArgFromMetaquery(CmdMulti)
+var ArgTee = ArgFromMetaquery(CmdTee)
 
 // BoolToOnOff :: convert a boolean value onto the string "on" or "off"
 func BoolToOnOff(val bool) string {
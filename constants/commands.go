@@ -19,6 +19,11 @@ const (
 	CmdSearchPath       = ".search_path"        // Set or show search-path
 	CmdSearchPathPrefix = ".search_path_prefix" // set search path prefix
 	CmdCache            = ".cache"              // cache control
+	CmdHistory          = ".history"            // list or search query history
+	CmdExport           = ".export"             // export the last query result to a file
+	CmdTee              = ".tee"                // write all subsequent query results to a file
+	CmdExplain          = ".explain"            // show the query plan and per-connection timings for a query
+	CmdVar              = ".var"                // set the session value of a mod variable
 )
 
 // ArgFromMetaquery converts a metaquery of form '.header' into the config argument used to set the mode, i.e. 'header'
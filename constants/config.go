@@ -46,6 +46,11 @@ func ModsDir() string {
 	return steampipeSubDir("mods")
 }
 
+// TemplatesDir returns the path to the mod templates directory (creates if missing)
+func TemplatesDir() string {
+	return steampipeSubDir("templates")
+}
+
 // ConfigDir returns the path to the config directory (creates if missing)
 func ConfigDir() string {
 	return steampipeSubDir("config")
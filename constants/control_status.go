@@ -7,3 +7,6 @@ const (
 	ControlInfo  = "info"
 	ControlError = "error"
 )
+
+// ControlStatuses is the list of valid control result statuses
+var ControlStatuses = []string{ControlOk, ControlAlarm, ControlInfo, ControlError, ControlSkip}
@@ -41,12 +41,18 @@ const (
 	FunctionSchema = "internal"
 
 	// CommandSchema is the schema which is used to send commands to the FDW
-	CommandSchema               = "steampipe_command"
-	CacheCommandTable           = "cache"
-	CacheCommandOperationColumn = "operation"
-	CommandCacheOn              = "cache_on"
-	CommandCacheOff             = "cache_off"
-	CommandCacheClear           = "cache_clear"
+	CommandSchema                = "steampipe_command"
+	CacheCommandTable            = "cache"
+	CacheCommandOperationColumn  = "operation"
+	CacheCommandConnectionColumn = "connection"
+	CacheCommandTableColumn      = "table"
+	CacheCommandTTLColumn        = "ttl"
+	CommandCacheOn               = "cache_on"
+	CommandCacheOff              = "cache_off"
+	CommandCacheClear            = "cache_clear"
+	CommandCacheTTL              = "cache_ttl"
+	// CacheStatusTable is the command schema table which reports the cache status of each connection
+	CacheStatusTable = "cache_status"
 )
 
 // Functions :: a list of SQLFunc objects that are installed in the db 'internal' schema startup
@@ -64,6 +70,29 @@ begin
 	output_pattern = replace(output_pattern, '?', '_');
 	return output_pattern;
 end;
+`,
+	},
+	{
+		// return the value of a mod variable, as text, from the steampipe_variable introspection table
+		// the name may be given with or without the 'var.' prefix
+		Name:     "var",
+		Params:   map[string]string{"var_name": "text"},
+		Returns:  "text",
+		Language: "plpgsql",
+		Body: `
+declare
+	var_value text;
+	var_found boolean;
+begin
+	select coalesce(value, default_value) #>> '{}', true into var_value, var_found
+	from steampipe_variable
+	where name = var_name or name = 'var.' || var_name
+	limit 1;
+	if var_found is null then
+		raise exception 'variable ''%'' is not defined', var_name;
+	end if;
+	return var_value;
+end;
 `,
 	},
 }
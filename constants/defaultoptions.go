@@ -26,6 +26,7 @@ const DefaultSPCContent = `
 #   search_path         =  ""     # comma-separated string
 #   search_path_prefix  =  ""     # comma-separated string
 #   watch  			    =  true   # true, false
+#   workspace_history   =  false  # true, false
 # }
 
 # options "general" {
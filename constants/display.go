@@ -12,4 +12,9 @@ const (
 
 	// what do we display for null column values
 	NullString = "<null>"
+
+	// StreamingRowThreshold :: the number of rows after which results are streamed rather than buffered
+	StreamingRowThreshold = 10000
+	// StreamingTablePageSize :: the number of rows rendered in each table when streaming table output
+	StreamingTablePageSize = 1000
 )
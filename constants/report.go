@@ -0,0 +1,11 @@
+package constants
+
+import "time"
+
+// Report constants
+const (
+	// ReportResultCacheTTL :: duration for which report panel query results are cached
+	ReportResultCacheTTL = 5 * time.Minute
+	// ReportResultCacheMaxEntries :: the maximum number of report panel query results which are cached
+	ReportResultCacheMaxEntries = 1000
+)
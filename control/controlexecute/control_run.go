@@ -38,6 +38,8 @@ type ControlRun struct {
 
 	// execution duration
 	Duration time.Duration `json:"-"`
+	// if the control used the query result of another control which ran the same query, the name of that control
+	SharedResultFrom string `json:"-"`
 
 	// the result
 	ControlId   string            `json:"control_id"`
@@ -55,6 +57,9 @@ type ControlRun struct {
 
 	group         *ResultGroup
 	executionTree *ExecutionTree
+
+	// if query results are shared between controls, the key of the query result in the result cache
+	resultCacheKey string
 }
 
 func NewControlRun(control *modconfig.Control, group *ResultGroup, executionTree *ExecutionTree) *ControlRun {
@@ -104,6 +109,16 @@ func (r *ControlRun) Start(ctx context.Context, client db_common.Client) {
 		return
 	}
 
+	// if the query result is shared with other controls, use the result of any control which has already run the query
+	// (if this is a retry, the result has already been looked for)
+	if r.resultCacheKey != "" && r.attempts == 0 {
+		if entry, ok := r.executionTree.resultCache.Get(r.resultCacheKey); ok {
+			r.useSharedResult(entry)
+			r.Duration = time.Since(startTime)
+			return
+		}
+	}
+
 	// set a log line in the database logs for convenience
 	// pass 'true' to disable spinner
 	_, _ = client.ExecuteSync(ctx, fmt.Sprintf("--- Executing %s", control.GetTitle()), true)
@@ -180,20 +195,19 @@ func (r *ControlRun) Start(ctx context.Context, client db_common.Client) {
 }
 
 func (r *ControlRun) gatherResults() {
+	// if the query result is shared and other controls are still to use it, keep the rows to add to the cache
+	keepRows := r.resultCacheKey != "" && r.executionTree.resultCache.IsWanted(r.resultCacheKey)
+	var rows []*queryresult.RowResult
 	for {
 		select {
 		case row := <-*r.queryResult.RowChan:
 			if row == nil {
-				// update the result group status with our status - this will be passed all the way up the execution tree
-				r.group.updateSummary(r.Summary)
-				if len(r.Severity) != 0 {
-					r.group.updateSeverityCounts(r.Severity, r.Summary)
+				// nil row means we are done - if the query result is shared, add it to the cache
+				if keepRows {
+					r.executionTree.resultCache.Set(r.resultCacheKey, r.Control.Name(), r.queryResult.ColTypes, rows)
 				}
-
-				// nil row means we are done
-				r.setRunStatus(ControlRunComplete)
+				r.complete()
 				return
-
 			}
 			// got a result - send a ping over the channel so that the
 			// loop can check against the timeout
@@ -207,12 +221,66 @@ func (r *ControlRun) gatherResults() {
 				return
 			}
 			r.addResultRow(result)
+			if keepRows {
+				rows = append(rows, row)
+			}
 		case <-r.doneChan:
 			return
 		}
 	}
 }
 
+// populate our results from the query result of another control which ran the same query
+func (r *ControlRun) useSharedResult(entry *queryResultCacheEntry) {
+	log.Printf("[TRACE] %s using the query result of %s\n", r.Control.Name(), entry.controlName)
+	r.SharedResultFrom = entry.controlName
+	for _, row := range entry.rows {
+		result, err := NewResultRow(r.Control, row, entry.colTypes)
+		if err != nil {
+			r.SetError(err)
+			return
+		}
+		r.addResultRow(result)
+	}
+	r.complete()
+}
+
+// return the key used to share the query result between controls
+// controls with the same sql, args and search path will have the same result
+func (r *ControlRun) getResultCacheKey() (string, error) {
+	control := r.Control
+	source, err := r.executionTree.workspace.ResolveControlQuerySource(control)
+	if err != nil {
+		return "", err
+	}
+	var sql string
+	switch s := source.(type) {
+	case *modconfig.Query:
+		sql = typehelpers.SafeString(s.SQL)
+	case *modconfig.Control:
+		sql = typehelpers.SafeString(s.SQL)
+	}
+	params, err := control.Args.ResolveAsString(source)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s\nparams: %s\nsearch_path: %s\nsearch_path_prefix: %s",
+		sql,
+		params,
+		typehelpers.SafeString(control.SearchPath),
+		typehelpers.SafeString(control.SearchPathPrefix)), nil
+}
+
+// update the result group with our status and set our status to complete
+func (r *ControlRun) complete() {
+	// update the result group status with our status - this will be passed all the way up the execution tree
+	r.group.updateSummary(r.Summary)
+	if len(r.Severity) != 0 {
+		r.group.updateSeverityCounts(r.Severity, r.Summary)
+	}
+	r.setRunStatus(ControlRunComplete)
+}
+
 // add the result row to our results and update the summary with the row status
 func (r *ControlRun) addResultRow(row *ResultRow) {
 	// update results
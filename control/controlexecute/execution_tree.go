@@ -28,10 +28,15 @@ type ExecutionTree struct {
 	DimensionColorGenerator *DimensionColorGenerator
 	// flat list of all control runs
 	controlRuns []*ControlRun
+	// if set, controls which run the same query share the query result
+	// (the cache may be shared with other execution trees)
+	resultCache *QueryResultCache
 }
 
 // NewExecutionTree creates a result group from a ModTreeItem
-func NewExecutionTree(ctx context.Context, workspace *workspace.Workspace, client db_common.Client, arg string) (*ExecutionTree, error) {
+// if resultCache is set, controls which run the same query only execute it once - the cache may be shared by
+// several execution trees, as long as all of the trees are created before any of them are executed
+func NewExecutionTree(ctx context.Context, workspace *workspace.Workspace, client db_common.Client, arg string, resultCache *QueryResultCache) (*ExecutionTree, error) {
 	// now populate the ExecutionTree
 	executionTree := &ExecutionTree{
 		workspace: workspace,
@@ -57,9 +62,31 @@ func NewExecutionTree(ctx context.Context, workspace *workspace.Workspace, clien
 	// after tree has built, ControlCount will be set - create progress rendered
 	executionTree.progress = NewControlProgressRenderer(len(executionTree.controlRuns))
 
+	if resultCache != nil {
+		executionTree.groupSharedQueries(resultCache)
+	}
+
 	return executionTree, nil
 }
 
+// register the query of each control run with the result cache, keyed by the query, args and search path
+// for each query, only the first control to run executes it - any others, in this or another execution tree
+// using the same cache, use its result
+// the results of queries run by a single control are not cached
+func (e *ExecutionTree) groupSharedQueries(resultCache *QueryResultCache) {
+	e.resultCache = resultCache
+	for _, r := range e.controlRuns {
+		key, err := r.getResultCacheKey()
+		if err != nil {
+			// the control will execute its query as normal
+			log.Printf("[TRACE] %s cannot share its query result: %s\n", r.Control.Name(), err.Error())
+			continue
+		}
+		resultCache.AddConsumers(key, 1)
+		r.resultCacheKey = key
+	}
+}
+
 // AddControl checks whether control should be included in the tree
 // if so, creates a ControlRun, which is added to the parent group
 func (e *ExecutionTree) AddControl(control *modconfig.Control, group *ResultGroup) {
@@ -88,6 +115,17 @@ func (e *ExecutionTree) Execute(ctx context.Context, client db_common.Client) in
 	return errors
 }
 
+// SharedResultCount returns the number of control runs which used the query result of another control
+func (e *ExecutionTree) SharedResultCount() int {
+	count := 0
+	for _, r := range e.controlRuns {
+		if r.SharedResultFrom != "" {
+			count++
+		}
+	}
+	return count
+}
+
 func (e *ExecutionTree) populateControlFilterMap(ctx context.Context) error {
 	// if both '--where' and '--tag' have been used, then it's an error
 	if viper.IsSet(constants.ArgWhere) && viper.IsSet(constants.ArgTag) {
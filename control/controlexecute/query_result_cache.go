@@ -0,0 +1,81 @@
+package controlexecute
+
+import (
+	"database/sql"
+	"sync"
+
+	"github.com/turbot/steampipe/query/queryresult"
+)
+
+// QueryResultCache is an in-process cache of control query results, keyed by the SQL, args and search path of the control
+// it may be shared by the execution trees of several check arguments
+// only the results of queries run by more than one control are cached,
+// and each result is released once the last of these controls has used it
+type QueryResultCache struct {
+	entries map[string]*queryResultCacheEntry
+	// map of key to the number of controls which use the result and have not yet started
+	consumers map[string]int
+	lock      sync.Mutex
+}
+
+type queryResultCacheEntry struct {
+	// the name of the control which executed the query
+	controlName string
+	colTypes    []*sql.ColumnType
+	rows        []*queryresult.RowResult
+}
+
+func NewQueryResultCache() *QueryResultCache {
+	return &QueryResultCache{
+		entries:   make(map[string]*queryResultCacheEntry),
+		consumers: make(map[string]int),
+	}
+}
+
+// AddConsumers registers the number of controls which use the result for the given key
+func (c *QueryResultCache) AddConsumers(key string, count int) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.consumers[key] += count
+}
+
+// Get is called by each control which uses the result for the given key when it starts
+// it returns the cached result, if there is one - if this is the last control to use the result, the result is released
+func (c *QueryResultCache) Get(key string) (*queryResultCacheEntry, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.consumers[key]--
+	entry, ok := c.entries[key]
+	if c.consumers[key] <= 0 {
+		delete(c.entries, key)
+		delete(c.consumers, key)
+	}
+	return entry, ok
+}
+
+// IsWanted returns whether any control which uses the result for the given key has not yet started
+// - if not, there is no need to gather the result rows
+func (c *QueryResultCache) IsWanted(key string) bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.consumers[key] > 0
+}
+
+// Set adds the result of the query executed by the given control to the cache,
+// as long as it will be used by another control
+func (c *QueryResultCache) Set(key string, controlName string, colTypes []*sql.ColumnType, rows []*queryresult.RowResult) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.consumers[key] <= 0 {
+		return
+	}
+	c.entries[key] = &queryResultCacheEntry{
+		controlName: controlName,
+		colTypes:    colTypes,
+		rows:        rows,
+	}
+}
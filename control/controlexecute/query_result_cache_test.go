@@ -0,0 +1,226 @@
+package controlexecute
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"testing"
+
+	"github.com/turbot/steampipe/constants"
+	"github.com/turbot/steampipe/db/db_common"
+	"github.com/turbot/steampipe/query/queryresult"
+	"github.com/turbot/steampipe/steampipeconfig/modconfig"
+	"github.com/turbot/steampipe/utils"
+	"github.com/turbot/steampipe/workspace"
+)
+
+// testColumnsDriver is a database driver whose queries return no rows, with the columns of a control query
+// it is used to create column types, which cannot be constructed directly
+type testColumnsDriver struct{}
+
+func (testColumnsDriver) Open(string) (driver.Conn, error) { return testColumnsConn{}, nil }
+
+type testColumnsConn struct{}
+
+func (testColumnsConn) Prepare(query string) (driver.Stmt, error) { return testColumnsStmt(query), nil }
+func (testColumnsConn) Close() error                              { return nil }
+func (testColumnsConn) Begin() (driver.Tx, error)                 { return nil, driver.ErrSkip }
+
+type testColumnsStmt string
+
+func (testColumnsStmt) Close() error                               { return nil }
+func (testColumnsStmt) NumInput() int                              { return 0 }
+func (testColumnsStmt) Exec([]driver.Value) (driver.Result, error) { return nil, driver.ErrSkip }
+func (s testColumnsStmt) Query([]driver.Value) (driver.Rows, error) {
+	return testColumnsRows(s), nil
+}
+
+type testColumnsRows string
+
+func (testColumnsRows) Columns() []string         { return []string{"resource", "status", "reason"} }
+func (testColumnsRows) Close() error              { return nil }
+func (testColumnsRows) Next([]driver.Value) error { return io.EOF }
+
+func init() {
+	sql.Register("controlexecute_test", testColumnsDriver{})
+}
+
+func testColumnTypes(t *testing.T) []*sql.ColumnType {
+	db, err := sql.Open("controlexecute_test", "")
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	defer db.Close()
+	rows, err := db.Query("columns")
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	defer rows.Close()
+	colTypes, err := rows.ColumnTypes()
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	return colTypes
+}
+
+// testClient streams the same rows for every query, recording the queries executed
+type testClient struct {
+	db_common.Client
+	colTypes []*sql.ColumnType
+	rows     [][]interface{}
+	executed []string
+}
+
+func (c *testClient) ExecuteSync(context.Context, string, bool) (*queryresult.SyncQueryResult, error) {
+	return &queryresult.SyncQueryResult{}, nil
+}
+
+func (c *testClient) Execute(_ context.Context, query string, _ bool) (*queryresult.Result, error) {
+	c.executed = append(c.executed, query)
+	result := queryresult.NewQueryResult(c.colTypes)
+	go func() {
+		for _, row := range c.rows {
+			result.StreamRow(row)
+		}
+		result.Close()
+	}()
+	return result, nil
+}
+
+func newTestControl(name, sql string, args map[string]string) *modconfig.Control {
+	control := &modconfig.Control{
+		ShortName: name,
+		FullName:  "control." + name,
+		SQL:       utils.ToStringPointer(sql),
+		Args:      modconfig.NewQueryArgs(),
+		Params:    []*modconfig.ParamDef{{Name: "region", Default: utils.ToStringPointer("'us-east-1'")}},
+		Mod:       &modconfig.Mod{ShortName: "m1"},
+	}
+	for k, v := range args {
+		control.Args.Args[k] = v
+	}
+	return control
+}
+
+// newTestExecutionTree builds an execution tree for the given controls, registering their queries with the result cache
+func newTestExecutionTree(resultCache *QueryResultCache, controls ...*modconfig.Control) *ExecutionTree {
+	tree := &ExecutionTree{
+		workspace: &workspace.Workspace{},
+		progress:  NewControlProgressRenderer(len(controls)),
+	}
+	var items []modconfig.ModTreeItem
+	for _, control := range controls {
+		items = append(items, control)
+	}
+	tree.Root = NewRootResultGroup(tree, items...)
+	tree.groupSharedQueries(resultCache)
+	return tree
+}
+
+func newTestClient(t *testing.T) *testClient {
+	return &testClient{
+		colTypes: testColumnTypes(t),
+		rows: [][]interface{}{
+			{"r1", constants.ControlOk, "r1 is ok"},
+			{"r2", constants.ControlAlarm, "r2 is in alarm"},
+			{"r3", constants.ControlOk, "r3 is ok"},
+		},
+	}
+}
+
+func TestQueryResultCacheSharedResult(t *testing.T) {
+	tree := newTestExecutionTree(NewQueryResultCache(),
+		newTestControl("c1", "select $1", nil),
+		newTestControl("c2", "select $1", nil),
+		newTestControl("c3", "select $1", map[string]string{"region": "'eu-west-2'"}))
+
+	runs := tree.Root.ControlRuns
+	if runs[0].resultCacheKey == "" || runs[0].resultCacheKey != runs[1].resultCacheKey {
+		t.Errorf("Test: 'same sql and args' FAILED : expected keys to match, got %q and %q", runs[0].resultCacheKey, runs[1].resultCacheKey)
+	}
+	if runs[2].resultCacheKey == runs[0].resultCacheKey {
+		t.Errorf("Test: 'different args' FAILED : expected keys to differ, got %q", runs[2].resultCacheKey)
+	}
+
+	client := newTestClient(t)
+	failures := tree.Root.Execute(context.Background(), client)
+
+	// c2 uses the result of c1
+	if len(client.executed) != 2 {
+		t.Errorf("Test: 'shared result' FAILED : expected 2 queries to be executed, got %d", len(client.executed))
+	}
+	if runs[1].SharedResultFrom != "control.c1" {
+		t.Errorf("Test: 'shared result' FAILED : expected c2 to share the result of control.c1, got '%s'", runs[1].SharedResultFrom)
+	}
+	if count := tree.SharedResultCount(); count != 1 {
+		t.Errorf("Test: 'shared result count' FAILED : expected 1, got %d", count)
+	}
+	// each control has the rows and summary of the query result
+	expectedSummary := StatusSummary{Ok: 2, Alarm: 1}
+	for _, run := range runs {
+		if run.GetRunStatus() != ControlRunComplete {
+			t.Errorf("Test: '%s' FAILED : expected complete run, got status %d, error %v", run.ControlId, run.GetRunStatus(), run.GetError())
+			continue
+		}
+		if run.Summary != expectedSummary {
+			t.Errorf("Test: '%s' FAILED : expected summary %+v, got %+v", run.ControlId, expectedSummary, run.Summary)
+		}
+		if len(run.Rows) != 3 || run.Rows[1].Resource != "r2" || run.Rows[1].Status != constants.ControlAlarm || run.Rows[1].Control != run.Control {
+			t.Errorf("Test: '%s' FAILED : expected the rows of the query result, got %+v", run.ControlId, run.Rows)
+		}
+	}
+	expectedGroupSummary := StatusSummary{Ok: 6, Alarm: 3}
+	if tree.Root.Summary.Status != expectedGroupSummary {
+		t.Errorf("Test: 'group summary' FAILED : expected %+v, got %+v", expectedGroupSummary, tree.Root.Summary.Status)
+	}
+	if failures != 3 {
+		t.Errorf("Test: 'failures' FAILED : expected 3, got %d", failures)
+	}
+	// the result is released once the last control has used it
+	if len(tree.resultCache.entries) != 0 || len(tree.resultCache.consumers) != 0 {
+		t.Errorf("Test: 'release' FAILED : expected the cache to be empty, got %d entries", len(tree.resultCache.entries))
+	}
+}
+
+func TestQueryResultCacheSharedBetweenTrees(t *testing.T) {
+	// the trees of two check arguments share a cache - both trees are created before either is executed
+	resultCache := NewQueryResultCache()
+	tree1 := newTestExecutionTree(resultCache,
+		newTestControl("c1", "select $1", nil),
+		newTestControl("c2", "select $1", map[string]string{"region": "'eu-west-2'"}))
+	tree2 := newTestExecutionTree(resultCache,
+		newTestControl("c3", "select $1", nil),
+		newTestControl("c4", "select $1", map[string]string{"region": "'eu-west-2'"}),
+		newTestControl("c5", "select $1", map[string]string{"region": "'eu-west-3'"}))
+
+	client := newTestClient(t)
+	tree1.Root.Execute(context.Background(), client)
+
+	// the results of c1 and c2 are kept for tree2 - the result of c5 is only used once so is never cached
+	if len(resultCache.entries) != 2 {
+		t.Errorf("Test: 'kept for second tree' FAILED : expected 2 entries, got %d", len(resultCache.entries))
+	}
+
+	tree2.Root.Execute(context.Background(), client)
+
+	// c3 and c4 use the results of c1 and c2, so only c1, c2 and c5 execute their queries
+	if len(client.executed) != 3 {
+		t.Errorf("Test: 'shared result' FAILED : expected 3 queries to be executed, got %d", len(client.executed))
+	}
+	runs := tree2.Root.ControlRuns
+	if runs[0].SharedResultFrom != "control.c1" || runs[1].SharedResultFrom != "control.c2" || runs[2].SharedResultFrom != "" {
+		t.Errorf("Test: 'shared result' FAILED : expected c3, c4 and c5 to share the results of control.c1, control.c2 and no control, got '%s', '%s' and '%s'",
+			runs[0].SharedResultFrom, runs[1].SharedResultFrom, runs[2].SharedResultFrom)
+	}
+	if count := tree1.SharedResultCount(); count != 0 {
+		t.Errorf("Test: 'shared result count' FAILED : expected 0 for the first tree, got %d", count)
+	}
+	if count := tree2.SharedResultCount(); count != 2 {
+		t.Errorf("Test: 'shared result count' FAILED : expected 2 for the second tree, got %d", count)
+	}
+	// the results are released once the last control has used them
+	if len(resultCache.entries) != 0 || len(resultCache.consumers) != 0 {
+		t.Errorf("Test: 'release' FAILED : expected the cache to be empty, got %d entries", len(resultCache.entries))
+	}
+}
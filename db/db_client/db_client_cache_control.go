@@ -1,33 +1,99 @@
 package db_client
 
 import (
+	"context"
 	"fmt"
+	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/turbot/steampipe/constants"
+	"github.com/turbot/steampipe/db/db_common"
 )
 
 // CacheOn implements Client
 func (c *DbClient) CacheOn() error {
-	return c.executeCacheCommand(constants.CommandCacheOn)
+	return c.executeCacheCommand(constants.CommandCacheOn, nil)
 }
 
 // CacheOff implements Client
 func (c *DbClient) CacheOff() error {
-	return c.executeCacheCommand(constants.CommandCacheOff)
+	return c.executeCacheCommand(constants.CommandCacheOff, nil)
 }
 
 // CacheClear implements Client
 func (c *DbClient) CacheClear() error {
-	return c.executeCacheCommand(constants.CommandCacheClear)
+	return c.executeCacheCommand(constants.CommandCacheClear, nil)
 }
 
-func (c *DbClient) executeCacheCommand(controlCommand string) error {
+// CacheClearConnection implements Client
+// clear the cache for a connection, or for a single table of the connection if a table is specified
+func (c *DbClient) CacheClearConnection(connection, table string) error {
+	args := map[string]string{constants.CacheCommandConnectionColumn: connection}
+	if table != "" {
+		args[constants.CacheCommandTableColumn] = table
+	}
+	return c.executeCacheCommand(constants.CommandCacheClear, args)
+}
+
+// CacheSetTTL implements Client
+func (c *DbClient) CacheSetTTL(ttlSeconds int) error {
+	return c.executeCacheCommand(constants.CommandCacheTTL, map[string]string{
+		constants.CacheCommandTTLColumn: strconv.Itoa(ttlSeconds),
+	})
+}
+
+// CacheStatus implements Client
+// read the cache status for each connection from the command schema
+func (c *DbClient) CacheStatus(ctx context.Context) ([]*db_common.CacheStatus, error) {
+	rows, err := c.dbClient.QueryContext(ctx, fmt.Sprintf(
+		"select connection, entries, size, hits, misses from %s.%s order by connection",
+		constants.CommandSchema,
+		constants.CacheStatusTable,
+	))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []*db_common.CacheStatus
+	for rows.Next() {
+		status := &db_common.CacheStatus{}
+		if err := rows.Scan(&status.Connection, &status.Entries, &status.Size, &status.Hits, &status.Misses); err != nil {
+			return nil, err
+		}
+		res = append(res, status)
+	}
+	return res, rows.Err()
+}
+
+// insert a command into the cache command table
+// args is a map of column name to value, for commands which require additional arguments
+func (c *DbClient) executeCacheCommand(controlCommand string, args map[string]string) error {
+	columns := []string{constants.CacheCommandOperationColumn}
+	values := []interface{}{controlCommand}
+
+	// sort the columns to give a consistent statement
+	argColumns := make([]string, 0, len(args))
+	for column := range args {
+		argColumns = append(argColumns, column)
+	}
+	sort.Strings(argColumns)
+	for _, column := range argColumns {
+		columns = append(columns, db_common.PgEscapeName(column))
+		values = append(values, args[column])
+	}
+
+	placeholders := make([]string, len(values))
+	for i := range values {
+		placeholders[i] = fmt.Sprintf("$%d", i+1)
+	}
 	_, err := c.dbClient.Exec(fmt.Sprintf(
-		"insert into %s.%s (%s) values ('%s')",
+		"insert into %s.%s (%s) values (%s)",
 		constants.CommandSchema,
 		constants.CacheCommandTable,
-		constants.CacheCommandOperationColumn,
-		controlCommand,
-	))
+		strings.Join(columns, ","),
+		strings.Join(placeholders, ","),
+	), values...)
 	return err
 }
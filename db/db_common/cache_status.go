@@ -0,0 +1,20 @@
+package db_common
+
+// CacheStatus :: the query cache status of a connection, as reported by the FDW
+type CacheStatus struct {
+	Connection string
+	Entries    int
+	// the size of the cached data in bytes
+	Size   int64
+	Hits   int
+	Misses int
+}
+
+// HitRate returns the percentage of cache lookups which were hits
+func (s *CacheStatus) HitRate() float64 {
+	total := s.Hits + s.Misses
+	if total == 0 {
+		return 0
+	}
+	return 100 * float64(s.Hits) / float64(total)
+}
@@ -27,6 +27,9 @@ type Client interface {
 	CacheOn() error
 	CacheOff() error
 	CacheClear() error
+	CacheClearConnection(connection, table string) error
+	CacheSetTTL(ttlSeconds int) error
+	CacheStatus(ctx context.Context) ([]*CacheStatus, error)
 
 	SetEnsureSessionDataFunc(EnsureSessionStateCallback)
 
@@ -19,6 +19,16 @@ import (
 // TagColumn is the tag used to specify the column name and type in the introspection tables
 const TagColumn = "column"
 
+// the introspection table for each resource type
+var introspectionTableForResourceType = map[string]string{
+	modconfig.BlockTypeMod:          constants.IntrospectionTableMod,
+	modconfig.BlockTypeQuery:        constants.IntrospectionTableQuery,
+	modconfig.BlockTypeControl:      constants.IntrospectionTableControl,
+	modconfig.BlockTypeBenchmark:    constants.IntrospectionTableBenchmark,
+	modconfig.BlockTypeVariable:     constants.IntrospectionTableVariable,
+	modconfig.ResourceTypeReference: constants.IntrospectionTableReference,
+}
+
 func UpdateIntrospectionTables(workspaceResources *modconfig.WorkspaceResourceMaps, client Client) error {
 	utils.LogTime("db.UpdateIntrospectionTables start")
 	defer utils.LogTime("db.UpdateIntrospectionTables end")
@@ -38,6 +48,42 @@ func UpdateIntrospectionTables(workspaceResources *modconfig.WorkspaceResourceMa
 	return nil
 }
 
+// UpdateChangedIntrospectionTables replaces the introspection table rows for only those resources which have changed
+// (or which reference a changed resource)
+// if the rows for a changed resource cannot be identified (i.e. it has no metadata), all rows are replaced
+func UpdateChangedIntrospectionTables(diff *modconfig.WorkspaceResourceMapsDiff, client Client) error {
+	utils.LogTime("db.UpdateChangedIntrospectionTables start")
+	defer utils.LogTime("db.UpdateChangedIntrospectionTables end")
+
+	updates := newIntrospectionRowUpdates()
+	for _, resourceType := range modconfig.ResourceTypes {
+		prevResources, currentResources := diff.Prev.ResourceMap(resourceType), diff.Current.ResourceMap(resourceType)
+		for name := range diff.ChangedNames(resourceType) {
+			// either may be nil, if the resource has been added or deleted
+			prev, current := prevResources[name], currentResources[name]
+			// do not expose the values of sensitive variables
+			if variable, ok := current.(*modconfig.Variable); ok {
+				current = variable.Redacted()
+			}
+			updates.add(prev, current, introspectionTableForResourceType[resourceType])
+		}
+	}
+
+	if updates.missingMetadata {
+		return UpdateIntrospectionTables(diff.Current, client)
+	}
+	sql := updates.sql()
+	if sql == "" {
+		return nil
+	}
+	// execute the query, passing 'true' to disable the spinner
+	_, err := client.ExecuteSync(context.Background(), sql, true)
+	if err != nil {
+		return fmt.Errorf("failed to update introspection tables: %v", err)
+	}
+	return nil
+}
+
 func CreateIntrospectionTables(ctx context.Context, workspaceResources *modconfig.WorkspaceResourceMaps, client Client) error {
 	utils.LogTime("db.CreateIntrospectionTables start")
 	defer utils.LogTime("db.CreateIntrospectionTables end")
@@ -115,7 +161,8 @@ func getTableInsertSql(workspaceResources *modconfig.WorkspaceResourceMaps) stri
 	for _, variable := range workspaceResources.Variables {
 		if _, added := resourcesAdded[variable.Name()]; !added {
 			resourcesAdded[variable.Name()] = true
-			insertSql = append(insertSql, getTableInsertSqlForResource(variable, constants.IntrospectionTableVariable))
+			// do not expose the values of sensitive variables
+			insertSql = append(insertSql, getTableInsertSqlForResource(variable.Redacted(), constants.IntrospectionTableVariable))
 		}
 	}
 	for _, reference := range workspaceResources.References {
@@ -249,3 +296,59 @@ func formatIntrospectionTableValue(item interface{}, columnTag *ColumnTag) (stri
 		return PgEscapeString(typeHelpers.ToString(item)), nil
 	}
 }
+
+// introspectionRowUpdates builds the sql to replace the introspection table rows of changed resources
+// rows are identified by the resource name and mod name from the resource metadata
+type introspectionRowUpdates struct {
+	deleteSql []string
+	insertSql []string
+	// the resources may be added more than once (keyed by long and short name) - avoid dupes
+	deleted  map[string]bool
+	inserted map[string]bool
+	// set if a changed resource has no metadata, meaning its rows cannot be identified
+	missingMetadata bool
+}
+
+func newIntrospectionRowUpdates() *introspectionRowUpdates {
+	return &introspectionRowUpdates{
+		deleted:  make(map[string]bool),
+		inserted: make(map[string]bool),
+	}
+}
+
+// add the sql to delete the row for the previous version of a resource and insert the row for the current version
+// either prev or current may be nil, if the resource has been added or deleted
+func (u *introspectionRowUpdates) add(prev, current modconfig.ResourceWithMetadata, tableName string) {
+	if prev != nil {
+		metadata := prev.GetMetadata()
+		if metadata == nil {
+			u.missingMetadata = true
+			return
+		}
+		key := fmt.Sprintf("%s.%s.%s", tableName, metadata.ModName, metadata.ResourceName)
+		if !u.deleted[key] {
+			u.deleted[key] = true
+			u.deleteSql = append(u.deleteSql, fmt.Sprintf("delete from %s where resource_name = %s and mod_name = %s;",
+				tableName,
+				PgEscapeString(metadata.ResourceName),
+				PgEscapeString(metadata.ModName)))
+		}
+	}
+	if current != nil {
+		metadata := current.GetMetadata()
+		if metadata == nil {
+			u.missingMetadata = true
+			return
+		}
+		key := fmt.Sprintf("%s.%s.%s", tableName, metadata.ModName, metadata.ResourceName)
+		if !u.inserted[key] {
+			u.inserted[key] = true
+			u.insertSql = append(u.insertSql, getTableInsertSqlForResource(current, tableName))
+		}
+	}
+}
+
+// all deletions are made before insertions, so a row inserted for one resource is never deleted for another
+func (u *introspectionRowUpdates) sql() string {
+	return strings.Join(append(u.deleteSql, u.insertSql...), "\n")
+}
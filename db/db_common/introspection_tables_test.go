@@ -0,0 +1,118 @@
+package db_common
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/turbot/steampipe/query/queryresult"
+	"github.com/turbot/steampipe/steampipeconfig/modconfig"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// testClient records the sql executed by ExecuteSync
+// all other Client functions panic
+type testClient struct {
+	Client
+	sql []string
+}
+
+func (c *testClient) ExecuteSync(_ context.Context, query string, _ bool) (*queryresult.SyncQueryResult, error) {
+	c.sql = append(c.sql, query)
+	return &queryresult.SyncQueryResult{}, nil
+}
+
+// build resource maps containing query q1 and control c1 with the given sql,
+// control c2 which runs q1 (so changes when q1 changes) and a sensitive variable v1 with the given value
+// each resource is keyed by both its short and long name
+func testResourceMaps(querySql, controlSql, variableValue string) *modconfig.WorkspaceResourceMaps {
+	mod := &modconfig.Mod{ShortName: "m1"}
+	metadata := func(name string) *modconfig.ResourceMetadata {
+		return &modconfig.ResourceMetadata{ResourceName: name, ModName: "m1"}
+	}
+
+	m := modconfig.NewWorkspaceResourceMaps()
+	q1 := &modconfig.Query{ShortName: "q1", FullName: "m1.query.q1", SQL: &querySql, Mod: mod}
+	q1.SetMetadata(metadata("q1"))
+	c1 := &modconfig.Control{ShortName: "c1", FullName: "m1.control.c1", SQL: &controlSql, Mod: mod}
+	c1.SetMetadata(metadata("c1"))
+	c2 := &modconfig.Control{ShortName: "c2", FullName: "m1.control.c2", Query: q1, Mod: mod}
+	c2.SetMetadata(metadata("c2"))
+	v1 := &modconfig.Variable{ShortName: "v1", FullName: "m1.var.v1", Type: cty.String, Sensitive: true, Default: cty.StringVal(variableValue), Value: cty.StringVal(variableValue)}
+	v1.SetMetadata(metadata("v1"))
+
+	m.Queries["query.q1"], m.Queries["m1.query.q1"] = q1, q1
+	m.Controls["control.c1"], m.Controls["m1.control.c1"] = c1, c1
+	m.Controls["control.c2"], m.Controls["m1.control.c2"] = c2, c2
+	m.Variables["var.v1"], m.Variables["m1.var.v1"] = v1, v1
+	return m
+}
+
+func TestUpdateChangedIntrospectionTables(t *testing.T) {
+	prev := testResourceMaps("select 1", "select 'ok' as status", "secret_1")
+	current := testResourceMaps("select 2", "select 'ok' as status", "secret_2")
+	client := &testClient{}
+
+	if err := UpdateChangedIntrospectionTables(prev.Diff(current), client); err != nil {
+		t.Fatalf("Test: 'changed query and variable' FAILED with unexpected error: %v", err)
+	}
+	if len(client.sql) != 1 {
+		t.Fatalf("Test: 'changed query and variable' FAILED : expected 1 statement to be executed, got %d", len(client.sql))
+	}
+	statements := strings.Split(client.sql[0], "\n")
+
+	// the rows of the query, the control which runs it and the variable are replaced
+	// - each exactly once, even though the resources are keyed by short and long name
+	changedRows := map[string]string{
+		"steampipe_query":    "q1",
+		"steampipe_control":  "c2",
+		"steampipe_variable": "v1",
+	}
+	for table, resourceName := range changedRows {
+		expected := fmt.Sprintf("delete from %s where resource_name = %s and mod_name = %s;", table, PgEscapeString(resourceName), PgEscapeString("m1"))
+		if count := countStatements(statements, expected); count != 1 {
+			t.Errorf("Test: 'changed query and variable' FAILED : expected statement %q once, got %d", expected, count)
+		}
+		if count := countStatements(statements, "insert into "+table+" "); count != 1 {
+			t.Errorf("Test: 'changed query and variable' FAILED : expected 1 insert into %s, got %d", table, count)
+		}
+	}
+	// the unchanged control is not updated
+	if count := countStatements(statements, PgEscapeString("c1")); count != 0 {
+		t.Errorf("Test: 'changed query and variable' FAILED : expected no statements for control c1, got %d", count)
+	}
+	// all deletions are made before insertions
+	if !strings.HasPrefix(statements[0], "delete") || !strings.HasPrefix(statements[len(statements)-1], "insert") {
+		t.Errorf("Test: 'changed query and variable' FAILED : expected deletions before insertions, got:\n%s", client.sql[0])
+	}
+	// the value of the sensitive variable is not exposed
+	if strings.Contains(client.sql[0], "secret_2") {
+		t.Errorf("Test: 'changed query and variable' FAILED : sensitive variable value was written to the introspection table")
+	}
+}
+
+func TestUpdateChangedIntrospectionTablesMissingMetadata(t *testing.T) {
+	prev := testResourceMaps("select 1", "select 'ok' as status", "secret_1")
+	current := testResourceMaps("select 2", "select 'ok' as status", "secret_1")
+	prev.Queries["query.q1"].SetMetadata(nil)
+	client := &testClient{}
+
+	if err := UpdateChangedIntrospectionTables(prev.Diff(current), client); err != nil {
+		t.Fatalf("Test: 'missing metadata' FAILED with unexpected error: %v", err)
+	}
+	// the rows cannot be identified, so all tables are cleared and repopulated
+	if len(client.sql) != 1 || !strings.HasPrefix(client.sql[0], getClearTablesSql()) {
+		t.Errorf("Test: 'missing metadata' FAILED : expected all introspection tables to be replaced, got:\n%s", strings.Join(client.sql, "\n"))
+	}
+}
+
+func countStatements(statements []string, substr string) int {
+	count := 0
+	for _, s := range statements {
+		if strings.Contains(s, substr) {
+			count++
+		}
+	}
+	return count
+}
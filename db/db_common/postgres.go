@@ -5,8 +5,9 @@ import (
 	"strings"
 )
 
+// PgEscapeName quotes a name as a postgres identifier, doubling any double quotes it contains
 func PgEscapeName(name string) string {
-	return fmt.Sprintf(`"%s"`, name)
+	return fmt.Sprintf(`"%s"`, strings.ReplaceAll(name, `"`, `""`))
 }
 
 // PgEscapeString escapes strings which are to be inserted
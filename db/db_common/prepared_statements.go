@@ -56,7 +56,7 @@ func GetPreparedStatementsSQL(resourceMaps *modconfig.WorkspaceResourceMaps) map
 		// remove trailing semicolons from sql as this breaks the prepare statement
 		rawSql := strings.TrimRight(strings.TrimSpace(typehelpers.SafeString(query.SQL)), ";")
 		preparedStatementName := query.GetPreparedStatementName()
-		sqlMap[query.FullName] = fmt.Sprintf("PREPARE %s AS (\n%s\n)", preparedStatementName, rawSql)
+		sqlMap[query.FullName] = fmt.Sprintf("PREPARE %s%s AS (\n%s\n)", preparedStatementName, getPreparedStatementParamTypes(query.Params), rawSql)
 	}
 
 	for _, control := range resourceMaps.Controls {
@@ -72,13 +72,29 @@ func GetPreparedStatementsSQL(resourceMaps *modconfig.WorkspaceResourceMaps) map
 		// remove trailing semicolons from sql as this breaks the prepare statement
 		rawSql := strings.TrimRight(strings.TrimSpace(typehelpers.SafeString(control.SQL)), ";")
 		preparedStatementName := control.GetPreparedStatementName()
-		sqlMap[control.FullName] = fmt.Sprintf("PREPARE %s AS (\n%s\n)", preparedStatementName, rawSql)
+		sqlMap[control.FullName] = fmt.Sprintf("PREPARE %s%s AS (\n%s\n)", preparedStatementName, getPreparedStatementParamTypes(control.Params), rawSql)
 	}
 
 	return sqlMap
 
 }
 
+// if any of the params declare a type, return the list of postgres types used to bind the params,
+// so that the arg values are converted to the correct type rather than being inferred by postgres
+// params with no type are passed as 'unknown', meaning their type is still inferred
+func getPreparedStatementParamTypes(params []*modconfig.ParamDef) string {
+	var pgTypes []string
+	typed := false
+	for _, param := range params {
+		pgTypes = append(pgTypes, param.PgType())
+		typed = typed || param.Type != ""
+	}
+	if !typed {
+		return ""
+	}
+	return fmt.Sprintf(" (%s)", strings.Join(pgTypes, ","))
+}
+
 // UpdatePreparedStatements first attempts to deallocate all prepared statements in workspace, then recreates them
 func UpdatePreparedStatements(ctx context.Context, prevResourceMaps, currentResourceMaps *modconfig.WorkspaceResourceMaps, client Client) error {
 	log.Printf("[TRACE] UpdatePreparedStatements")
@@ -118,3 +134,51 @@ func UpdatePreparedStatements(ctx context.Context, prevResourceMaps, currentReso
 	return CreatePreparedStatements(ctx, currentResourceMaps, client)
 
 }
+
+// UpdateChangedPreparedStatements deallocates and recreates the prepared statements for only those queries and controls
+// which have changed (or which reference a changed resource)
+func UpdateChangedPreparedStatements(ctx context.Context, diff *modconfig.WorkspaceResourceMapsDiff, client Client) error {
+	log.Printf("[TRACE] UpdateChangedPreparedStatements")
+
+	utils.LogTime("db.UpdateChangedPreparedStatements start")
+	defer utils.LogTime("db.UpdateChangedPreparedStatements end")
+
+	// the diff may contain the same resource keyed by long and short name - avoid dupes
+	deallocated := make(map[string]bool)
+	var sql []string
+	for name := range diff.Queries {
+		if query, ok := diff.Prev.Queries[name]; ok && !deallocated[query.GetPreparedStatementName()] {
+			deallocated[query.GetPreparedStatementName()] = true
+			sql = append(sql, fmt.Sprintf("DEALLOCATE %s;", query.GetPreparedStatementName()))
+		}
+	}
+	for name := range diff.Controls {
+		// prepared statements are only created for controls with inline SQL
+		if control, ok := diff.Prev.Controls[name]; ok && control.SQL != nil && !deallocated[control.GetPreparedStatementName()] {
+			deallocated[control.GetPreparedStatementName()] = true
+			sql = append(sql, fmt.Sprintf("DEALLOCATE %s;", control.GetPreparedStatementName()))
+		}
+	}
+
+	if len(sql) > 0 {
+		// execute the query, passing 'true' to disable the spinner
+		if _, err := client.ExecuteSync(ctx, strings.Join(sql, "\n"), true); err != nil {
+			log.Printf("[TRACE] failed to update prepared statements - deallocate returned error %v", err)
+			return err
+		}
+	}
+
+	// now recreate the prepared statements for the changed resources which still exist
+	changedResourceMaps := modconfig.NewWorkspaceResourceMaps()
+	for name := range diff.Queries {
+		if query, ok := diff.Current.Queries[name]; ok {
+			changedResourceMaps.Queries[name] = query
+		}
+	}
+	for name := range diff.Controls {
+		if control, ok := diff.Current.Controls[name]; ok {
+			changedResourceMaps.Controls[name] = control
+		}
+	}
+	return CreatePreparedStatements(ctx, changedResourceMaps, client)
+}
@@ -0,0 +1,90 @@
+package db_common
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+type updateChangedPreparedStatementsTest struct {
+	prevQuerySql      string
+	currentQuerySql   string
+	prevControlSql    string
+	currentControlSql string
+	// the short names of the resources whose prepared statements are expected to be recreated
+	expected []string
+}
+
+var testCasesUpdateChangedPreparedStatements = map[string]updateChangedPreparedStatementsTest{
+	"no changes": {
+		prevQuerySql:      "select 1",
+		currentQuerySql:   "select 1",
+		prevControlSql:    "select 'ok' as status",
+		currentControlSql: "select 'ok' as status",
+	},
+	"query changed": {
+		prevQuerySql:      "select 1",
+		currentQuerySql:   "select 2",
+		prevControlSql:    "select 'ok' as status",
+		currentControlSql: "select 'ok' as status",
+		expected:          []string{"q1"},
+	},
+	"query and control changed": {
+		prevQuerySql:      "select 1",
+		currentQuerySql:   "select 2;",
+		prevControlSql:    "select 'ok' as status",
+		currentControlSql: "select 'alarm' as status",
+		expected:          []string{"c1", "q1"},
+	},
+}
+
+func TestUpdateChangedPreparedStatements(t *testing.T) {
+	for name, test := range testCasesUpdateChangedPreparedStatements {
+		prev := testResourceMaps(test.prevQuerySql, test.prevControlSql, "secret_1")
+		current := testResourceMaps(test.currentQuerySql, test.currentControlSql, "secret_1")
+		client := &testClient{}
+
+		if err := UpdateChangedPreparedStatements(context.Background(), prev.Diff(current), client); err != nil {
+			t.Errorf("Test: '%s' FAILED with unexpected error: %v", name, err)
+			continue
+		}
+		if len(test.expected) == 0 {
+			if len(client.sql) != 0 {
+				t.Errorf("Test: '%s' FAILED : expected no sql to be executed, got:\n%s", name, strings.Join(client.sql, "\n"))
+			}
+			continue
+		}
+		// the statements are first deallocated, then created
+		if len(client.sql) != 2 {
+			t.Errorf("Test: '%s' FAILED : expected 2 statements to be executed, got %d", name, len(client.sql))
+			continue
+		}
+		deallocateSql, prepareSql := client.sql[0], client.sql[1]
+		// control c2 runs q1, so has no prepared statement of its own - the deallocate and prepare for q1 cover it
+		if count := strings.Count(deallocateSql, "DEALLOCATE"); count != len(test.expected) {
+			t.Errorf("Test: '%s' FAILED : expected %d deallocations, got:\n%s", name, len(test.expected), deallocateSql)
+		}
+		if count := strings.Count(prepareSql, "PREPARE"); count != len(test.expected) {
+			t.Errorf("Test: '%s' FAILED : expected %d prepared statements, got:\n%s", name, len(test.expected), prepareSql)
+		}
+		for _, resourceName := range test.expected {
+			var statementName string
+			if q, ok := current.Queries["query."+resourceName]; ok {
+				statementName = q.GetPreparedStatementName()
+			} else {
+				statementName = current.Controls["control."+resourceName].GetPreparedStatementName()
+			}
+			if !strings.Contains(deallocateSql, fmt.Sprintf("DEALLOCATE %s;", statementName)) {
+				t.Errorf("Test: '%s' FAILED : expected %s to be deallocated, got:\n%s", name, statementName, deallocateSql)
+			}
+			if !strings.Contains(prepareSql, fmt.Sprintf("PREPARE %s AS", statementName)) {
+				t.Errorf("Test: '%s' FAILED : expected %s to be prepared, got:\n%s", name, statementName, prepareSql)
+			}
+		}
+		// trailing semicolons are removed from the prepared sql
+		if strings.Contains(prepareSql, "select 2;") {
+			t.Errorf("Test: '%s' FAILED : expected trailing semicolon to be removed, got:\n%s", name, prepareSql)
+		}
+	}
+}
@@ -118,6 +118,21 @@ func (c *LocalDbClient) CacheClear() error {
 	return c.client.CacheClear()
 }
 
+// CacheClearConnection implements Client
+func (c *LocalDbClient) CacheClearConnection(connection, table string) error {
+	return c.client.CacheClearConnection(connection, table)
+}
+
+// CacheSetTTL implements Client
+func (c *LocalDbClient) CacheSetTTL(ttlSeconds int) error {
+	return c.client.CacheSetTTL(ttlSeconds)
+}
+
+// CacheStatus implements Client
+func (c *LocalDbClient) CacheStatus(ctx context.Context) ([]*db_common.CacheStatus, error) {
+	return c.client.CacheStatus(ctx)
+}
+
 // GetCurrentSearchPath implements Client
 func (c *LocalDbClient) GetCurrentSearchPath() ([]string, error) {
 	// NOTE: create a new client to do this, so we respond to any recent changes in user search path
@@ -294,6 +309,12 @@ func (c *LocalDbClient) refreshConnections() *db_common.RefreshConnectionResult
 		connectionQueries = getSchemaQueries(validatedUpdates, validationFailures)
 		// add comments queries for validated connections
 		connectionQueries = append(connectionQueries, getCommentQueries(validatedPlugins)...)
+		// store the key columns of the validated connections in the connection state
+		for _, p := range validatedPlugins {
+			if connectionData, ok := updates.RequiredConnections[p.ConnectionName]; ok {
+				connectionData.KeyColumns = p.KeyColumns()
+			}
+		}
 	}
 
 	for c := range updates.Delete {
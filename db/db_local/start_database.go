@@ -16,6 +16,7 @@ import (
 	"github.com/spf13/viper"
 	"github.com/turbot/go-kit/helpers"
 	"github.com/turbot/steampipe/constants"
+	"github.com/turbot/steampipe/db/db_common"
 	"github.com/turbot/steampipe/utils"
 )
 
@@ -419,10 +420,7 @@ func ensureSteampipeServer(databaseName string) error {
 // create the command schema and grant insert permission
 func ensureCommandSchema(databaseName string) error {
 	commandSchemaStatements := updateConnectionQuery(constants.CommandSchema, constants.CommandSchema)
-	commandSchemaStatements = append(
-		commandSchemaStatements,
-		fmt.Sprintf("grant insert on %s.%s to steampipe_users;", constants.CommandSchema, constants.CacheCommandTable),
-	)
+	commandSchemaStatements = append(commandSchemaStatements, cacheCommandStatements()...)
 	rootClient, err := createLocalDbClient(&CreateDbOptions{DatabaseName: databaseName, Username: constants.DatabaseSuperUser})
 	if err != nil {
 		return err
@@ -437,6 +435,24 @@ func ensureCommandSchema(databaseName string) error {
 	return err
 }
 
+// add the columns used to pass the connection, table and ttl of a cache command to the FDW,
+// create the table the FDW reports the cache status of each connection in,
+// and grant the required permissions
+func cacheCommandStatements() []string {
+	commandTable := fmt.Sprintf("%s.%s", constants.CommandSchema, constants.CacheCommandTable)
+	statusTable := fmt.Sprintf("%s.%s", constants.CommandSchema, constants.CacheStatusTable)
+	return []string{
+		fmt.Sprintf(`alter foreign table %s add column if not exists %s text, add column if not exists %s text, add column if not exists %s integer;`,
+			commandTable,
+			db_common.PgEscapeName(constants.CacheCommandConnectionColumn),
+			db_common.PgEscapeName(constants.CacheCommandTableColumn),
+			db_common.PgEscapeName(constants.CacheCommandTTLColumn)),
+		fmt.Sprintf(`create foreign table if not exists %s (connection text, entries integer, size bigint, hits integer, misses integer) server steampipe;`, statusTable),
+		fmt.Sprintf("grant insert on %s to steampipe_users;", commandTable),
+		fmt.Sprintf("grant select on %s to steampipe_users;", statusTable),
+	}
+}
+
 // ensures that the 'steampipe_users' role has permissions to work with temporary tables
 // this is done during database installation, but we need to migrate current installations
 func ensureTempTablePermissions(databaseName string) error {
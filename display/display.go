@@ -5,6 +5,7 @@ import (
 	"encoding/csv"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 	"unicode/utf8"
@@ -28,6 +29,8 @@ func ShowOutput(result *queryresult.Result) {
 		displayCSV(result)
 	} else if output == constants.ArgLine {
 		displayLine(result)
+	} else if formatter, ok := rowFormatters[output]; ok {
+		displayFormatted(result, formatter)
 	} else {
 		// default
 		displayTable(result)
@@ -174,36 +177,69 @@ func getTerminalColumnsRequiredForString(str string) int {
 }
 
 func displayJSON(result *queryresult.Result) {
+	if err := writeJSON(os.Stdout, result); err != nil {
+		utils.ShowError(err)
+		return
+	}
+	fmt.Println()
+}
+
+// write the result to w as a JSON array of records
+func writeJSON(w io.Writer, result *queryresult.Result) error {
 	var jsonOutput []map[string]interface{}
+	// if the result is large (or streaming is enabled), stream the records rather than buffering them
+	var streamWriter *jsonStreamWriter
+	var writeErr error
 
 	// define function to add each row to the JSON output
 	rowFunc := func(row []interface{}, result *queryresult.Result) {
-		record := map[string]interface{}{}
-		for idx, colType := range result.ColTypes {
-			value, _ := ParseJSONOutputColumnValue(row[idx], colType)
-			record[colType.Name()] = value
+		record := rowAsRecord(row, result.ColTypes)
+		if streamWriter == nil && shouldStream(len(jsonOutput)+1) {
+			// switch to streaming - write out the buffered records
+			streamWriter = &jsonStreamWriter{w: w}
+			for _, r := range jsonOutput {
+				if writeErr == nil {
+					writeErr = streamWriter.write(r)
+				}
+			}
+			jsonOutput = nil
+		}
+		if streamWriter == nil {
+			jsonOutput = append(jsonOutput, record)
+		} else if writeErr == nil {
+			writeErr = streamWriter.write(record)
 		}
-		jsonOutput = append(jsonOutput, record)
 	}
 
 	// call this function for each row
 	if err := iterateResults(result, rowFunc); err != nil {
-		utils.ShowError(err)
-		return
+		return err
 	}
-	// display the JSON
-	encoder := json.NewEncoder(os.Stdout)
+	if streamWriter != nil {
+		if writeErr != nil {
+			return writeErr
+		}
+		return streamWriter.close()
+	}
+	// write the JSON
+	encoder := json.NewEncoder(w)
 	encoder.SetIndent("", " ")
 	encoder.SetEscapeHTML(false)
 	if err := encoder.Encode(jsonOutput); err != nil {
-		fmt.Print("Error displaying result as JSON", err)
-		return
+		return fmt.Errorf("error displaying result as JSON: %v", err)
 	}
-	fmt.Println()
+	return nil
 }
 
 func displayCSV(result *queryresult.Result) {
-	csvWriter := csv.NewWriter(os.Stdout)
+	if err := writeCSV(os.Stdout, result); err != nil {
+		utils.ShowError(err)
+	}
+}
+
+// write the result to w as csv, using the configured separator and header settings
+func writeCSV(w io.Writer, result *queryresult.Result) error {
+	csvWriter := csv.NewWriter(w)
 	csvWriter.Comma = []rune(cmdconfig.Viper().GetString(constants.ArgSeparator))[0]
 
 	if cmdconfig.Viper().GetBool(constants.ArgHeader) {
@@ -219,13 +255,20 @@ func displayCSV(result *queryresult.Result) {
 
 	// call this function for each row
 	if err := iterateResults(result, rowFunc); err != nil {
-		utils.ShowError(err)
-		return
+		return err
 	}
 
 	csvWriter.Flush()
 	if csvWriter.Error() != nil {
-		utils.ShowErrorWithMessage(csvWriter.Error(), "unable to print csv")
+		return fmt.Errorf("unable to print csv: %v", csvWriter.Error())
+	}
+	return nil
+}
+
+// display the result using a RowFormatter, streaming each row to stdout as it is read
+func displayFormatted(result *queryresult.Result, formatter RowFormatter) {
+	if err := writeFormattedResult(os.Stdout, result, formatter); err != nil {
+		utils.ShowError(err)
 	}
 }
 
@@ -256,24 +299,54 @@ func displayTable(result *queryresult.Result) {
 		t.AppendHeader(headers)
 	}
 
+	// buffer the rows - if the result is large (or streaming is enabled),
+	// switch to rendering the table page by page, so we do not hold all rows in memory
+	var rows [][]string
+	var pageWriter *tablePageWriter
+
 	// define a function to execute for each row
 	rowFunc := func(row []interface{}, result *queryresult.Result) {
 		rowAsString, _ := ColumnValuesAsString(row, result.ColTypes)
-		rowObj := table.Row{}
-		for _, col := range rowAsString {
-			rowObj = append(rowObj, col)
+		if pageWriter == nil && shouldStream(len(rows)+1) {
+			// switch to streaming - write out the buffered rows
+			pageWriter = newTablePageWriter(os.Stdout, result.ColTypes)
+			for _, r := range rows {
+				pageWriter.add(r)
+			}
+			rows = nil
+		}
+		if pageWriter != nil {
+			pageWriter.add(rowAsString)
+		} else {
+			rows = append(rows, rowAsString)
 		}
-		t.AppendRow(rowObj)
 	}
 
 	// iterate each row, adding each to the table
 	err := iterateResults(result, rowFunc)
+	if pageWriter != nil {
+		pageWriter.flush()
+	}
 	if err != nil {
 		// display the error
 		fmt.Println()
 		utils.ShowError(err)
 		fmt.Println()
 	}
+	if pageWriter != nil {
+		// the table has already been written
+		if cmdconfig.Viper().GetBool(constants.ArgTimer) {
+			fmt.Printf("\nTime: %v\n", <-result.Duration)
+		}
+		return
+	}
+	for _, row := range rows {
+		rowObj := table.Row{}
+		for _, col := range row {
+			rowObj = append(rowObj, col)
+		}
+		t.AppendRow(rowObj)
+	}
 	// if timer is turned on
 	if cmdconfig.Viper().GetBool(constants.ArgTimer) {
 		// put in the time information in the buffer
@@ -0,0 +1,53 @@
+package display
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/jedib0t/go-pretty/v6/list"
+	"github.com/turbot/steampipe/constants"
+	"github.com/turbot/steampipe/query/queryplan"
+	"github.com/turbot/steampipe/utils"
+)
+
+// ShowQueryPlan displays a tree summarising the foreign table scans of a query plan, grouped by connection,
+// with the rows returned and time taken by each, and the filter which postgres rechecks against the returned rows
+func ShowQueryPlan(plan *queryplan.QueryPlan) {
+	fmt.Printf("Planning time: %s, execution time: %s\n", formatMilliseconds(plan.PlanningTime), formatMilliseconds(plan.ExecutionTime))
+
+	summaries := plan.ConnectionSummaries()
+	if len(summaries) == 0 {
+		fmt.Println("The query did not scan any foreign tables")
+		return
+	}
+
+	l := list.NewWriter()
+	l.SetStyle(list.StyleConnectedLight)
+	l.SetOutputMirror(os.Stdout)
+	for _, summary := range summaries {
+		l.AppendItem(fmt.Sprintf("%s: %d %s in %s",
+			constants.Bold(summary.Connection),
+			summary.Rows,
+			utils.Pluralize("row", summary.Rows),
+			formatMilliseconds(summary.Time)))
+		l.Indent()
+		for _, scan := range summary.Scans {
+			table := scan.Table
+			if scan.Alias != "" && scan.Alias != scan.Table {
+				table = fmt.Sprintf("%s (%s)", scan.Table, scan.Alias)
+			}
+			l.AppendItem(fmt.Sprintf("%s: %d %s in %s", table, scan.Rows, utils.Pluralize("row", scan.Rows), formatMilliseconds(scan.Time)))
+			if scan.Filter != "" {
+				l.Indent()
+				l.AppendItem(fmt.Sprintf("filter (rechecked): %s", scan.Filter))
+				l.UnIndent()
+			}
+		}
+		l.UnIndent()
+	}
+	l.Render()
+}
+
+func formatMilliseconds(ms float64) string {
+	return fmt.Sprintf("%.3fms", ms)
+}
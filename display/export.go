@@ -0,0 +1,96 @@
+package display
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/turbot/steampipe/constants"
+	"github.com/turbot/steampipe/query/queryresult"
+)
+
+type exportFunc func(w io.Writer, result *queryresult.Result) error
+
+// return the function used to write a result in the given export format
+// all formats with a RowFormatter may be exported, as well as csv and json
+func getExportFunc(format string) (exportFunc, bool) {
+	switch format {
+	case constants.ArgCSV:
+		return writeCSV, true
+	case constants.ArgJSON:
+		return writeJSON, true
+	}
+	formatter, ok := rowFormatters[format]
+	if !ok {
+		return nil, false
+	}
+	return func(w io.Writer, result *queryresult.Result) error {
+		return writeFormattedResult(w, result, formatter)
+	}, true
+}
+
+// CanCombineExports returns whether the results of several queries may be written to the same file in the given format
+// - json exports are a single document, so cannot be combined
+func CanCombineExports(format string) bool {
+	switch format {
+	case constants.ArgCSV, constants.ArgNDJSON, constants.ArgMarkdown, constants.ArgHTML, constants.ArgYAML:
+		return true
+	}
+	return false
+}
+
+// ValidateExportFormat returns an error if the given format is not a supported export format
+func ValidateExportFormat(format string) error {
+	if _, ok := getExportFunc(format); !ok {
+		return fmt.Errorf("invalid export format '%s' - must be one of csv,json,markdown,html,yaml,ndjson", format)
+	}
+	return nil
+}
+
+// InferExportFormatFromFileName returns the export format implied by the extension of the given file name
+func InferExportFormatFromFileName(fileName string) (string, error) {
+	switch strings.ToLower(filepath.Ext(fileName)) {
+	case ".csv":
+		return constants.ArgCSV, nil
+	case ".json":
+		return constants.ArgJSON, nil
+	case ".md", ".markdown":
+		return constants.ArgMarkdown, nil
+	case ".html", ".htm":
+		return constants.ArgHTML, nil
+	case ".yaml", ".yml":
+		return constants.ArgYAML, nil
+	case ".ndjson", ".jsonl":
+		return constants.ArgNDJSON, nil
+	default:
+		return "", fmt.Errorf("could not infer valid export format from filename '%s'", fileName)
+	}
+}
+
+// ExportResult writes the result to the given file in the given format
+// if appendToFile is set, the result is appended to any existing file contents,
+// otherwise the file is overwritten
+// the result is always read in full, even if the export fails
+func ExportResult(result *queryresult.Result, fileName string, format string, appendToFile bool) error {
+	// the result may be one of several teed results, all of which must be read for any to receive more rows
+	defer result.Drain()
+
+	export, ok := getExportFunc(format)
+	if !ok {
+		return ValidateExportFormat(format)
+	}
+
+	flags := os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+	if appendToFile {
+		flags = os.O_CREATE | os.O_WRONLY | os.O_APPEND
+	}
+	file, err := os.OpenFile(fileName, flags, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return export(file, result)
+}
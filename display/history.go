@@ -0,0 +1,41 @@
+package display
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/turbot/steampipe/query/queryhistory"
+)
+
+// ShowQueryHistory displays the given history entries as a table, oldest first
+func ShowQueryHistory(entries []*queryhistory.HistoryEntry) {
+	if len(entries) == 0 {
+		fmt.Println("No matching history entries")
+		return
+	}
+	headers := []string{"timestamp", "workspace", "duration", "rows", "error", "query"}
+	rows := make([][]string, len(entries))
+	for i, entry := range entries {
+		var timestamp, duration, rowCount string
+		// entries loaded from a legacy history file have no metadata
+		if !entry.Timestamp.IsZero() {
+			timestamp = entry.Timestamp.Format("2006-01-02 15:04:05")
+		}
+		if entry.Duration > 0 {
+			duration = entry.Duration.String()
+		}
+		if !entry.Timestamp.IsZero() && entry.Error == "" {
+			rowCount = fmt.Sprintf("%d", entry.RowCount)
+		}
+		rows[i] = []string{
+			timestamp,
+			entry.Workspace,
+			duration,
+			rowCount,
+			entry.Error,
+			// show multi-line queries on a single line
+			strings.Join(strings.Fields(entry.Query), " "),
+		}
+	}
+	ShowWrappedTable(headers, rows, false)
+}
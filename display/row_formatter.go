@@ -0,0 +1,178 @@
+package display
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"strings"
+
+	"github.com/turbot/steampipe/cmdconfig"
+	"github.com/turbot/steampipe/constants"
+	"github.com/turbot/steampipe/query/queryresult"
+	"sigs.k8s.io/yaml"
+)
+
+// RowFormatter :: writes a query result one row at a time, so results are streamed as they are read
+type RowFormatter interface {
+	// Header is called before the first row is written
+	Header(w io.Writer, colTypes []*sql.ColumnType) error
+	// Row is called for each row of the result
+	Row(w io.Writer, row []interface{}, colTypes []*sql.ColumnType) error
+	// Footer is called once all rows have been written
+	Footer(w io.Writer) error
+}
+
+// map of output format to the RowFormatter which writes it
+var rowFormatters = map[string]RowFormatter{
+	constants.ArgMarkdown: &markdownFormatter{},
+	constants.ArgHTML:     &htmlFormatter{},
+	constants.ArgYAML:     &yamlFormatter{},
+	constants.ArgNDJSON:   &ndjsonFormatter{},
+}
+
+// write the result to w using the given row formatter
+func writeFormattedResult(w io.Writer, result *queryresult.Result, formatter RowFormatter) error {
+	if err := formatter.Header(w, result.ColTypes); err != nil {
+		return err
+	}
+	var writeErr error
+	rowFunc := func(row []interface{}, result *queryresult.Result) {
+		// once a write has failed, ignore the remaining rows
+		if writeErr == nil {
+			writeErr = formatter.Row(w, row, result.ColTypes)
+		}
+	}
+	if err := iterateResults(result, rowFunc); err != nil {
+		return err
+	}
+	if writeErr != nil {
+		return writeErr
+	}
+	return formatter.Footer(w)
+}
+
+// convert a row into a record map, keyed by column name
+func rowAsRecord(row []interface{}, colTypes []*sql.ColumnType) map[string]interface{} {
+	record := map[string]interface{}{}
+	for idx, colType := range colTypes {
+		value, _ := ParseJSONOutputColumnValue(row[idx], colType)
+		record[colType.Name()] = value
+	}
+	return record
+}
+
+// markdownFormatter :: writes the result as a markdown table
+type markdownFormatter struct{}
+
+func (f *markdownFormatter) Header(w io.Writer, colTypes []*sql.ColumnType) error {
+	// a markdown table must have a header row - if headers are disabled, leave it blank
+	headers := make([]string, len(colTypes))
+	separators := make([]string, len(colTypes))
+	for idx, colType := range colTypes {
+		if cmdconfig.Viper().GetBool(constants.ArgHeader) {
+			headers[idx] = escapeMarkdown(colType.Name())
+		}
+		separators[idx] = "---"
+	}
+	_, err := fmt.Fprintf(w, "| %s |\n| %s |\n", strings.Join(headers, " | "), strings.Join(separators, " | "))
+	return err
+}
+
+func (f *markdownFormatter) Row(w io.Writer, row []interface{}, colTypes []*sql.ColumnType) error {
+	rowAsString, _ := ColumnValuesAsString(row, colTypes)
+	for idx, value := range rowAsString {
+		rowAsString[idx] = escapeMarkdown(value)
+	}
+	_, err := fmt.Fprintf(w, "| %s |\n", strings.Join(rowAsString, " | "))
+	return err
+}
+
+func (f *markdownFormatter) Footer(w io.Writer) error {
+	// add a blank line so that multiple tables written to the same output are kept separate
+	_, err := fmt.Fprintln(w)
+	return err
+}
+
+// escape characters which would break a markdown table cell
+func escapeMarkdown(value string) string {
+	value = strings.ReplaceAll(value, "|", `\|`)
+	return strings.ReplaceAll(value, "\n", "<br>")
+}
+
+// htmlFormatter :: writes the result as an html table
+type htmlFormatter struct{}
+
+func (f *htmlFormatter) Header(w io.Writer, colTypes []*sql.ColumnType) error {
+	var b strings.Builder
+	b.WriteString("<table>\n")
+	if cmdconfig.Viper().GetBool(constants.ArgHeader) {
+		b.WriteString("  <thead>\n    <tr>\n")
+		for _, colType := range colTypes {
+			fmt.Fprintf(&b, "      <th>%s</th>\n", html.EscapeString(colType.Name()))
+		}
+		b.WriteString("    </tr>\n  </thead>\n")
+	}
+	b.WriteString("  <tbody>\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func (f *htmlFormatter) Row(w io.Writer, row []interface{}, colTypes []*sql.ColumnType) error {
+	rowAsString, _ := ColumnValuesAsString(row, colTypes)
+	var b strings.Builder
+	b.WriteString("    <tr>\n")
+	for _, value := range rowAsString {
+		fmt.Fprintf(&b, "      <td>%s</td>\n", html.EscapeString(value))
+	}
+	b.WriteString("    </tr>\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func (f *htmlFormatter) Footer(w io.Writer) error {
+	_, err := io.WriteString(w, "  </tbody>\n</table>\n")
+	return err
+}
+
+// yamlFormatter :: writes the result as a yaml list of records
+type yamlFormatter struct{}
+
+func (f *yamlFormatter) Header(w io.Writer, colTypes []*sql.ColumnType) error {
+	return nil
+}
+
+func (f *yamlFormatter) Row(w io.Writer, row []interface{}, colTypes []*sql.ColumnType) error {
+	// marshal the record as a single element list, so that each row is written as a list item
+	yamlBytes, err := yaml.Marshal([]map[string]interface{}{rowAsRecord(row, colTypes)})
+	if err != nil {
+		return fmt.Errorf("error displaying result as YAML: %v", err)
+	}
+	_, err = w.Write(yamlBytes)
+	return err
+}
+
+func (f *yamlFormatter) Footer(w io.Writer) error {
+	return nil
+}
+
+// ndjsonFormatter :: writes the result as newline delimited json, with a record per line
+type ndjsonFormatter struct{}
+
+func (f *ndjsonFormatter) Header(w io.Writer, colTypes []*sql.ColumnType) error {
+	return nil
+}
+
+func (f *ndjsonFormatter) Row(w io.Writer, row []interface{}, colTypes []*sql.ColumnType) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(rowAsRecord(row, colTypes)); err != nil {
+		return fmt.Errorf("error displaying result as JSON: %v", err)
+	}
+	return nil
+}
+
+func (f *ndjsonFormatter) Footer(w io.Writer) error {
+	return nil
+}
@@ -0,0 +1,136 @@
+package display
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"unicode/utf8"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/turbot/steampipe/cmdconfig"
+	"github.com/turbot/steampipe/constants"
+)
+
+// return whether we should switch to streaming output, given the number of rows buffered so far
+// streaming is used if it was requested with the --streaming flag, or if the result is larger than the threshold
+func shouldStream(rowCount int) bool {
+	return cmdconfig.Viper().GetBool(constants.ArgStreaming) || rowCount > constants.StreamingRowThreshold
+}
+
+// tablePageWriter :: renders table output page by page, so that memory use is bounded for large results
+// each page is rendered as a separate table, with the column widths fixed by the first page
+type tablePageWriter struct {
+	w        io.Writer
+	colTypes []*sql.ColumnType
+	page     [][]string
+	// the column configs - set when the first page is rendered
+	colConfigs []table.ColumnConfig
+}
+
+func newTablePageWriter(w io.Writer, colTypes []*sql.ColumnType) *tablePageWriter {
+	return &tablePageWriter{
+		w:        w,
+		colTypes: colTypes,
+	}
+}
+
+// add a row, rendering the current page if it is full
+func (p *tablePageWriter) add(row []string) {
+	p.page = append(p.page, row)
+	if len(p.page) >= constants.StreamingTablePageSize {
+		p.flush()
+	}
+}
+
+// render any buffered rows
+func (p *tablePageWriter) flush() {
+	if len(p.page) == 0 {
+		return
+	}
+	if p.colConfigs == nil {
+		p.colConfigs = p.getColumnConfigs()
+	}
+
+	t := table.NewWriter()
+	t.SetOutputMirror(p.w)
+	t.SetStyle(table.StyleDefault)
+	t.Style().Format.Header = text.FormatDefault
+	t.SetColumnConfigs(p.colConfigs)
+	if cmdconfig.Viper().GetBool(constants.ArgHeader) {
+		headers := make(table.Row, len(p.colTypes))
+		for idx, column := range p.colTypes {
+			headers[idx] = column.Name()
+		}
+		t.AppendHeader(headers)
+	}
+	for _, row := range p.page {
+		rowObj := table.Row{}
+		for _, col := range row {
+			rowObj = append(rowObj, col)
+		}
+		t.AppendRow(rowObj)
+	}
+	t.Render()
+
+	p.page = nil
+}
+
+// fix the width of each column to the widest value in the current page (up to MaxColumnWidth),
+// so that all pages are rendered with the same column widths
+func (p *tablePageWriter) getColumnConfigs() []table.ColumnConfig {
+	colConfigs := make([]table.ColumnConfig, len(p.colTypes))
+	for idx, column := range p.colTypes {
+		width := utf8.RuneCountInString(column.Name())
+		for _, row := range p.page {
+			if l := getTerminalColumnsRequiredForString(row[idx]); l > width {
+				width = l
+			}
+		}
+		if width > constants.MaxColumnWidth {
+			width = constants.MaxColumnWidth
+		}
+		colConfigs[idx] = table.ColumnConfig{
+			Name:     column.Name(),
+			Number:   idx + 1,
+			WidthMin: width,
+			WidthMax: width,
+		}
+	}
+	return colConfigs
+}
+
+// jsonStreamWriter :: writes a JSON array of records one record at a time,
+// producing the same output as encoding the full array
+type jsonStreamWriter struct {
+	w     io.Writer
+	count int
+}
+
+func (j *jsonStreamWriter) write(record map[string]interface{}) error {
+	var b bytes.Buffer
+	encoder := json.NewEncoder(&b)
+	encoder.SetIndent(" ", " ")
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(record); err != nil {
+		return fmt.Errorf("error displaying result as JSON: %v", err)
+	}
+	separator := ",\n"
+	if j.count == 0 {
+		separator = "[\n"
+	}
+	j.count++
+	_, err := fmt.Fprintf(j.w, "%s %s", separator, bytes.TrimSuffix(b.Bytes(), []byte("\n")))
+	return err
+}
+
+func (j *jsonStreamWriter) close() error {
+	closing := "\n]\n"
+	if j.count == 0 {
+		closing = "[]\n"
+	}
+	_, err := io.WriteString(j.w, closing)
+	return err
+}
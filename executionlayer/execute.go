@@ -2,6 +2,8 @@ package executionlayer
 
 import (
 	"context"
+	"log"
+	"time"
 
 	"github.com/turbot/steampipe/db/db_common"
 	"github.com/turbot/steampipe/report/reportevents"
@@ -10,10 +12,10 @@ import (
 	"github.com/turbot/steampipe/workspace"
 )
 
-func ExecuteReportNode(ctx context.Context, reportName string, workspace *workspace.Workspace, client db_common.Client) error {
-	executionTree, err := reportexecute.NewReportExecutionTree(reportName, client, workspace)
+func ExecuteReportNode(ctx context.Context, reportName string, workspace *workspace.Workspace, client db_common.Client, cache *reportexecute.ResultCache) (*reportexecute.ReportExecutionTree, error) {
+	executionTree, err := reportexecute.NewReportExecutionTree(reportName, client, workspace, cache)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	go func() {
@@ -28,5 +30,35 @@ func ExecuteReportNode(ctx context.Context, reportName string, workspace *worksp
 		workspace.PublishReportEvent(&reportevents.ExecutionComplete{Report: executionTree.Root})
 	}()
 
-	return nil
+	return executionTree, nil
+}
+
+// RefreshReportNodes starts a timer for every node in the execution tree which specifies a refresh interval,
+// and re-executes the node each time its timer fires, until the context is cancelled.
+// Before each refresh, shouldRefresh is called - if it returns false, the refresh is skipped
+func RefreshReportNodes(ctx context.Context, executionTree *reportexecute.ReportExecutionTree, shouldRefresh func() bool) {
+	for name, interval := range executionTree.RefreshIntervals() {
+		go refreshNode(ctx, executionTree, name, interval, shouldRefresh)
+	}
+}
+
+func refreshNode(ctx context.Context, executionTree *reportexecute.ReportExecutionTree, name string, interval time.Duration, shouldRefresh func() bool) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if !shouldRefresh() {
+				log.Printf("[TRACE] skipping refresh of %s - no clients are viewing report %s", name, executionTree.Root.GetName())
+				continue
+			}
+			if err := executionTree.Refresh(ctx, name); err != nil {
+				// the error will already have been raised as a panel error event
+				log.Printf("[WARN] failed to refresh %s: %v", name, err)
+			}
+		}
+	}
 }
@@ -0,0 +1,148 @@
+package interactive
+
+import (
+	"fmt"
+
+	"github.com/c-bata/go-prompt"
+)
+
+// historySearch :: the state of a reverse incremental history search, started by pressing ctrl+r
+type historySearch struct {
+	// the search term
+	term string
+	// the queries in the history matching the term, most recent first
+	matches []string
+	// the index of the match currently displayed
+	index int
+	// the text currently displayed in the prompt buffer
+	text string
+}
+
+func (s *historySearch) failing() bool {
+	return len(s.matches) == 0
+}
+
+// the prompt prefix to display while searching
+func (s *historySearch) prefix() string {
+	if s.failing() {
+		return fmt.Sprintf("(failing reverse-i-search)`%s': ", s.term)
+	}
+	return fmt.Sprintf("(reverse-i-search)`%s': ", s.term)
+}
+
+// reverseSearchHistory is called when ctrl+r is pressed
+// if no search is active, start a search, using any text already entered as the search term
+// otherwise, display the next (older) match for the current search term
+func (c *InteractiveClient) reverseSearchHistory(b *prompt.Buffer) {
+	search := c.activeHistorySearch(b.Text())
+	if search == nil {
+		c.historySearch = &historySearch{term: b.Text(), text: b.Text()}
+		c.updateHistorySearch(b)
+		return
+	}
+	if search.index < len(search.matches)-1 {
+		search.index++
+		c.showHistorySearchMatch(b)
+	}
+}
+
+// handleHistorySearchInput is called for each printable character entered
+// if a search is active, the character is appended to the search term,
+// otherwise it is inserted into the buffer as normal
+func (c *InteractiveClient) handleHistorySearchInput(b *prompt.Buffer, input string) {
+	search := c.activeHistorySearch(b.Text())
+	if search == nil {
+		b.InsertText(input, false, true)
+		return
+	}
+	search.term += input
+	c.updateHistorySearch(b)
+}
+
+// handleHistorySearchBackspace is called after backspace has been handled by the prompt
+// if a search is active, remove the last character of the search term
+func (c *InteractiveClient) handleHistorySearchBackspace(b *prompt.Buffer) {
+	search := c.historySearch
+	if search == nil {
+		return
+	}
+	// the prompt will already have deleted the last character of the match text
+	runes := []rune(search.text)
+	if text := b.Text(); text != search.text && (len(runes) == 0 || text != string(runes[:len(runes)-1])) {
+		// the buffer has been edited since the search was run
+		c.endHistorySearch()
+		return
+	}
+	if termRunes := []rune(search.term); len(termRunes) > 0 {
+		search.term = string(termRunes[:len(termRunes)-1])
+	}
+	c.updateHistorySearch(b)
+}
+
+// endHistorySearch stops any active search, leaving the current match in the buffer so it may be edited
+func (c *InteractiveClient) endHistorySearch() {
+	c.historySearch = nil
+}
+
+// return the active search, if any
+// if the buffer no longer contains the search text, it has been edited by some other key binding,
+// in which case the search is ended
+func (c *InteractiveClient) activeHistorySearch(text string) *historySearch {
+	if c.historySearch != nil && c.historySearch.text != text {
+		c.endHistorySearch()
+	}
+	return c.historySearch
+}
+
+// rerun the search for the current term and display the most recent match
+// if there is no match, the buffer is left unchanged
+func (c *InteractiveClient) updateHistorySearch(b *prompt.Buffer) {
+	search := c.historySearch
+	search.matches = c.interactiveQueryHistory.FuzzySearch(search.term)
+	search.index = 0
+	c.showHistorySearchMatch(b)
+}
+
+// replace the buffer contents with the current match (this may be a multi-line query)
+func (c *InteractiveClient) showHistorySearchMatch(b *prompt.Buffer) {
+	search := c.historySearch
+	text := search.text
+	if !search.failing() {
+		text = search.matches[search.index]
+	}
+	// move to the end of the buffer and delete everything
+	b.CursorRight(len([]rune(b.Document().TextAfterCursor())))
+	b.DeleteBeforeCursor(len([]rune(b.Text())))
+	b.InsertText(text, false, true)
+	search.text = text
+}
+
+// key bindings for the history search
+func (c *InteractiveClient) historySearchKeyBindings() []prompt.Option {
+	opts := []prompt.Option{
+		prompt.OptionAddKeyBind(prompt.KeyBind{
+			Key: prompt.ControlR,
+			Fn:  c.reverseSearchHistory,
+		}),
+		prompt.OptionAddKeyBind(prompt.KeyBind{
+			Key: prompt.Backspace,
+			Fn:  c.handleHistorySearchBackspace,
+		}),
+	}
+	// moving the cursor ends the search, leaving the match in the buffer for editing
+	for _, key := range []prompt.Key{prompt.Left, prompt.Right, prompt.Home, prompt.End, prompt.ControlA, prompt.ControlE} {
+		opts = append(opts, prompt.OptionAddKeyBind(prompt.KeyBind{
+			Key: key,
+			Fn:  func(*prompt.Buffer) { c.endHistorySearch() },
+		}))
+	}
+	// bind all printable characters so they can be added to the search term when searching
+	for i := byte(' '); i <= '~'; i++ {
+		input := string(i)
+		opts = append(opts, prompt.OptionAddASCIICodeBind(prompt.ASCIICodeBind{
+			ASCIICode: []byte(input),
+			Fn:        func(b *prompt.Buffer) { c.handleHistorySearchInput(b, input) },
+		}))
+	}
+	return opts
+}
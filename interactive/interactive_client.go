@@ -35,6 +35,9 @@ const (
 	AfterPromptCloseRestart
 )
 
+// the characters which separate the word being completed from the preceding text, e.g. '(' in 'count(b.name'
+const completionWordSeparators = " \t(),;=<>!"
+
 // InteractiveClient :: wrapper over *LocalClient and *prompt.Prompt along
 // to facilitate interactive query prompt
 type InteractiveClient struct {
@@ -58,6 +61,15 @@ type InteractiveClient struct {
 	executionLock sync.Mutex
 
 	highlighter *Highlighter
+
+	// the active reverse history search - nil if we are not searching
+	historySearch *historySearch
+
+	// the result of the last query executed, retained so it may be exported
+	lastResult *queryresult.SyncQueryResult
+
+	// the qualifier values used by previous queries, suggested when completing a comparison of a column
+	qualifierValues *autocomplete.QualifierValues
 }
 
 func getHighlighter(theme string) *Highlighter {
@@ -69,9 +81,11 @@ func getHighlighter(theme string) *Highlighter {
 }
 
 func newInteractiveClient(initChan *chan *db_common.QueryInitData, resultsStreamer *queryresult.ResultStreamer) (*InteractiveClient, error) {
+	history := queryhistory.New()
 	c := &InteractiveClient{
 		resultsStreamer:         resultsStreamer,
-		interactiveQueryHistory: queryhistory.New(),
+		interactiveQueryHistory: history,
+		qualifierValues:         autocomplete.NewQualifierValues(history.Get()),
 		interactiveBuffer:       []string{},
 		autocompleteOnEmpty:     false,
 		initDataChan:            initChan,
@@ -213,14 +227,14 @@ func (c *InteractiveClient) runInteractivePrompt(ctx context.Context) (ret utils
 	completer := func(d prompt.Document) []prompt.Suggest {
 		return c.queryCompleter(d)
 	}
-	c.interactivePrompt = prompt.New(
-		callExecutor,
-		completer,
+	opts := []prompt.Option{
 		prompt.OptionTitle("steampipe interactive client "),
 		prompt.OptionLivePrefix(func() (prefix string, useLive bool) {
 			prefix = "> "
 			useLive = true
-			if len(c.interactiveBuffer) > 0 {
+			if c.historySearch != nil {
+				prefix = c.historySearch.prefix()
+			} else if len(c.interactiveBuffer) > 0 {
 				prefix = ">>  "
 			}
 			return
@@ -230,6 +244,7 @@ func (c *InteractiveClient) runInteractivePrompt(ctx context.Context) (ret utils
 		prompt.OptionInputTextColor(prompt.DefaultColor),
 		prompt.OptionPrefixTextColor(prompt.DefaultColor),
 		prompt.OptionMaxSuggestion(20),
+		prompt.OptionCompletionWordSeparator(completionWordSeparators),
 		// Known Key Bindings
 		prompt.OptionAddKeyBind(prompt.KeyBind{
 			Key: prompt.ControlC,
@@ -260,6 +275,7 @@ func (c *InteractiveClient) runInteractivePrompt(ctx context.Context) (ret utils
 				if len(b.Text()) == 0 {
 					c.autocompleteOnEmpty = false
 				}
+				c.endHistorySearch()
 			},
 		}),
 		prompt.OptionAddKeyBind(prompt.KeyBind{
@@ -298,7 +314,10 @@ func (c *InteractiveClient) runInteractivePrompt(ctx context.Context) (ret utils
 			ASCIICode: constants.AltRightArrowASCIICode,
 			Fn:        prompt.GoRightWord,
 		}),
-	)
+	}
+	// add the key bindings for reverse history search (ctrl+r)
+	opts = append(opts, c.historySearchKeyBindings()...)
+	c.interactivePrompt = prompt.New(callExecutor, completer, opts...)
 	// set this to a default
 	c.autocompleteOnEmpty = false
 	c.interactivePrompt.RunCtx(ctx)
@@ -308,6 +327,7 @@ func (c *InteractiveClient) runInteractivePrompt(ctx context.Context) (ret utils
 
 func (c *InteractiveClient) breakMultilinePrompt(buffer *prompt.Buffer) {
 	c.interactiveBuffer = []string{}
+	c.endHistorySearch()
 }
 
 func (c *InteractiveClient) executor(line string) {
@@ -316,16 +336,26 @@ func (c *InteractiveClient) executor(line string) {
 
 	// set afterClose to restart - is we are exiting the metaquery will set this to AfterPromptCloseExit
 	c.afterClose = AfterPromptCloseRestart
+	// if a history search was active, the selected match is being executed
+	c.endHistorySearch()
 
 	line = strings.TrimSpace(line)
+
+	query, err := c.getQuery(line)
 	// store the history (the raw line which was entered)
 	// we want to store even if we fail to resolve a query
-	c.interactiveQueryHistory.Push(line)
+	// - if this line completes a multi-line query, store the full query instead, so it may be recalled as a whole
+	historyText := line
+	if len(c.interactiveBuffer) > 1 && query != "" {
+		historyText = strings.Join(c.interactiveBuffer, "\n")
+	}
+	historyEntry := c.interactiveQueryHistory.Push(historyText)
 
-	query, err := c.getQuery(line)
 	if query == "" {
 		if err != nil {
-			utils.ShowError(utils.HandleCancelError(err))
+			err = utils.HandleCancelError(err)
+			utils.ShowError(err)
+			setHistoryEntryError(historyEntry, err)
 		}
 		// restart the prompt
 		c.restartInteractiveSession()
@@ -338,17 +368,34 @@ func (c *InteractiveClient) executor(line string) {
 	if metaquery.IsMetaQuery(query) {
 		if err := c.executeMetaquery(queryContext, query); err != nil {
 			utils.ShowError(err)
+			setHistoryEntryError(historyEntry, err)
 		}
 		// cancel the context
 		c.cancelActiveQueryIfAny()
 
 	} else {
+		// record the qualifier values of the query for completion
+		c.qualifierValues.Add(query)
 		// otherwise execute query
 		result, err := c.client().Execute(queryContext, query, false)
 		if err != nil {
-			utils.ShowError(utils.HandleCancelError(err))
+			err = utils.HandleCancelError(err)
+			utils.ShowError(err)
+			setHistoryEntryError(historyEntry, err)
 		} else {
+			// wrap the result so we can record the row count and duration in the history
+			result, summaryChan := result.WithSummary()
+			// also capture the rows so the result may be exported or written to the tee file
+			// - results too large to hold in memory are not kept
+			result, captureChan := result.WithCapture(constants.StreamingRowThreshold)
 			c.resultsStreamer.StreamResult(result)
+			setHistoryEntrySummary(historyEntry, <-summaryChan)
+			c.lastResult = <-captureChan
+			if c.lastResult == nil {
+				fmt.Printf("The result has more than %d rows so was not kept - it cannot be exported or written to the tee file\n", constants.StreamingRowThreshold)
+			} else {
+				teeResult(c.lastResult)
+			}
 		}
 	}
 
@@ -437,13 +484,15 @@ func (c *InteractiveClient) executeMetaquery(ctx context.Context, query string)
 	}
 	client := c.client()
 	// validation passed, now we will run
-	return metaquery.Handle(&metaquery.HandlerInput{
+	return metaquery.Handle(ctx, &metaquery.HandlerInput{
 		Query:       query,
 		Executor:    client,
 		Schema:      client.SchemaMetadata(),
 		Connections: client.ConnectionMap(),
 		Prompt:      c.interactivePrompt,
 		ClosePrompt: func() { c.afterClose = AfterPromptCloseExit },
+		History:     c.interactiveQueryHistory,
+		LastResult:  c.lastResult,
 	})
 }
 
@@ -462,6 +511,10 @@ func (c *InteractiveClient) queryCompleter(d prompt.Document) []prompt.Suggest {
 	if !c.isInitialised() {
 		return nil
 	}
+	// do not show suggestions while searching the history
+	if c.historySearch != nil {
+		return nil
+	}
 
 	text := strings.TrimLeft(strings.ToLower(d.Text), " ")
 
@@ -498,23 +551,22 @@ func (c *InteractiveClient) queryCompleter(d prompt.Document) []prompt.Suggest {
 
 		s = append(s, suggestions...)
 	} else {
-		queryInfo := getQueryInfo(text)
-
-		// only add table suggestions if the client is initialised
-		if queryInfo.EditingTable && c.isInitialised() {
-			s = append(s, autocomplete.GetTableAutoCompleteSuggestions(c.initData.Client.SchemaMetadata(), c.initData.Client.ConnectionMap())...)
+		// complete the sql statement - include any previous lines of a multi-line query
+		var previousLines string
+		if len(c.interactiveBuffer) > 0 {
+			previousLines = strings.Join(c.interactiveBuffer, "\n") + "\n"
 		}
-
-		// Not sure this is working. comment out for now!
-		// if queryInfo.EditingColumn {
-		// 	fmt.Println(queryInfo.Table)
-		// 	for _, column := range schemaMetadata.ColumnMap[queryInfo.Table] {
-		// 		s = append(s, prompt.Suggest{Text: column})
-		// 	}
-		// }
-
+		client := c.client()
+		s = append(s, autocomplete.GetSQLAutoCompleteSuggestions(&autocomplete.SQLCompleterInput{
+			Text:            previousLines + d.Text,
+			Cursor:          len(previousLines) + len(d.TextBeforeCursor()),
+			Schema:          client.SchemaMetadata(),
+			Connections:     client.ConnectionMap(),
+			Functions:       constants.Functions,
+			QualifierValues: c.qualifierValues,
+		})...)
 	}
-	return prompt.FilterHasPrefix(s, d.GetWordBeforeCursor(), true)
+	return prompt.FilterHasPrefix(s, d.GetWordBeforeCursorUntilSeparator(completionWordSeparators), true)
 }
 
 func (c *InteractiveClient) namedQuerySuggestions() []prompt.Suggest {
@@ -1,81 +1,59 @@
 package interactive
 
 import (
+	"fmt"
 	"strings"
 
-	"github.com/turbot/go-kit/helpers"
+	"github.com/turbot/steampipe/cmdconfig"
+	"github.com/turbot/steampipe/constants"
+	"github.com/turbot/steampipe/display"
+	"github.com/turbot/steampipe/query/queryhistory"
+	"github.com/turbot/steampipe/query/queryresult"
+	"github.com/turbot/steampipe/utils"
 )
 
-type queryCompletionInfo struct {
-	Table         string
-	EditingTable  bool
-	EditingColumn bool
+// if there are no spaces this is the first word
+func isFirstWord(text string) bool {
+	return strings.LastIndex(text, " ") == -1
 }
 
-func getQueryInfo(text string) *queryCompletionInfo {
-	table := getTable(text)
-	prevWord := getPreviousWord(text)
-
-	return &queryCompletionInfo{
-		Table:         table,
-		EditingTable:  isEditingTable(prevWord),
-		EditingColumn: isEditingColumn(prevWord, table),
+// record the error in the history entry (which may be nil if the line was not stored)
+func setHistoryEntryError(entry *queryhistory.HistoryEntry, err error) {
+	if entry == nil {
+		return
 	}
+	entry.Error = err.Error()
 }
 
-func isEditingTable(prevWord string) bool {
-	var editingTable = prevWord == "from"
-	return editingTable
-}
-
-func isEditingColumn(prevWord string, table string) bool {
-	var editingColumn = prevWord == "where" || prevWord == "and" || prevWord == "or" && table != ""
-	return editingColumn
-}
-
-func getTable(text string) string {
-	// split on space and remove empty results - they occur if there is a double space
-	split := helpers.RemoveFromStringSlice(strings.Split(text, " "), "")
-
-	for idx, word := range split {
-		if word == "from" {
-			if idx+1 < len(split) {
-				return split[idx+1]
-			}
-		}
+// record the row count, duration and any error of the executed query in the history entry
+func setHistoryEntrySummary(entry *queryhistory.HistoryEntry, summary *queryresult.ResultSummary) {
+	if entry == nil {
+		return
 	}
-	return ""
-}
-
-func getPreviousWord(text string) string {
-	// create a new document up the previous space
-	finalSpace := strings.LastIndex(text, " ")
-	if finalSpace == -1 {
-		return ""
-	}
-	lastNotSpace := lastIndexByteNot(text[:finalSpace], ' ')
-	if lastNotSpace == -1 {
-		return ""
-	}
-	prevSpace := strings.LastIndex(text[:lastNotSpace], " ")
-	if prevSpace == -1 {
-		return ""
+	entry.RowCount = summary.RowCount
+	entry.Duration = summary.Duration
+	if summary.Error != nil {
+		entry.Error = summary.Error.Error()
 	}
-	return text[prevSpace+1 : lastNotSpace+1]
 }
 
-func lastIndexByteNot(s string, c byte) int {
-	for i := len(s) - 1; i >= 0; i-- {
-		if s[i] != c {
-			return i
+// if tee is enabled, append the result to the tee file
+// results containing an error are not written (the error will already have been displayed)
+func teeResult(result *queryresult.SyncQueryResult) {
+	teeFile := cmdconfig.Viper().GetString(constants.ArgTee)
+	if teeFile == "" {
+		return
+	}
+	for _, row := range result.Rows {
+		if row.(*queryresult.RowResult).Error != nil {
+			return
 		}
 	}
-	return -1
-}
-
-// if there are no spaces this is the first word
-func isFirstWord(text string) bool {
-	return strings.LastIndex(text, " ") == -1
+	// the format was validated when tee was enabled
+	format, _ := display.InferExportFormatFromFileName(teeFile)
+	if err := display.ExportResult(result.Stream(), teeFile, format, true); err != nil {
+		utils.ShowErrorWithMessage(err, fmt.Sprintf("failed to write result to %s", teeFile))
+	}
 }
 
 //
@@ -15,7 +15,7 @@ func PromptForMissingVariables(ctx context.Context, missingVariables []*modconfi
 	fmt.Println()
 	fmt.Println("Variables defined with no value set.")
 	for _, v := range missingVariables {
-		r, err := promptForVariable(ctx, v.ShortName, v.Description)
+		r, err := promptForVariable(ctx, v.ShortName, v.Description, v.Sensitive)
 		if err != nil {
 			return err
 		}
@@ -24,12 +24,14 @@ func PromptForMissingVariables(ctx context.Context, missingVariables []*modconfi
 	return nil
 }
 
-func promptForVariable(ctx context.Context, name, description string) (string, error) {
+// if the variable is sensitive, the value is not echoed as it is entered
+func promptForVariable(ctx context.Context, name, description string, sensitive bool) (string, error) {
 	uiInput := &input_vars.UIInput{}
 	rawValue, err := uiInput.Input(ctx, &terraform.InputOpts{
 		Id:          fmt.Sprintf("var.%s", name),
 		Query:       fmt.Sprintf("var.%s", name),
 		Description: description,
+		Secret:      sensitive,
 	})
 
 	return rawValue, err
@@ -0,0 +1,97 @@
+package lsp
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/gohcl"
+	"github.com/turbot/steampipe/steampipeconfig/modconfig"
+	"github.com/turbot/steampipe/steampipeconfig/modconfig/var_config"
+	"github.com/turbot/steampipe/steampipeconfig/parse"
+)
+
+// the schema of each block type
+// nested blocks whose schema depends on their parent are keyed by '<parent>.<type>'
+var blockSchemas = map[string]*hcl.BodySchema{
+	modconfig.BlockTypeMod:       impliedSchema(&modconfig.Mod{}),
+	"requires":                   impliedSchema(&modconfig.Requires{}),
+	"requires.mod":               impliedSchema(&modconfig.ModVersion{}),
+	"requires.plugin":            impliedSchema(&modconfig.PluginVersion{}),
+	"opengraph":                  impliedSchema(&modconfig.OpenGraph{}),
+	modconfig.BlockTypeVariable:  var_config.VariableBlockSchema(),
+	modconfig.BlockTypeQuery:     parse.QueryBlockSchema,
+	modconfig.BlockTypeControl:   parse.ControlBlockSchema,
+	modconfig.BlockTypeBenchmark: impliedSchema(&modconfig.Benchmark{}),
+	"benchmark.override":         impliedSchema(&modconfig.BenchmarkOverride{}),
+	modconfig.BlockTypeReport:    parse.ReportBlockSchema,
+	modconfig.BlockTypePanel:     parse.PanelBlockSchema,
+	modconfig.BlockTypeTest:      parse.TestBlockSchema,
+	modconfig.BlockTypeParam:     parse.ParamDefBlockSchema,
+	modconfig.BlockTypeFixture:   parse.TestFixtureBlockSchema,
+}
+
+func impliedSchema(val interface{}) *hcl.BodySchema {
+	schema, _ := gohcl.ImpliedBodySchema(val)
+	return schema
+}
+
+// return the completions at the given offset of the document text
+// - when typing a traversal, e.g. 'query.', the names of matching resources
+// - when typing a value, the resource types and mod names which may start a traversal
+// - at the top level, the block types
+// - within a block, the attributes and nested blocks of the block
+func completions(text string, offset int, idx *resourceIndex) []*completionItem {
+	word, start := wordAt(text, offset)
+	word = word[:offset-start]
+
+	if strings.Contains(word, ".") {
+		return idx.completions(word)
+	}
+
+	ctx := scanEditContext(text, start)
+	if ctx.inValue || ctx.inExpression {
+		return idx.rootCompletions()
+	}
+	if len(ctx.blocks) == 0 {
+		return schemaCompletions(parse.ModBlockSchema)
+	}
+	return schemaCompletions(getBlockSchema(ctx.blocks))
+}
+
+// return the schema for the innermost of the given blocks
+func getBlockSchema(blocks []string) *hcl.BodySchema {
+	blockType := blocks[len(blocks)-1]
+	if len(blocks) > 1 {
+		if schema, ok := blockSchemas[blocks[len(blocks)-2]+"."+blockType]; ok {
+			return schema
+		}
+	}
+	return blockSchemas[blockType]
+}
+
+// return completions for the attributes and blocks of a schema
+func schemaCompletions(schema *hcl.BodySchema) []*completionItem {
+	if schema == nil {
+		return nil
+	}
+	var items []*completionItem
+	for _, attribute := range schema.Attributes {
+		item := &completionItem{Label: attribute.Name, Kind: completionKindProperty}
+		if attribute.Required {
+			item.Detail = "required"
+		}
+		items = append(items, item)
+	}
+	seen := make(map[string]bool)
+	for _, block := range schema.Blocks {
+		if !seen[block.Type] {
+			seen[block.Type] = true
+			items = append(items, &completionItem{Label: block.Type, Kind: completionKindKeyword, Detail: "block"})
+		}
+	}
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].Label < items[j].Label
+	})
+	return items
+}
@@ -0,0 +1,86 @@
+package lsp
+
+import (
+	"fmt"
+	"path/filepath"
+	"unicode/utf8"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/turbot/steampipe/constants"
+)
+
+// publish the diagnostics, grouped by file
+// diagnostics with no source range are published for the mod file
+// the diagnostics of files which no longer have any are cleared
+func (s *Server) publishDiagnostics(diags hcl.Diagnostics) {
+	modFilePath := filepath.Join(s.workspacePath, constants.WorkspaceModFileName)
+
+	fileDiagnostics := make(map[string][]*diagnostic)
+	for _, d := range diags {
+		path := modFilePath
+		var diagRange textRange
+		if d.Subject != nil && d.Subject.Filename != "" {
+			path = d.Subject.Filename
+			diagRange = s.hclRange(d.Subject)
+		}
+		uri := pathToURI(path)
+		fileDiagnostics[uri] = append(fileDiagnostics[uri], &diagnostic{
+			Range:    diagRange,
+			Severity: diagnosticSeverity(d.Severity),
+			Source:   diagnosticSource,
+			Message:  diagnosticMessage(d),
+		})
+	}
+
+	for uri := range s.diagnosticURIs {
+		if _, ok := fileDiagnostics[uri]; !ok {
+			s.conn.notify("textDocument/publishDiagnostics", &publishDiagnosticsParams{URI: uri, Diagnostics: []*diagnostic{}})
+		}
+	}
+	s.diagnosticURIs = make(map[string]bool)
+	for uri, fileDiags := range fileDiagnostics {
+		s.conn.notify("textDocument/publishDiagnostics", &publishDiagnosticsParams{URI: uri, Diagnostics: fileDiags})
+		s.diagnosticURIs[uri] = true
+	}
+}
+
+// convert an hcl range to an LSP range
+func (s *Server) hclRange(r *hcl.Range) textRange {
+	text := s.fileText(r.Filename)
+	return textRange{
+		Start: positionForOffset(text, hclPosOffset(text, r.Start)),
+		End:   positionForOffset(text, hclPosOffset(text, r.End)),
+	}
+}
+
+// return the byte offset of an hcl position
+// ranges which are not built by the hcl parser may not set the byte offset - for these, use the line and column
+func hclPosOffset(text string, pos hcl.Pos) int {
+	if pos.Byte > 0 || (pos.Line <= 1 && pos.Column <= 1) {
+		return pos.Byte
+	}
+	// hcl lines and columns are 1 based, and columns count characters
+	offset := offsetForPosition(text, position{Line: pos.Line - 1})
+	for column := 1; column < pos.Column && offset < len(text); column++ {
+		r, size := utf8.DecodeRuneInString(text[offset:])
+		if r == '\n' {
+			break
+		}
+		offset += size
+	}
+	return offset
+}
+
+func diagnosticSeverity(severity hcl.DiagnosticSeverity) int {
+	if severity == hcl.DiagWarning {
+		return severityWarning
+	}
+	return severityError
+}
+
+func diagnosticMessage(d *hcl.Diagnostic) string {
+	if d.Detail == "" {
+		return d.Summary
+	}
+	return fmt.Sprintf("%s: %s", d.Summary, d.Detail)
+}
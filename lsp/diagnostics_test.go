@@ -0,0 +1,45 @@
+package lsp
+
+import (
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+)
+
+type hclPosOffsetTest struct {
+	pos      hcl.Pos
+	expected int
+}
+
+const hclPosOffsetText = "query \"q1\" {\n  title = \"é é\"\n}\n"
+
+var testCasesHclPosOffset = map[string]hclPosOffsetTest{
+	"start of file": {
+		pos:      hcl.Pos{Line: 1, Column: 1, Byte: 0},
+		expected: 0,
+	},
+	"byte offset": {
+		pos:      hcl.Pos{Line: 2, Column: 3, Byte: 15},
+		expected: 15,
+	},
+	"line and column": {
+		pos:      hcl.Pos{Line: 2, Column: 3},
+		expected: 15,
+	},
+	"column after multibyte character": {
+		pos:      hcl.Pos{Line: 2, Column: 14},
+		expected: 27,
+	},
+	"column beyond end of line": {
+		pos:      hcl.Pos{Line: 3, Column: 10},
+		expected: 32,
+	},
+}
+
+func TestHclPosOffset(t *testing.T) {
+	for name, test := range testCasesHclPosOffset {
+		if offset := hclPosOffset(hclPosOffsetText, test.pos); offset != test.expected {
+			t.Errorf("Test: '%s' FAILED : expected %d, got %d", name, test.expected, offset)
+		}
+	}
+}
@@ -0,0 +1,166 @@
+package lsp
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/turbot/go-kit/types"
+	"github.com/turbot/steampipe/steampipeconfig/modconfig"
+	"github.com/turbot/steampipe/steampipeconfig/parse"
+	"github.com/turbot/steampipe/workspace"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// resourceInfo is the information about a resource used for completion, hover and definition
+type resourceInfo struct {
+	// the name the resource is referenced by, e.g. query.q1 or aws_compliance.query.q1
+	name         string
+	resourceType string
+	title        string
+	description  string
+	// additional markdown, e.g. the type and default of a variable
+	detail    string
+	declRange *hcl.Range
+}
+
+// return the hover markdown for the resource
+func (r *resourceInfo) markdown() string {
+	var parts []string
+	if r.title != "" {
+		parts = append(parts, fmt.Sprintf("**%s**", r.title))
+	}
+	parts = append(parts, fmt.Sprintf("`%s`", r.name))
+	if r.description != "" {
+		parts = append(parts, r.description)
+	}
+	if r.detail != "" {
+		parts = append(parts, r.detail)
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+// resourceIndex is the index of all resources of the workspace, keyed by every name they may be referenced by
+type resourceIndex struct {
+	resources map[string]*resourceInfo
+}
+
+// build the index from the workspace resource maps
+// local resources are keyed by both short and qualified name, dependency mod resources by qualified name
+func newResourceIndex(w *workspace.Workspace) *resourceIndex {
+	idx := &resourceIndex{resources: make(map[string]*resourceInfo)}
+	if w == nil {
+		return idx
+	}
+
+	resourceMaps := w.GetResourceMaps()
+	for name, q := range resourceMaps.Queries {
+		idx.add(name, modconfig.BlockTypeQuery, types.SafeString(q.Title), types.SafeString(q.Description), "", q.GetDeclRange())
+	}
+	for name, c := range resourceMaps.Controls {
+		idx.add(name, modconfig.BlockTypeControl, c.GetTitle(), c.GetDescription(), "", c.GetDeclRange())
+	}
+	for name, b := range resourceMaps.Benchmarks {
+		idx.add(name, modconfig.BlockTypeBenchmark, b.GetTitle(), b.GetDescription(), "", b.GetDeclRange())
+	}
+	for name, v := range resourceMaps.Variables {
+		idx.add(name, "var", "", v.Description, variableDetail(v), v.GetDeclRange())
+	}
+	return idx
+}
+
+func (idx *resourceIndex) add(name, resourceType, title, description, detail string, declRange *hcl.Range) {
+	idx.resources[name] = &resourceInfo{
+		name:         name,
+		resourceType: resourceType,
+		title:        title,
+		description:  description,
+		detail:       detail,
+		declRange:    declRange,
+	}
+}
+
+// return the type and default of a variable - the default of a sensitive variable is not shown
+func variableDetail(v *modconfig.Variable) string {
+	v = v.Redacted()
+	detail := fmt.Sprintf("Type: `%s`", v.Type.FriendlyName())
+	if v.Default != cty.NilVal {
+		if defaultValue, err := parse.CtyToJSON(v.Default); err == nil {
+			detail += fmt.Sprintf("\n\nDefault: `%s`", defaultValue)
+		}
+	}
+	return detail
+}
+
+// return the resource referenced by a traversal, e.g. query.q1, dep.control.c1 or var.config.region
+func (idx *resourceIndex) lookup(traversal string) *resourceInfo {
+	parts := strings.Split(traversal, ".")
+	for n := len(parts); n >= 2; n-- {
+		if r, ok := idx.resources[strings.Join(parts[:n], ".")]; ok {
+			return r
+		}
+	}
+	return nil
+}
+
+// return the completions for the final segment of a partial traversal, e.g. 'query.' or 'dep.control.c'
+// the completions are the names of the resources and, for partial mod qualified names, the resource types
+func (idx *resourceIndex) completions(traversal string) []*completionItem {
+	head := traversal[:strings.LastIndex(traversal, ".")+1]
+
+	var items []*completionItem
+	seen := make(map[string]bool)
+	for _, name := range idx.sortedNames() {
+		if !strings.HasPrefix(name, head) {
+			continue
+		}
+		rest := name[len(head):]
+		if segment := strings.Split(rest, ".")[0]; segment != rest {
+			// this is an intermediate segment, e.g. the resource type of a mod qualified name
+			if !seen[segment] {
+				seen[segment] = true
+				items = append(items, &completionItem{Label: segment, Kind: completionKindModule})
+			}
+			continue
+		}
+		items = append(items, idx.resources[name].completionItem(rest))
+	}
+	return items
+}
+
+// return the completions for the first segment of a traversal - the resource types and dependency mod names
+func (idx *resourceIndex) rootCompletions() []*completionItem {
+	var items []*completionItem
+	seen := make(map[string]bool)
+	for _, name := range idx.sortedNames() {
+		root := strings.Split(name, ".")[0]
+		if !seen[root] {
+			seen[root] = true
+			items = append(items, &completionItem{Label: root, Kind: completionKindModule})
+		}
+	}
+	return items
+}
+
+func (idx *resourceIndex) sortedNames() []string {
+	names := make([]string, 0, len(idx.resources))
+	for name := range idx.resources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (r *resourceInfo) completionItem(label string) *completionItem {
+	item := &completionItem{
+		Label:         label,
+		Kind:          completionKindReference,
+		Detail:        r.title,
+		Documentation: &markupContent{Kind: "markdown", Value: r.markdown()},
+	}
+	if r.resourceType == "var" {
+		item.Kind = completionKindVariable
+	}
+	return item
+}
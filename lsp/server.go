@@ -0,0 +1,282 @@
+package lsp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"path/filepath"
+	"regexp"
+
+	"github.com/turbot/go-kit/helpers"
+	"github.com/turbot/steampipe/mod/modvalidate"
+	"github.com/turbot/steampipe/steampipeconfig/modconfig"
+	"github.com/turbot/steampipe/version"
+	"github.com/turbot/steampipe/workspace"
+)
+
+// the source of published diagnostics
+const diagnosticSource = "steampipe"
+
+// matches a block header, e.g. 'control "c1" {', capturing the block type and name
+var blockHeader = regexp.MustCompile(`^\s*([A-Za-z_]+)\s+"([^"]*)"`)
+
+// Server is a language server for the .sp files of a workspace mod
+// the workspace is loaded on start and reloaded whenever a document is saved
+type Server struct {
+	workspacePath string
+	conn          *conn
+	// the text of the open documents, keyed by uri
+	documents map[string]string
+	// the last successfully loaded workspace, and the index of its resources
+	workspace *workspace.Workspace
+	index     *resourceIndex
+	// the uris of the documents with published diagnostics
+	diagnosticURIs map[string]bool
+	shutdown       bool
+}
+
+// NewServer creates a language server for the workspace at the given path
+// if the client provides a root uri on initialization, that is used as the workspace path
+func NewServer(workspacePath string) *Server {
+	return &Server{
+		workspacePath:  workspacePath,
+		documents:      make(map[string]string),
+		index:          newResourceIndex(nil),
+		diagnosticURIs: make(map[string]bool),
+	}
+}
+
+// Run reads requests from the reader and writes responses to the writer until the client sends an exit notification
+// an error is returned if the input is closed, or the client exits without requesting shutdown
+func (s *Server) Run(ctx context.Context, r io.Reader, w io.Writer) error {
+	s.conn = newConn(r, w)
+	defer func() {
+		if s.workspace != nil {
+			s.workspace.Close()
+		}
+	}()
+
+	for {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		msg, err := s.conn.read()
+		if err != nil {
+			if rpcErr, ok := err.(*responseError); ok {
+				s.conn.replyError(nil, rpcErr.Code, rpcErr.Message)
+				continue
+			}
+			return err
+		}
+		if msg.Method == "exit" {
+			if !s.shutdown {
+				return fmt.Errorf("exit received before shutdown")
+			}
+			return nil
+		}
+		s.handle(msg)
+	}
+}
+
+// handle a request or notification - requests are always replied to
+func (s *Server) handle(msg *message) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("[WARN] language server failed to handle %s: %v", msg.Method, r)
+			if msg.ID != nil {
+				s.conn.replyError(msg.ID, codeInternalError, helpers.ToError(r).Error())
+			}
+		}
+	}()
+
+	result, err := s.dispatch(msg)
+	if msg.ID == nil {
+		if err != nil {
+			log.Printf("[WARN] language server failed to handle %s: %s", msg.Method, err.Error())
+		}
+		return
+	}
+	if err != nil {
+		code := codeInternalError
+		if rpcErr, ok := err.(*responseError); ok {
+			code = rpcErr.Code
+		}
+		s.conn.replyError(msg.ID, code, err.Error())
+		return
+	}
+	s.conn.reply(msg.ID, result)
+}
+
+func (s *Server) dispatch(msg *message) (interface{}, error) {
+	switch msg.Method {
+	case "initialize":
+		var params initializeParams
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			return nil, err
+		}
+		return s.initialize(&params), nil
+	case "initialized":
+		s.loadWorkspace()
+	case "shutdown":
+		s.shutdown = true
+	case "textDocument/didOpen":
+		var params didOpenParams
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			return nil, err
+		}
+		s.documents[params.TextDocument.URI] = params.TextDocument.Text
+	case "textDocument/didChange":
+		var params didChangeParams
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			return nil, err
+		}
+		// full document sync is used, so the last change is the full text of the document
+		if len(params.ContentChanges) > 0 {
+			s.documents[params.TextDocument.URI] = params.ContentChanges[len(params.ContentChanges)-1].Text
+		}
+	case "textDocument/didSave":
+		s.loadWorkspace()
+	case "textDocument/didClose":
+		var params didCloseParams
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			return nil, err
+		}
+		delete(s.documents, params.TextDocument.URI)
+	case "textDocument/completion":
+		var params textDocumentPositionParams
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			return nil, err
+		}
+		return s.completion(&params), nil
+	case "textDocument/hover":
+		var params textDocumentPositionParams
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			return nil, err
+		}
+		return s.hover(&params), nil
+	case "textDocument/definition":
+		var params textDocumentPositionParams
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			return nil, err
+		}
+		return s.definition(&params), nil
+	default:
+		// unsupported notifications are ignored
+		if msg.ID != nil {
+			return nil, &responseError{Code: codeMethodNotFound, Message: fmt.Sprintf("method '%s' not supported", msg.Method)}
+		}
+	}
+	return nil, nil
+}
+
+func (s *Server) initialize(params *initializeParams) *initializeResult {
+	if params.RootURI != "" {
+		s.workspacePath = uriToPath(params.RootURI)
+	}
+	if absPath, err := filepath.Abs(s.workspacePath); err == nil {
+		s.workspacePath = absPath
+	}
+
+	return &initializeResult{
+		Capabilities: serverCapabilities{
+			TextDocumentSync: textDocumentSyncOptions{
+				OpenClose: true,
+				Change:    textDocumentSyncFull,
+				Save:      saveOptions{IncludeText: false},
+			},
+			CompletionProvider: completionOptions{TriggerCharacters: []string{"."}},
+			HoverProvider:      true,
+			DefinitionProvider: true,
+		},
+		ServerInfo: serverInfo{Name: "steampipe", Version: version.String()},
+	}
+}
+
+// load the workspace and publish its diagnostics
+// if the workspace fails to load, the previously loaded workspace is retained for completion
+func (s *Server) loadWorkspace() {
+	w, err := workspace.Load(s.workspacePath)
+	if err != nil {
+		s.publishDiagnostics(modvalidate.LoadErrorDiagnostics(err))
+		return
+	}
+	if s.workspace != nil {
+		s.workspace.Close()
+	}
+	s.workspace = w
+	s.index = newResourceIndex(w)
+	s.publishDiagnostics(modvalidate.ValidateWorkspace(w))
+}
+
+func (s *Server) completion(params *textDocumentPositionParams) *completionList {
+	text := s.documents[params.TextDocument.URI]
+	items := completions(text, offsetForPosition(text, params.Position), s.index)
+	if items == nil {
+		items = []*completionItem{}
+	}
+	return &completionList{Items: items}
+}
+
+func (s *Server) hover(params *textDocumentPositionParams) *hover {
+	resource, wordRange := s.resourceAt(params)
+	if resource == nil {
+		return nil
+	}
+	return &hover{
+		Contents: markupContent{Kind: "markdown", Value: resource.markdown()},
+		Range:    wordRange,
+	}
+}
+
+func (s *Server) definition(params *textDocumentPositionParams) *location {
+	resource, _ := s.resourceAt(params)
+	if resource == nil || resource.declRange == nil {
+		return nil
+	}
+	return &location{
+		URI:   pathToURI(resource.declRange.Filename),
+		Range: s.hclRange(resource.declRange),
+	}
+}
+
+// return the resource at the position of the document, and the range of its name
+// this is either a reference, e.g. query.q1, or the name of a block, e.g. "q1" in 'query "q1" {'
+func (s *Server) resourceAt(params *textDocumentPositionParams) (*resourceInfo, *textRange) {
+	text := s.documents[params.TextDocument.URI]
+	offset := offsetForPosition(text, params.Position)
+
+	// is the position within the name of a block
+	start := lineStart(text, offset)
+	if match := blockHeader.FindStringSubmatchIndex(text[start:]); match != nil && offset >= start+match[4] && offset <= start+match[5] {
+		blockType, blockName := text[start+match[2]:start+match[3]], text[start+match[4]:start+match[5]]
+		if blockType == modconfig.BlockTypeVariable {
+			blockType = "var"
+		}
+		if resource := s.index.lookup(fmt.Sprintf("%s.%s", blockType, blockName)); resource != nil {
+			return resource, &textRange{Start: positionForOffset(text, start+match[4]), End: positionForOffset(text, start+match[5])}
+		}
+		return nil, nil
+	}
+
+	word, wordStart := wordAt(text, offset)
+	resource := s.index.lookup(word)
+	if resource == nil {
+		return nil, nil
+	}
+	return resource, &textRange{Start: positionForOffset(text, wordStart), End: positionForOffset(text, wordStart+len(word))}
+}
+
+// return the text of a file - the open document if there is one, otherwise the file on disk
+func (s *Server) fileText(path string) string {
+	if text, ok := s.documents[pathToURI(path)]; ok {
+		return text
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
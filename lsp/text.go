@@ -0,0 +1,203 @@
+package lsp
+
+import (
+	"net/url"
+	"path/filepath"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// convert a file uri to a file path
+func uriToPath(uri string) string {
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme != "file" {
+		return uri
+	}
+	return filepath.FromSlash(u.Path)
+}
+
+// convert a file path to a file uri
+func pathToURI(path string) string {
+	return (&url.URL{Scheme: "file", Path: filepath.ToSlash(path)}).String()
+}
+
+// return the byte offset of an LSP position
+// LSP positions count characters in UTF-16 code units
+func offsetForPosition(text string, pos position) int {
+	offset := 0
+	for line := 0; line < pos.Line; line++ {
+		newline := strings.IndexByte(text[offset:], '\n')
+		if newline == -1 {
+			return len(text)
+		}
+		offset += newline + 1
+	}
+	for units := 0; units < pos.Character && offset < len(text); {
+		r, size := utf8.DecodeRuneInString(text[offset:])
+		if r == '\n' {
+			break
+		}
+		units += len(utf16.Encode([]rune{r}))
+		offset += size
+	}
+	return offset
+}
+
+// return the LSP position of a byte offset
+func positionForOffset(text string, offset int) position {
+	if offset > len(text) {
+		offset = len(text)
+	}
+	start := lineStart(text, offset)
+	return position{
+		Line:      strings.Count(text[:start], "\n"),
+		Character: len(utf16.Encode([]rune(text[start:offset]))),
+	}
+}
+
+func isWordChar(c byte) bool {
+	return c == '_' || c == '-' || c == '.' ||
+		(c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+// return the word containing the offset, i.e. a resource name or traversal such as query.q1 or var.region
+// returns the word and its start offset
+func wordAt(text string, offset int) (string, int) {
+	start, end := offset, offset
+	for start > 0 && isWordChar(text[start-1]) {
+		start--
+	}
+	for end < len(text) && isWordChar(text[end]) {
+		end++
+	}
+	return text[start:end], start
+}
+
+// editContext describes the position being edited within a document
+type editContext struct {
+	// the types of the blocks containing the position, outermost first
+	blocks []string
+	// is the position within an expression, i.e. within brackets or an object
+	inExpression bool
+	// is the position on the right hand side of an attribute assignment
+	inValue bool
+}
+
+// scan the text up to the offset to determine the edit context
+// this uses a simple scanner, rather than the hcl parser, as a document being edited is usually not valid
+func scanEditContext(text string, offset int) *editContext {
+	if offset > len(text) {
+		offset = len(text)
+	}
+	// the stack of open braces - block types for blocks, an empty string for objects
+	var braces []string
+	// the depth of open brackets and parentheses
+	brackets := 0
+
+	for i := 0; i < offset; i++ {
+		c := text[i]
+		switch {
+		case c == '#' || (c == '/' && i+1 < len(text) && text[i+1] == '/'):
+			// line comment - skip to the end of the line
+			i = skipTo(text, i, "\n", offset) - 1
+		case c == '/' && i+1 < len(text) && text[i+1] == '*':
+			i = skipTo(text, i+2, "*/", offset) - 1
+		case c == '"':
+			i = skipString(text, i+1, offset)
+		case c == '<' && strings.HasPrefix(text[i:], "<<"):
+			i = skipHeredoc(text, i, offset)
+		case c == '[' || c == '(':
+			brackets++
+		case c == ']' || c == ')':
+			if brackets > 0 {
+				brackets--
+			}
+		case c == '{':
+			// the block type is the first word of the line, e.g. 'control' in 'control "c1" {'
+			// an opening brace following an assignment, or within brackets, is an object
+			header := strings.Fields(text[lineStart(text, i):i])
+			if len(header) == 0 || brackets > 0 || strings.Contains(strings.Join(header, " "), "=") {
+				braces = append(braces, "")
+			} else {
+				braces = append(braces, header[0])
+			}
+		case c == '}':
+			if len(braces) > 0 {
+				braces = braces[:len(braces)-1]
+			}
+		}
+	}
+
+	ctx := &editContext{
+		inExpression: brackets > 0,
+		inValue:      strings.Contains(text[lineStart(text, offset):offset], "="),
+	}
+	for _, b := range braces {
+		if b == "" {
+			ctx.inExpression = true
+			continue
+		}
+		ctx.blocks = append(ctx.blocks, b)
+	}
+	return ctx
+}
+
+// return the offset of the start of the line containing the offset
+func lineStart(text string, offset int) int {
+	return strings.LastIndexByte(text[:offset], '\n') + 1
+}
+
+// return the offset after the next occurrence of the terminator, limited to the given offset
+func skipTo(text string, start int, terminator string, limit int) int {
+	idx := strings.Index(text[start:], terminator)
+	if idx == -1 || start+idx+len(terminator) > limit {
+		return limit
+	}
+	return start + idx + len(terminator)
+}
+
+// skip a quoted string, returning the offset of the closing quote (or the limit)
+func skipString(text string, start, limit int) int {
+	for i := start; i < limit; i++ {
+		switch text[i] {
+		case '\\':
+			i++
+		case '"', '\n':
+			return i
+		}
+	}
+	return limit
+}
+
+// skip a heredoc starting at the given offset, returning the offset of the end of the closing marker (or the limit)
+// if the text is not a heredoc, the offset is returned unchanged
+func skipHeredoc(text string, start, limit int) int {
+	lineEnd := strings.IndexByte(text[start:], '\n')
+	if lineEnd == -1 {
+		return start
+	}
+	marker := strings.TrimPrefix(strings.TrimPrefix(text[start:start+lineEnd], "<<"), "-")
+	marker = strings.TrimSpace(marker)
+	if marker == "" || strings.ContainsAny(marker, " \t\"{}[]()") {
+		return start
+	}
+	for i := start + lineEnd + 1; i < len(text); {
+		end := strings.IndexByte(text[i:], '\n')
+		line := text[i:]
+		if end != -1 {
+			line = text[i : i+end]
+		}
+		if strings.TrimSpace(line) == marker {
+			if i+len(line) > limit {
+				return limit
+			}
+			return i + len(line) - 1
+		}
+		if end == -1 {
+			break
+		}
+		i += end + 1
+	}
+	return limit
+}
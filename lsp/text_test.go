@@ -0,0 +1,59 @@
+package lsp
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type scanEditContextTest struct {
+	// the text, with the edit position marked by '|'
+	text     string
+	expected editContext
+}
+
+var testCasesScanEditContext = map[string]scanEditContextTest{
+	"top level": {
+		text:     "query \"q1\" {\n  sql = \"select 1\"\n}\n|",
+		expected: editContext{},
+	},
+	"block": {
+		text:     "control \"c1\" {\n  |\n}",
+		expected: editContext{blocks: []string{"control"}},
+	},
+	"nested block": {
+		text:     "mod \"m\" {\n  requires {\n    plugin \"aws\" {\n      |\n    }\n  }\n}",
+		expected: editContext{blocks: []string{"mod", "requires", "plugin"}},
+	},
+	"value": {
+		text:     "control \"c1\" {\n  query = |\n}",
+		expected: editContext{blocks: []string{"control"}, inValue: true},
+	},
+	"list": {
+		text:     "benchmark \"b1\" {\n  children = [\n    |\n  ]\n}",
+		expected: editContext{blocks: []string{"benchmark"}, inExpression: true},
+	},
+	"object": {
+		text:     "control \"c1\" {\n  tags = {\n    |\n  }\n}",
+		expected: editContext{blocks: []string{"control"}, inExpression: true},
+	},
+	"braces in string and comment": {
+		text:     "control \"c1\" {\n  title = \"{ }}\"\n  # }\n  |\n}",
+		expected: editContext{blocks: []string{"control"}},
+	},
+	"braces in heredoc": {
+		text:     "query \"q1\" {\n  sql = <<-EOQ\n    select '}}'\n  EOQ\n  |\n}",
+		expected: editContext{blocks: []string{"query"}},
+	},
+}
+
+func TestScanEditContext(t *testing.T) {
+	for name, test := range testCasesScanEditContext {
+		offset := strings.Index(test.text, "|")
+		text := test.text[:offset] + test.text[offset+1:]
+		ctx := scanEditContext(text, offset)
+		if !reflect.DeepEqual(*ctx, test.expected) {
+			t.Errorf("Test: '%s' FAILED : expected %+v, got %+v", name, test.expected, *ctx)
+		}
+	}
+}
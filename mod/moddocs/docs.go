@@ -0,0 +1,48 @@
+package moddocs
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/turbot/steampipe/workspace"
+)
+
+// Generate writes documentation for the workspace mod to the output directory
+// a markdown page is written for the mod index, each benchmark, control, query and variable, and the tag index
+// if html is set, an html page is also written for each
+// returns the paths of the files written
+func Generate(w *workspace.Workspace, outputDir string, html bool) ([]string, error) {
+	d := newDocSet(w.Mod)
+
+	var files []string
+	for _, p := range d.pages {
+		filePath := filepath.Join(outputDir, filepath.FromSlash(p.path)+markdownExtension)
+		if err := writePage(filePath, renderMarkdown(p)); err != nil {
+			return files, err
+		}
+		files = append(files, filePath)
+
+		if !html {
+			continue
+		}
+		content, err := renderHTML(p)
+		if err != nil {
+			return files, fmt.Errorf("failed to render html for '%s': %s", p.path, err.Error())
+		}
+		filePath = filepath.Join(outputDir, filepath.FromSlash(p.path)+htmlExtension)
+		if err := writePage(filePath, content); err != nil {
+			return files, err
+		}
+		files = append(files, filePath)
+	}
+	return files, nil
+}
+
+func writePage(filePath, content string) error {
+	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filePath, []byte(content), 0644)
+}
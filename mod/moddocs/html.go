@@ -0,0 +1,204 @@
+package moddocs
+
+import (
+	"bytes"
+	"html/template"
+	"strings"
+
+	"github.com/alecthomas/chroma"
+	chromahtml "github.com/alecthomas/chroma/formatters/html"
+	"github.com/alecthomas/chroma/lexers"
+	"github.com/alecthomas/chroma/styles"
+	"github.com/russross/blackfriday/v2"
+	"github.com/turbot/go-kit/types"
+)
+
+const htmlExtension = ".html"
+
+// htmlPage is the template data for a page, with all links resolved relative to the page
+type htmlPage struct {
+	Title         string
+	ResourceName  string
+	Description   string
+	Documentation template.HTML
+	Properties    [][2]string
+	Tags          []htmlTag
+	SQL           template.HTML
+	Params        []htmlParam
+	Sections      []htmlSection
+	IndexLink     string
+	Style         template.CSS
+}
+
+type htmlParam struct {
+	Name        string
+	Type        string
+	Default     string
+	Description string
+}
+
+type htmlTag struct {
+	Key   string
+	Value string
+	Href  string
+}
+
+type htmlSection struct {
+	Title  string
+	Anchor string
+	Links  []htmlLink
+}
+
+type htmlLink struct {
+	Title string
+	Href  string
+	Depth int
+}
+
+var htmlTemplate = template.Must(template.New("page").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", Helvetica, Arial, sans-serif; max-width: 960px; margin: 2em auto; padding: 0 1em; color: #24292e; }
+table { border-collapse: collapse; margin-bottom: 1em; }
+th, td { border: 1px solid #dfe2e5; padding: 4px 12px; text-align: left; }
+pre { padding: 1em; overflow: auto; background: #f6f8fa; }
+{{.Style}}
+</style>
+</head>
+<body>
+<p><a href="{{.IndexLink}}">Home</a></p>
+<h1>{{.Title}}</h1>
+{{if .ResourceName}}<p><code>{{.ResourceName}}</code></p>{{end}}
+{{if .Description}}<p>{{.Description}}</p>{{end}}
+{{if .Properties}}<table>
+<tr><th>Property</th><th>Value</th></tr>
+{{range .Properties}}<tr><td>{{index . 0}}</td><td>{{index . 1}}</td></tr>
+{{end}}</table>{{end}}
+{{if .Tags}}<h2>Tags</h2>
+<ul>
+{{range .Tags}}<li><a href="{{.Href}}"><code>{{.Key}} = {{.Value}}</code></a></li>
+{{end}}</ul>{{end}}
+{{if .SQL}}<h2>SQL</h2>
+{{.SQL}}{{end}}
+{{if .Params}}<h2>Params</h2>
+<table>
+<tr><th>Name</th><th>Type</th><th>Default</th><th>Description</th></tr>
+{{range .Params}}<tr><td>{{.Name}}</td><td>{{.Type}}</td><td>{{if .Default}}<code>{{.Default}}</code>{{end}}</td><td>{{.Description}}</td></tr>
+{{end}}</table>{{end}}
+{{range .Sections}}<h2{{if .Anchor}} id="{{.Anchor}}"{{end}}>{{.Title}}</h2>
+<ul>
+{{range .Links}}<li style="margin-left: {{.Depth}}em">{{if .Href}}<a href="{{.Href}}">{{.Title}}</a>{{else}}<code>{{.Title}}</code>{{end}}</li>
+{{end}}</ul>
+{{end}}
+{{if .Documentation}}<h2>Documentation</h2>
+{{.Documentation}}{{end}}
+</body>
+</html>
+`))
+
+// render a page as html
+func renderHTML(p *page) (string, error) {
+	data := &htmlPage{
+		Title:         p.title,
+		ResourceName:  p.resourceName,
+		Description:   p.description,
+		Documentation: renderDocumentation(p.documentation),
+		Properties:    p.properties,
+		IndexLink:     relativeLink(p.path, indexPage, htmlExtension),
+	}
+	for _, key := range sortedKeys(p.tags) {
+		value := p.tags[key]
+		data.Tags = append(data.Tags, htmlTag{Key: key, Value: value, Href: tagLink(p.path, key, value, htmlExtension)})
+	}
+	for _, param := range p.params {
+		data.Params = append(data.Params, htmlParam{
+			Name:        param.Name,
+			Type:        param.Type,
+			Default:     types.SafeString(param.Default),
+			Description: types.SafeString(param.Description),
+		})
+	}
+	for _, section := range p.sections {
+		if len(section.links) == 0 && len(section.tree) == 0 {
+			continue
+		}
+		s := htmlSection{Title: section.title, Anchor: section.anchor}
+		for _, l := range section.links {
+			s.Links = append(s.Links, newHTMLLink(p.path, l, 0))
+		}
+		s.Links = append(s.Links, flattenTree(p.path, section.tree, 0)...)
+		data.Sections = append(data.Sections, s)
+	}
+
+	if p.sql != "" {
+		sql, css, err := highlightSQL(strings.TrimSpace(p.sql))
+		if err != nil {
+			return "", err
+		}
+		data.SQL = sql
+		data.Style = css
+	}
+
+	var b bytes.Buffer
+	if err := htmlTemplate.Execute(&b, data); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+func newHTMLLink(fromPath string, l *link, depth int) htmlLink {
+	if l.path == "" {
+		return htmlLink{Title: l.name, Depth: depth}
+	}
+	return htmlLink{Title: l.title, Href: relativeLink(fromPath, l.path, htmlExtension), Depth: depth}
+}
+
+// flatten the tree into a list of links, with the depth of each link used for indentation
+func flattenTree(fromPath string, items []*treeItem, depth int) []htmlLink {
+	var res []htmlLink
+	for _, item := range items {
+		res = append(res, newHTMLLink(fromPath, item.link, depth))
+		res = append(res, flattenTree(fromPath, item.children, depth+1)...)
+	}
+	return res
+}
+
+// render the markdown documentation as html
+// raw html in the documentation is skipped and only links with safe protocols are rendered
+func renderDocumentation(documentation string) template.HTML {
+	documentation = strings.TrimSpace(documentation)
+	if documentation == "" {
+		return ""
+	}
+	renderer := blackfriday.NewHTMLRenderer(blackfriday.HTMLRendererParameters{
+		Flags: blackfriday.CommonHTMLFlags | blackfriday.SkipHTML | blackfriday.Safelink,
+	})
+	return template.HTML(blackfriday.Run([]byte(documentation), blackfriday.WithRenderer(renderer)))
+}
+
+// return the syntax highlighted html for the sql, and the css for its classes
+func highlightSQL(sql string) (template.HTML, template.CSS, error) {
+	lexer := lexers.Get("sql")
+	if lexer == nil {
+		lexer = lexers.Fallback
+	}
+	lexer = chroma.Coalesce(lexer)
+	style := styles.Get("github")
+	formatter := chromahtml.New(chromahtml.WithClasses(true))
+
+	iterator, err := lexer.Tokenise(nil, sql)
+	if err != nil {
+		return "", "", err
+	}
+	var html, css bytes.Buffer
+	if err := formatter.Format(&html, style, iterator); err != nil {
+		return "", "", err
+	}
+	if err := formatter.WriteCSS(&css, style); err != nil {
+		return "", "", err
+	}
+	return template.HTML(html.String()), template.CSS(css.String()), nil
+}
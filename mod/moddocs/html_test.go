@@ -0,0 +1,73 @@
+package moddocs
+
+import (
+	"strings"
+	"testing"
+)
+
+type renderHTMLTest struct {
+	page     *page
+	expected []string
+	// strings which must not be in the output
+	unexpected []string
+}
+
+var testCasesRenderHTML = map[string]renderHTMLTest{
+	"markdown documentation": {
+		page: &page{
+			path:          "controls/c1",
+			title:         "Control 1",
+			documentation: "## Overview\n\nChecks **all** buckets.\n\n<script>alert(1)</script>\n",
+		},
+		expected: []string{
+			"<h2>Overview</h2>",
+			"<strong>all</strong>",
+		},
+		unexpected: []string{
+			"## Overview",
+			"<script>",
+		},
+	},
+	"tag links": {
+		page: &page{
+			path:  "controls/c1",
+			title: "Control 1",
+			tags:  map[string]string{"service": "AWS/S3"},
+		},
+		expected: []string{
+			`<a href="../tags.html#tag-service-aws-s3">`,
+		},
+	},
+	"tag index anchors": {
+		page: &page{
+			path:  tagsPage,
+			title: "Tags",
+			sections: []*linkSection{
+				{title: "service = AWS/S3", anchor: tagAnchor("service", "AWS/S3"), links: []*link{{name: "control.c1", title: "Control 1", path: "controls/c1"}}},
+			},
+		},
+		expected: []string{
+			`<h2 id="tag-service-aws-s3">service = AWS/S3</h2>`,
+		},
+	},
+}
+
+func TestRenderHTML(t *testing.T) {
+	for name, test := range testCasesRenderHTML {
+		html, err := renderHTML(test.page)
+		if err != nil {
+			t.Errorf("Test: '%s' FAILED with unexpected error: %v", name, err)
+			continue
+		}
+		for _, s := range test.expected {
+			if !strings.Contains(html, s) {
+				t.Errorf("Test: '%s' FAILED : expected output to contain '%s', got:\n%s", name, s, html)
+			}
+		}
+		for _, s := range test.unexpected {
+			if strings.Contains(html, s) {
+				t.Errorf("Test: '%s' FAILED : expected output not to contain '%s', got:\n%s", name, s, html)
+			}
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package moddocs
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/turbot/go-kit/types"
+)
+
+const markdownExtension = ".md"
+
+// render a page as markdown
+func renderMarkdown(p *page) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n", escapeMarkdown(p.title))
+	if p.resourceName != "" {
+		fmt.Fprintf(&b, "`%s`\n\n", p.resourceName)
+	}
+	if p.description != "" {
+		fmt.Fprintf(&b, "%s\n\n", p.description)
+	}
+
+	if len(p.properties) > 0 {
+		b.WriteString("| Property | Value |\n| --- | --- |\n")
+		for _, property := range p.properties {
+			fmt.Fprintf(&b, "| %s | %s |\n", property[0], escapeTableCell(property[1]))
+		}
+		b.WriteString("\n")
+	}
+
+	if len(p.tags) > 0 {
+		b.WriteString("## Tags\n\n")
+		for _, key := range sortedKeys(p.tags) {
+			fmt.Fprintf(&b, "- [`%s = %s`](%s)\n", key, p.tags[key], tagLink(p.path, key, p.tags[key], markdownExtension))
+		}
+		b.WriteString("\n")
+	}
+
+	if p.sql != "" {
+		fmt.Fprintf(&b, "## SQL\n\n```sql\n%s\n```\n\n", strings.TrimSpace(p.sql))
+	}
+
+	if len(p.params) > 0 {
+		b.WriteString("## Params\n\n| Name | Type | Default | Description |\n| --- | --- | --- | --- |\n")
+		for _, param := range p.params {
+			defaultValue := ""
+			if param.Default != nil {
+				defaultValue = fmt.Sprintf("`%s`", *param.Default)
+			}
+			fmt.Fprintf(&b, "| %s | %s | %s | %s |\n", param.Name, param.Type, escapeTableCell(defaultValue), escapeTableCell(types.SafeString(param.Description)))
+		}
+		b.WriteString("\n")
+	}
+
+	for _, section := range p.sections {
+		if len(section.links) == 0 && len(section.tree) == 0 {
+			continue
+		}
+		if section.anchor != "" {
+			fmt.Fprintf(&b, "<a id=\"%s\"></a>\n\n", section.anchor)
+		}
+		fmt.Fprintf(&b, "## %s\n\n", escapeMarkdown(section.title))
+		for _, l := range section.links {
+			fmt.Fprintf(&b, "- %s\n", markdownLink(p.path, l))
+		}
+		writeMarkdownTree(&b, p.path, section.tree, 0)
+		b.WriteString("\n")
+	}
+
+	if p.documentation != "" {
+		fmt.Fprintf(&b, "## Documentation\n\n%s\n", strings.TrimSpace(p.documentation))
+	}
+	return strings.TrimRight(b.String(), "\n") + "\n"
+}
+
+func writeMarkdownTree(b *strings.Builder, fromPath string, items []*treeItem, depth int) {
+	for _, item := range items {
+		fmt.Fprintf(b, "%s- %s\n", strings.Repeat("  ", depth), markdownLink(fromPath, item.link))
+		writeMarkdownTree(b, fromPath, item.children, depth+1)
+	}
+}
+
+// return a markdown link to the resource page, or the resource name if it is not documented
+func markdownLink(fromPath string, l *link) string {
+	if l.path == "" {
+		return fmt.Sprintf("`%s`", l.name)
+	}
+	return fmt.Sprintf("[%s](%s)", escapeMarkdown(l.title), relativeLink(fromPath, l.path, markdownExtension))
+}
+
+// return the link from one page to another, relative to the folder of the source page
+func relativeLink(fromPath, toPath, extension string) string {
+	fromDir := path.Dir(fromPath)
+	var up []string
+	if fromDir != "." {
+		for range strings.Split(fromDir, "/") {
+			up = append(up, "..")
+		}
+	}
+	return path.Join(append(up, toPath)...) + extension
+}
+
+var markdownEscaper = strings.NewReplacer("[", `\[`, "]", `\]`, "*", `\*`, "_", `\_`)
+
+func escapeMarkdown(s string) string {
+	return markdownEscaper.Replace(s)
+}
+
+func escapeTableCell(s string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(s, "|", `\|`), "\n", " ")
+}
@@ -0,0 +1,38 @@
+package moddocs
+
+import (
+	"testing"
+)
+
+type relativeLinkTest struct {
+	from     string
+	to       string
+	expected string
+}
+
+var testCasesRelativeLink = map[string]relativeLinkTest{
+	"index to control": {
+		from:     "index",
+		to:       "controls/c1",
+		expected: "controls/c1.md",
+	},
+	"control to query": {
+		from:     "controls/c1",
+		to:       "queries/q1",
+		expected: "../queries/q1.md",
+	},
+	"control to tags": {
+		from:     "controls/c1",
+		to:       "tags",
+		expected: "../tags.md",
+	},
+}
+
+func TestRelativeLink(t *testing.T) {
+	for name, test := range testCasesRelativeLink {
+		link := relativeLink(test.from, test.to, markdownExtension)
+		if link != test.expected {
+			t.Errorf("Test: '%s' FAILED : expected '%s', got '%s'", name, test.expected, link)
+		}
+	}
+}
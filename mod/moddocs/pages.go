@@ -0,0 +1,398 @@
+package moddocs
+
+import (
+	"fmt"
+	"path"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/turbot/go-kit/helpers"
+	"github.com/turbot/go-kit/types"
+	"github.com/turbot/steampipe/steampipeconfig/modconfig"
+	"github.com/turbot/steampipe/steampipeconfig/parse"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// the folder for the pages of each resource type
+var resourceFolders = map[string]string{
+	modconfig.BlockTypeBenchmark: "benchmarks",
+	modconfig.BlockTypeControl:   "controls",
+	modconfig.BlockTypeQuery:     "queries",
+	"var":                        "variables",
+}
+
+const (
+	indexPage = "index"
+	tagsPage  = "tags"
+)
+
+// page is the content of a single documentation page
+type page struct {
+	// the path of the page, relative to the output folder and without extension, e.g. controls/my_control
+	path         string
+	title        string
+	resourceName string
+	description  string
+	// markdown documentation
+	documentation string
+	// property name/value pairs, e.g. severity
+	properties [][2]string
+	tags       map[string]string
+	sql        string
+	params     []*modconfig.ParamDef
+	// sections of links, e.g. children, references, referenced by
+	sections []*linkSection
+}
+
+type linkSection struct {
+	title string
+	// if set, the id of the section, used to link to it (used for the sections of the tag index)
+	anchor string
+	links  []*link
+	// if set, the links are rendered as a nested list (used for the benchmark hierarchy)
+	tree []*treeItem
+}
+
+// link is a link to a resource page - if path is empty, the resource is not documented (e.g. a dependency mod resource)
+type link struct {
+	name  string
+	title string
+	path  string
+}
+
+type treeItem struct {
+	link     *link
+	children []*treeItem
+}
+
+// docSet builds the pages for a mod
+type docSet struct {
+	mod   *modconfig.Mod
+	pages []*page
+	// map of resource name to link
+	links map[string]*link
+	// map of resource name to the names of the resources which reference it
+	referencedBy map[string][]string
+}
+
+func newDocSet(mod *modconfig.Mod) *docSet {
+	d := &docSet{
+		mod:          mod,
+		links:        make(map[string]*link),
+		referencedBy: make(map[string][]string),
+	}
+	d.buildLinks()
+	d.buildReferencedBy()
+
+	d.pages = append(d.pages, d.indexPage())
+	for _, name := range sortedKeys(d.mod.Benchmarks) {
+		d.pages = append(d.pages, d.benchmarkPage(d.mod.Benchmarks[name]))
+	}
+	for _, name := range sortedKeys(d.mod.Controls) {
+		d.pages = append(d.pages, d.controlPage(d.mod.Controls[name]))
+	}
+	for _, name := range sortedKeys(d.mod.Queries) {
+		d.pages = append(d.pages, d.queryPage(d.mod.Queries[name]))
+	}
+	for _, name := range sortedKeys(d.mod.Variables) {
+		d.pages = append(d.pages, d.variablePage(d.mod.Variables[name]))
+	}
+	d.pages = append(d.pages, d.tagsPage())
+	return d
+}
+
+// build a link for every documented resource
+func (d *docSet) buildLinks() {
+	add := func(name, title string) {
+		parsedName, err := modconfig.ParseResourceName(name)
+		if err != nil {
+			return
+		}
+		if title == "" {
+			title = name
+		}
+		d.links[name] = &link{
+			name:  name,
+			title: title,
+			path:  path.Join(resourceFolders[parsedName.ItemType], parsedName.Name),
+		}
+	}
+	for _, b := range d.mod.Benchmarks {
+		add(b.Name(), types.SafeString(b.Title))
+	}
+	for _, c := range d.mod.Controls {
+		add(c.Name(), types.SafeString(c.Title))
+	}
+	for _, q := range d.mod.Queries {
+		add(q.Name(), types.SafeString(q.Title))
+	}
+	for _, v := range d.mod.Variables {
+		add(v.Name(), "")
+	}
+}
+
+// build the reverse reference map
+func (d *docSet) buildReferencedBy() {
+	for _, reference := range d.allReferences() {
+		to := referenceTarget(reference.To)
+		if !helpers.StringSliceContains(d.referencedBy[to], reference.From) {
+			d.referencedBy[to] = append(d.referencedBy[to], reference.From)
+		}
+	}
+	for to := range d.referencedBy {
+		sort.Strings(d.referencedBy[to])
+	}
+}
+
+func (d *docSet) allReferences() []*modconfig.ResourceReference {
+	var references []*modconfig.ResourceReference
+	for _, q := range d.mod.Queries {
+		references = append(references, q.References...)
+	}
+	for _, c := range d.mod.Controls {
+		references = append(references, c.References...)
+	}
+	for _, b := range d.mod.Benchmarks {
+		references = append(references, b.References...)
+	}
+	return references
+}
+
+// return a link for the resource with the given name
+// resources which are not documented (e.g. those in dependency mods) have a link with no path
+func (d *docSet) link(name string) *link {
+	if l, ok := d.links[name]; ok {
+		return l
+	}
+	return &link{name: name, title: name}
+}
+
+func (d *docSet) indexPage() *page {
+	p := &page{
+		path:          indexPage,
+		title:         d.mod.GetTitle(),
+		resourceName:  d.mod.ShortName,
+		description:   d.mod.GetDescription(),
+		documentation: types.SafeString(d.mod.Documentation),
+		tags:          d.mod.GetTags(),
+	}
+	if p.title == "" {
+		p.title = d.mod.ShortName
+	}
+
+	// the benchmark hierarchy - top level benchmarks are those with no parent benchmark
+	var tree []*treeItem
+	for _, name := range sortedKeys(d.mod.Benchmarks) {
+		b := d.mod.Benchmarks[name]
+		if !d.hasParentBenchmark(b) {
+			tree = append(tree, d.benchmarkTree(b))
+		}
+	}
+	if len(tree) > 0 {
+		p.sections = append(p.sections, &linkSection{title: "Benchmarks", tree: tree})
+	}
+	p.sections = append(p.sections,
+		d.resourceSection("Controls", sortedKeys(d.mod.Controls)),
+		d.resourceSection("Queries", sortedKeys(d.mod.Queries)),
+		d.resourceSection("Variables", sortedKeys(d.mod.Variables)),
+		&linkSection{title: "Index", links: []*link{{name: tagsPage, title: "Tags", path: tagsPage}}},
+	)
+	return p
+}
+
+func (d *docSet) hasParentBenchmark(b *modconfig.Benchmark) bool {
+	for _, other := range d.mod.Benchmarks {
+		if helpers.StringSliceContains(other.ChildNameStrings, b.Name()) {
+			return true
+		}
+	}
+	return false
+}
+
+func (d *docSet) benchmarkTree(b *modconfig.Benchmark) *treeItem {
+	item := &treeItem{link: d.link(b.Name())}
+	for _, child := range b.GetChildren() {
+		if childBenchmark, ok := child.(*modconfig.Benchmark); ok && childBenchmark.Mod == d.mod {
+			item.children = append(item.children, d.benchmarkTree(childBenchmark))
+		} else {
+			item.children = append(item.children, &treeItem{link: d.link(child.Name())})
+		}
+	}
+	return item
+}
+
+func (d *docSet) benchmarkPage(b *modconfig.Benchmark) *page {
+	p := d.newResourcePage(b.Name(), b.Description, b.Documentation, b.Tags)
+	var children []string
+	for _, child := range b.GetChildren() {
+		children = append(children, child.Name())
+	}
+	p.sections = append(p.sections, d.resourceSection("Children", children))
+	p.sections = append(p.sections, d.resourceSection("Referenced By", d.referencedBy[b.Name()]))
+	return p
+}
+
+func (d *docSet) controlPage(c *modconfig.Control) *page {
+	p := d.newResourcePage(c.Name(), c.Description, c.Documentation, c.Tags)
+	if c.Severity != nil {
+		p.properties = append(p.properties, [2]string{"Severity", *c.Severity})
+	}
+	if c.SQL != nil {
+		p.sql = *c.SQL
+	}
+	p.params = c.Params
+
+	var benchmarks []string
+	for _, parent := range c.GetParentNames() {
+		if strings.HasPrefix(parent, modconfig.BlockTypeBenchmark+".") {
+			benchmarks = append(benchmarks, parent)
+		}
+	}
+	sort.Strings(benchmarks)
+	p.sections = append(p.sections,
+		d.resourceSection("Benchmarks", benchmarks),
+		d.resourceSection("References", d.referencesFrom(c.References)),
+	)
+	return p
+}
+
+func (d *docSet) queryPage(q *modconfig.Query) *page {
+	p := d.newResourcePage(q.Name(), q.Description, q.Documentation, q.Tags)
+	if q.SQL != nil {
+		p.sql = *q.SQL
+	}
+	p.params = q.Params
+	p.sections = append(p.sections,
+		d.resourceSection("References", d.referencesFrom(q.References)),
+		d.resourceSection("Referenced By", d.referencedBy[q.Name()]),
+	)
+	return p
+}
+
+func (d *docSet) variablePage(v *modconfig.Variable) *page {
+	// never document the value of a sensitive variable
+	v = v.Redacted()
+	p := d.newResourcePage(v.Name(), &v.Description, nil, nil)
+	p.properties = append(p.properties, [2]string{"Type", v.Type.FriendlyName()})
+	if v.Default != cty.NilVal {
+		if defaultValue, err := parse.CtyToJSON(v.Default); err == nil {
+			p.properties = append(p.properties, [2]string{"Default", defaultValue})
+		}
+	}
+	if v.Sensitive {
+		p.properties = append(p.properties, [2]string{"Sensitive", "true"})
+	}
+	p.sections = append(p.sections, d.resourceSection("Referenced By", d.referencedBy[v.Name()]))
+	return p
+}
+
+func (d *docSet) newResourcePage(name string, description, documentation *string, tags *map[string]string) *page {
+	l := d.link(name)
+	p := &page{
+		path:          l.path,
+		title:         l.title,
+		resourceName:  name,
+		description:   types.SafeString(description),
+		documentation: types.SafeString(documentation),
+	}
+	if tags != nil {
+		p.tags = *tags
+	}
+	return p
+}
+
+// the tag index - for each tag key and value, the resources with that tag
+func (d *docSet) tagsPage() *page {
+	p := &page{path: tagsPage, title: "Tags"}
+
+	tagged := make(map[string][]string)
+	anchors := make(map[string]string)
+	add := func(name string, tags *map[string]string) {
+		if tags == nil {
+			return
+		}
+		for k, v := range *tags {
+			key := fmt.Sprintf("%s = %s", k, v)
+			tagged[key] = append(tagged[key], name)
+			anchors[key] = tagAnchor(k, v)
+		}
+	}
+	for _, b := range d.mod.Benchmarks {
+		add(b.Name(), b.Tags)
+	}
+	for _, c := range d.mod.Controls {
+		add(c.Name(), c.Tags)
+	}
+	for _, q := range d.mod.Queries {
+		add(q.Name(), q.Tags)
+	}
+
+	for _, key := range sortedKeys(tagged) {
+		names := tagged[key]
+		sort.Strings(names)
+		section := d.resourceSection(key, names)
+		section.anchor = anchors[key]
+		p.sections = append(p.sections, section)
+	}
+	return p
+}
+
+var anchorInvalidChars = regexp.MustCompile(`[^a-z0-9]+`)
+
+// return the id of the section of the tag index for the given tag, e.g. tag-service-aws-ec2 for service = AWS/EC2
+func tagAnchor(key, value string) string {
+	anchor := anchorInvalidChars.ReplaceAllString(strings.ToLower(key+" "+value), "-")
+	return "tag-" + strings.Trim(anchor, "-")
+}
+
+// return the link from a page to the section of the tag index for the given tag
+func tagLink(fromPath, key, value, extension string) string {
+	return relativeLink(fromPath, tagsPage, extension) + "#" + tagAnchor(key, value)
+}
+
+func (d *docSet) resourceSection(title string, names []string) *linkSection {
+	section := &linkSection{title: title}
+	for _, name := range names {
+		section.links = append(section.links, d.link(name))
+	}
+	return section
+}
+
+// return the names of the resources referenced by the given references
+func (d *docSet) referencesFrom(references []*modconfig.ResourceReference) []string {
+	var names []string
+	for _, reference := range references {
+		to := referenceTarget(reference.To)
+		if !helpers.StringSliceContains(names, to) {
+			names = append(names, to)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// convert a reference into the name of the referenced resource
+// - references to resources in the workspace mod are returned unqualified, e.g. query.q1
+// - property references are returned as the resource name, e.g. var.config.region is returned as var.config
+func referenceTarget(to string) string {
+	parts := strings.Split(to, ".")
+	if len(parts) > 2 {
+		if _, ok := resourceFolders[parts[0]]; ok {
+			return strings.Join(parts[:2], ".")
+		}
+	}
+	return to
+}
+
+// return the keys of a map keyed by resource name, sorted
+func sortedKeys(m interface{}) []string {
+	keys := reflect.ValueOf(m).MapKeys()
+	res := make([]string, len(keys))
+	for i, k := range keys {
+		res[i] = k.String()
+	}
+	sort.Strings(res)
+	return res
+}
@@ -0,0 +1,128 @@
+package modfmt
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/sergi/go-diff/diffmatchpatch"
+)
+
+// the number of unchanged lines shown around each change
+const diffContext = 3
+
+// a line of a diff - op is one of ' ', '-', '+'
+type diffLine struct {
+	op   byte
+	text string
+}
+
+// Diff returns a unified diff of the original and formatted content of the file
+func (r *FileResult) Diff() string {
+	return unifiedDiff(r.Path, string(r.Original), string(r.Formatted))
+}
+
+func unifiedDiff(name, a, b string) string {
+	lines := diffLines(a, b)
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "--- %s\n+++ %s\n", name, name)
+
+	// line numbers (1 based) in a and b of the current line
+	aLine, bLine := 1, 1
+	for i := 0; i < len(lines); {
+		if lines[i].op == ' ' {
+			aLine++
+			bLine++
+			i++
+			continue
+		}
+
+		// build a hunk from this change, including any further changes within the context distance
+		start := i - diffContext
+		if start < 0 {
+			start = 0
+		}
+		end := i
+		for end < len(lines) {
+			// find the next change
+			next := end
+			for next < len(lines) && lines[next].op == ' ' {
+				next++
+			}
+			if next == len(lines) || next-end > 2*diffContext {
+				break
+			}
+			end = next
+			for end < len(lines) && lines[end].op != ' ' {
+				end++
+			}
+		}
+		end += diffContext
+		if end > len(lines) {
+			end = len(lines)
+		}
+
+		// the hunk starts with the leading context lines, which are before the current line numbers
+		hunkALine, hunkBLine := aLine-(i-start), bLine-(i-start)
+		var aCount, bCount int
+		var body strings.Builder
+		for _, line := range lines[start:end] {
+			fmt.Fprintf(&body, "%c%s\n", line.op, line.text)
+			if line.op != '+' {
+				aCount++
+			}
+			if line.op != '-' {
+				bCount++
+			}
+		}
+		fmt.Fprintf(&sb, "@@ -%s +%s @@\n%s", hunkRange(hunkALine, aCount), hunkRange(hunkBLine, bCount), body.String())
+
+		// update the line numbers for the lines of the hunk after the current line
+		for _, line := range lines[i:end] {
+			if line.op != '+' {
+				aLine++
+			}
+			if line.op != '-' {
+				bLine++
+			}
+		}
+		i = end
+	}
+	return sb.String()
+}
+
+// the range of a hunk - an empty range refers to the line before the hunk
+func hunkRange(start, count int) string {
+	if count == 0 {
+		start--
+	}
+	if count == 1 {
+		return fmt.Sprintf("%d", start)
+	}
+	return fmt.Sprintf("%d,%d", start, count)
+}
+
+// build a line based diff of a and b
+func diffLines(a, b string) []diffLine {
+	dmp := diffmatchpatch.New()
+	aChars, bChars, lineArray := dmp.DiffLinesToChars(a, b)
+	diffs := dmp.DiffCharsToLines(dmp.DiffMain(aChars, bChars, false), lineArray)
+
+	var res []diffLine
+	for _, d := range diffs {
+		op := byte(' ')
+		switch d.Type {
+		case diffmatchpatch.DiffDelete:
+			op = '-'
+		case diffmatchpatch.DiffInsert:
+			op = '+'
+		}
+		for _, text := range strings.SplitAfter(d.Text, "\n") {
+			if text == "" {
+				continue
+			}
+			res = append(res, diffLine{op: op, text: strings.TrimSuffix(text, "\n")})
+		}
+	}
+	return res
+}
@@ -0,0 +1,274 @@
+package modfmt
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/turbot/steampipe-plugin-sdk/plugin"
+	"github.com/turbot/steampipe/constants"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// the indent used for heredoc content, relative to the line containing the heredoc
+const heredocIndent = "  "
+
+// FileResult is the result of formatting a single file
+type FileResult struct {
+	Path      string
+	Original  []byte
+	Formatted []byte
+}
+
+// Changed returns whether formatting changed the file
+func (r *FileResult) Changed() bool {
+	return string(r.Original) != string(r.Formatted)
+}
+
+// Write writes the formatted content to the file, preserving its permissions
+func (r *FileResult) Write() error {
+	info, err := os.Stat(r.Path)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(r.Path, r.Formatted, info.Mode())
+}
+
+// IsHclFile returns whether the file is an HCL mod file which may be formatted
+// other mod files, e.g. sql files, are not formatted
+func IsHclFile(path string) bool {
+	ext := filepath.Ext(path)
+	return ext == constants.ModDataExtension || ext == constants.VariablesExtension
+}
+
+// FormatFile reads and formats the given file - the file is not written
+func FormatFile(path string) (*FileResult, error) {
+	src, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	formatted, err := Format(src, path)
+	if err != nil {
+		return nil, err
+	}
+	return &FileResult{Path: path, Original: src, Formatted: formatted}, nil
+}
+
+// Format returns the canonical formatting of the given HCL source
+// - the source is formatted using hclwrite
+// - heredocs are converted to indented heredocs, with their content indented one level deeper than the line they start on
+// the source must be valid HCL - an error is returned otherwise
+// an error is also returned if formatting would change the value of any attribute
+func Format(src []byte, filename string) ([]byte, error) {
+	if _, diags := hclsyntax.ParseConfig(src, filename, hcl.Pos{Line: 1, Column: 1}); diags.HasErrors() {
+		return nil, plugin.DiagsToError(fmt.Sprintf("Failed to parse %s", filename), diags)
+	}
+	formatted := []byte(normaliseHeredocs(hclwrite.Format(src), filename))
+	if err := checkValuesUnchanged(src, formatted, filename); err != nil {
+		return nil, err
+	}
+	return formatted, nil
+}
+
+// heredoc is the location of a heredoc in the source
+type heredoc struct {
+	// the zero based line numbers of the opening and closing markers
+	startLine int
+	endLine   int
+	// the opening marker, e.g. <<-EOQ
+	opener string
+	marker string
+	// is this an indented heredoc, i.e. <<-EOQ
+	indented bool
+}
+
+// find the top level heredocs of the source using the hcl lexer, so heredoc markers in comments,
+// strings and heredoc content are ignored
+func findHeredocs(src []byte, filename string) []*heredoc {
+	tokens, _ := hclsyntax.LexConfig(src, filename, hcl.Pos{Line: 1, Column: 1})
+	var res []*heredoc
+	var open []*heredoc
+	for _, token := range tokens {
+		switch token.Type {
+		case hclsyntax.TokenOHeredoc:
+			opener := strings.TrimSpace(string(token.Bytes))
+			open = append(open, &heredoc{
+				startLine: token.Range.Start.Line - 1,
+				opener:    opener,
+				marker:    strings.TrimLeft(opener, "<-"),
+				indented:  strings.HasPrefix(opener, "<<-"),
+			})
+		case hclsyntax.TokenCHeredoc:
+			if len(open) == 0 {
+				continue
+			}
+			h := open[len(open)-1]
+			open = open[:len(open)-1]
+			// heredocs nested in the interpolations of another heredoc are part of its content
+			if len(open) == 0 {
+				h.endLine = token.Range.Start.Line - 1
+				res = append(res, h)
+			}
+		}
+	}
+	return res
+}
+
+// normalise the indentation of all heredocs
+// the common leading whitespace of the content is replaced, so relative indentation is preserved
+// - the content of an indented heredoc is reindented, which does not change its value as the common
+// indentation of an indented heredoc is ignored
+// - a standard heredoc is only converted to an indented heredoc if its content has no common indentation,
+// otherwise the conversion would change its value
+func normaliseHeredocs(src []byte, filename string) string {
+	lines := strings.Split(string(src), "\n")
+	var res []string
+	next := 0
+	for _, h := range findHeredocs(src, filename) {
+		res = append(res, lines[next:h.startLine]...)
+		next = h.endLine + 1
+
+		line := lines[h.startLine]
+		content := lines[h.startLine+1 : h.endLine]
+		if !strings.HasSuffix(line, h.opener) || (!h.indented && commonIndentWidth(content) > 0) {
+			res = append(res, lines[h.startLine:next]...)
+			continue
+		}
+		indent := leadingWhitespace(line)
+		res = append(res, fmt.Sprintf("%s<<-%s", strings.TrimSuffix(line, h.opener), h.marker))
+		res = append(res, reindent(content, indent+heredocIndent)...)
+		res = append(res, indent+h.marker)
+	}
+	res = append(res, lines[next:]...)
+	return strings.Join(res, "\n")
+}
+
+// remove the common leading whitespace of the lines and indent them with the given indent
+// only the leading whitespace is changed - as for the value of an indented heredoc, lines containing
+// only whitespace are left unchanged and do not count towards the common leading whitespace
+func reindent(lines []string, indent string) []string {
+	common := ""
+	first := true
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		lineIndent := leadingWhitespace(line)
+		if first {
+			common = lineIndent
+			first = false
+			continue
+		}
+		for !strings.HasPrefix(lineIndent, common) {
+			common = common[:len(common)-1]
+		}
+	}
+
+	res := make([]string, len(lines))
+	for i, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			res[i] = line
+			continue
+		}
+		res[i] = indent + line[len(common):]
+	}
+	return res
+}
+
+// return the number of whitespace characters which an indented heredoc would remove from the start of each line
+func commonIndentWidth(lines []string) int {
+	width := -1
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		if lineWidth := utf8.RuneCountInString(leadingWhitespace(line)); width == -1 || lineWidth < width {
+			width = lineWidth
+		}
+	}
+	if width == -1 {
+		return 0
+	}
+	return width
+}
+
+func leadingWhitespace(line string) string {
+	return line[:len(line)-len(strings.TrimLeftFunc(line, unicode.IsSpace))]
+}
+
+// attributeValue is the literal values of an attribute expression, including the content of heredocs and templates
+type attributeValue struct {
+	name   string
+	rng    hcl.Range
+	values []cty.Value
+}
+
+// return an error if any attribute of the formatted source has a different value to the original
+func checkValuesUnchanged(original, formatted []byte, filename string) error {
+	originalFile, _ := hclsyntax.ParseConfig(original, filename, hcl.Pos{Line: 1, Column: 1})
+	formattedFile, diags := hclsyntax.ParseConfig(formatted, filename, hcl.Pos{Line: 1, Column: 1})
+	if diags.HasErrors() {
+		return plugin.DiagsToError(fmt.Sprintf("Formatting %s produced invalid HCL - the file has not been formatted", filename), diags)
+	}
+
+	originalValues := make(map[string]*attributeValue)
+	attributeValues(originalFile.Body.(*hclsyntax.Body), "", originalValues)
+	formattedValues := make(map[string]*attributeValue)
+	attributeValues(formattedFile.Body.(*hclsyntax.Body), "", formattedValues)
+
+	for _, key := range sortedKeys(originalValues) {
+		o := originalValues[key]
+		if f, ok := formattedValues[key]; !ok || !valuesEqual(o.values, f.values) {
+			return fmt.Errorf("formatting would change the value of '%s' (%s) - %s has not been formatted", o.name, o.rng, filename)
+		}
+	}
+	return nil
+}
+
+// add the values of all attributes of the body and its nested blocks to the map, keyed by the path of the attribute
+func attributeValues(body *hclsyntax.Body, path string, res map[string]*attributeValue) {
+	for name, attr := range body.Attributes {
+		v := &attributeValue{name: name, rng: attr.SrcRange}
+		hclsyntax.VisitAll(attr.Expr, func(node hclsyntax.Node) hcl.Diagnostics {
+			if literal, ok := node.(*hclsyntax.LiteralValueExpr); ok {
+				v.values = append(v.values, literal.Val)
+			}
+			return nil
+		})
+		res[path+"/"+name] = v
+	}
+	// include the index of the block in the path, as blocks may have the same type and labels
+	for i, block := range body.Blocks {
+		blockPath := fmt.Sprintf("%s/%s[%d]", path, strings.Join(append([]string{block.Type}, block.Labels...), "."), i)
+		attributeValues(block.Body, blockPath, res)
+	}
+}
+
+func valuesEqual(a, b []cty.Value) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !a[i].RawEquals(b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func sortedKeys(m map[string]*attributeValue) []string {
+	var res []string
+	for k := range m {
+		res = append(res, k)
+	}
+	sort.Strings(res)
+	return res
+}
@@ -0,0 +1,156 @@
+package modfmt
+
+import (
+	"testing"
+)
+
+type formatTest struct {
+	source   string
+	expected string
+}
+
+var testCasesFormat = map[string]formatTest{
+	"attribute alignment": {
+		source: `query "q1" {
+title="Q1"
+    description = "the query"
+}
+`,
+		expected: `query "q1" {
+  title       = "Q1"
+  description = "the query"
+}
+`,
+	},
+	"heredoc": {
+		source: `query "q1" {
+  sql = <<EOQ
+select
+  *
+from foo
+
+where x = 1  ` + `
+EOQ
+}
+`,
+		// trailing whitespace is part of the value, so is kept
+		expected: `query "q1" {
+  sql = <<-EOQ
+    select
+      *
+    from foo
+
+    where x = 1  ` + `
+  EOQ
+}
+`,
+	},
+	"heredoc with common indentation": {
+		// converting to an indented heredoc would remove the common indentation from the value
+		source: `query "q1" {
+  sql = <<EOQ
+    select 1
+EOQ
+}
+`,
+		expected: `query "q1" {
+  sql = <<EOQ
+    select 1
+EOQ
+}
+`,
+	},
+	"heredoc marker in comment": {
+		source: `query "q1" {
+  # sql = <<-EOQ
+  description = "the query"
+  sql = <<-EOQ
+      select 1
+  EOQ
+}
+`,
+		expected: `query "q1" {
+  # sql = <<-EOQ
+  description = "the query"
+  sql         = <<-EOQ
+    select 1
+  EOQ
+}
+`,
+	},
+	"indented heredoc": {
+		source: `control "c1" {
+    sql = <<-EOQ
+            select 1
+    EOQ
+}
+`,
+		expected: `control "c1" {
+  sql = <<-EOQ
+    select 1
+  EOQ
+}
+`,
+	},
+	"unchanged": {
+		source: `query "q1" {
+  sql = "select 1"
+}
+`,
+		expected: `query "q1" {
+  sql = "select 1"
+}
+`,
+	},
+}
+
+func TestFormat(t *testing.T) {
+	for name, test := range testCasesFormat {
+		formatted, err := Format([]byte(test.source), "test.sp")
+		if err != nil {
+			t.Errorf("Test: '%s' FAILED : unexpected error %v", name, err)
+			continue
+		}
+		if string(formatted) != test.expected {
+			t.Errorf("Test: '%s' FAILED : expected:\n%s\ngot:\n%s", name, test.expected, string(formatted))
+			continue
+		}
+		// formatting must be idempotent
+		reformatted, err := Format(formatted, "test.sp")
+		if err != nil || string(reformatted) != string(formatted) {
+			t.Errorf("Test: '%s' FAILED : formatting is not idempotent", name)
+		}
+	}
+}
+
+type checkValuesUnchangedTest struct {
+	original    string
+	formatted   string
+	expectError bool
+}
+
+var testCasesCheckValuesUnchanged = map[string]checkValuesUnchangedTest{
+	"reindented heredoc": {
+		original:  "query \"q1\" {\n  sql = <<-EOQ\n        select 1\n  EOQ\n}\n",
+		formatted: "query \"q1\" {\n  sql = <<-EOQ\n    select 1\n  EOQ\n}\n",
+	},
+	"trailing whitespace removed": {
+		original:    "query \"q1\" {\n  sql = <<-EOQ\n    select 1  \n  EOQ\n}\n",
+		formatted:   "query \"q1\" {\n  sql = <<-EOQ\n    select 1\n  EOQ\n}\n",
+		expectError: true,
+	},
+	"common indentation removed": {
+		original:    "query \"q1\" {\n  sql = <<EOQ\n    select 1\nEOQ\n}\n",
+		formatted:   "query \"q1\" {\n  sql = <<-EOQ\n    select 1\n  EOQ\n}\n",
+		expectError: true,
+	},
+}
+
+func TestCheckValuesUnchanged(t *testing.T) {
+	for name, test := range testCasesCheckValuesUnchanged {
+		err := checkValuesUnchanged([]byte(test.original), []byte(test.formatted), "test.sp")
+		if (err != nil) != test.expectError {
+			t.Errorf("Test: '%s' FAILED : expected error %v, got %v", name, test.expectError, err)
+		}
+	}
+}
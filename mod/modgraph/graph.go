@@ -0,0 +1,361 @@
+package modgraph
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/turbot/go-kit/helpers"
+	"github.com/turbot/go-kit/types"
+	"github.com/turbot/steampipe/steampipeconfig/modconfig"
+	"github.com/turbot/steampipe/workspace"
+)
+
+// edge types
+const (
+	// EdgeTypeReference is an edge from a resource to a resource it references, e.g. control -> query
+	EdgeTypeReference = "reference"
+	// EdgeTypeChild is an edge from a parent to its child in the mod tree, e.g. benchmark -> control
+	EdgeTypeChild = "child"
+	// EdgeTypeRequires is an edge from a mod to a mod it requires
+	EdgeTypeRequires = "requires"
+)
+
+// the resource types which may be the target of a reference
+var referenceTypes = []string{
+	"var",
+	"local",
+	modconfig.BlockTypeQuery,
+	modconfig.BlockTypeControl,
+	modconfig.BlockTypeBenchmark,
+	modconfig.BlockTypeReport,
+	modconfig.BlockTypePanel,
+}
+
+// Node is a resource in the dependency graph
+type Node struct {
+	// the node id - the resource name qualified with the mod name, e.g. aws_compliance.query.s3_bucket_versioning
+	ID string `json:"id"`
+	// the resource name, e.g. query.s3_bucket_versioning
+	Name  string `json:"name"`
+	Type  string `json:"type"`
+	Mod   string `json:"mod"`
+	Title string `json:"title,omitempty"`
+}
+
+// Edge is a dependency between 2 nodes
+type Edge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	Type string `json:"type"`
+	// for reference edges, the attribute containing the reference
+	// for requires edges, the required version
+	Label string `json:"label,omitempty"`
+}
+
+// Graph is the dependency graph of the resources of a workspace
+type Graph struct {
+	// the name of the workspace mod
+	ModName string  `json:"mod"`
+	Nodes   []*Node `json:"nodes"`
+	Edges   []*Edge `json:"edges"`
+
+	nodeMap map[string]*Node
+	edgeMap map[string]bool
+}
+
+func newGraph(modName string) *Graph {
+	return &Graph{
+		ModName: modName,
+		Nodes:   []*Node{},
+		Edges:   []*Edge{},
+		nodeMap: make(map[string]*Node),
+		edgeMap: make(map[string]bool),
+	}
+}
+
+// Build builds the dependency graph of the workspace
+// this includes the resources of the workspace mod, and any resources of dependency mods which they depend on
+func Build(w *workspace.Workspace) *Graph {
+	g := buildAll(w)
+
+	// only include dependency mod resources which are reachable from the workspace mod
+	var roots []string
+	for _, node := range g.Nodes {
+		if node.Mod == w.Mod.ShortName {
+			roots = append(roots, node.ID)
+		}
+	}
+	return g.subgraph(roots, false)
+}
+
+// BuildForResource builds the dependency graph for a single resource of the workspace
+// this includes the resources it depends on, and the resources which depend on it
+func BuildForResource(w *workspace.Workspace, resourceName string) (*Graph, error) {
+	g := buildAll(w)
+
+	id, ok := g.resolveName(resourceName)
+	if !ok {
+		return nil, fmt.Errorf("resource '%s' not found", resourceName)
+	}
+	return g.subgraph([]string{id}, true), nil
+}
+
+// build the graph of all resources of the workspace mod and all dependency mods
+func buildAll(w *workspace.Workspace) *Graph {
+	g := newGraph(w.Mod.ShortName)
+
+	mods := []*modconfig.Mod{w.Mod}
+	var depNames []string
+	for name := range w.Mods {
+		depNames = append(depNames, name)
+	}
+	sort.Strings(depNames)
+	for _, name := range depNames {
+		mods = append(mods, w.Mods[name])
+	}
+
+	for _, mod := range mods {
+		g.addMod(mod)
+	}
+	for _, mod := range mods {
+		g.addModEdges(mod, w.Mods)
+	}
+
+	g.sort()
+	return g
+}
+
+// add nodes for the mod and all its resources
+func (g *Graph) addMod(mod *modconfig.Mod) {
+	g.addNode(mod.ShortName, modName(mod), mod.GetTitle())
+	for _, q := range mod.Queries {
+		g.addNode(mod.ShortName, q.Name(), types.SafeString(q.Title))
+	}
+	for _, c := range mod.Controls {
+		g.addNode(mod.ShortName, c.Name(), types.SafeString(c.Title))
+	}
+	for _, b := range mod.Benchmarks {
+		g.addNode(mod.ShortName, b.Name(), types.SafeString(b.Title))
+	}
+	for _, r := range mod.Reports {
+		g.addNode(mod.ShortName, r.Name(), types.SafeString(r.Title))
+	}
+	for _, p := range mod.Panels {
+		g.addNode(mod.ShortName, p.Name(), types.SafeString(p.Title))
+	}
+	for _, v := range mod.Variables {
+		g.addNode(mod.ShortName, v.Name(), "")
+	}
+	for _, l := range mod.Locals {
+		g.addNode(mod.ShortName, l.Name(), "")
+	}
+	for _, t := range mod.Tests {
+		g.addNode(mod.ShortName, t.Name(), types.SafeString(t.Title))
+	}
+}
+
+func (g *Graph) addNode(modName, name, title string) {
+	id := nodeID(modName, name)
+	parsedName, _ := modconfig.ParseResourceName(name)
+	g.nodeMap[id] = &Node{
+		ID:    id,
+		Name:  name,
+		Type:  parsedName.ItemType,
+		Mod:   modName,
+		Title: title,
+	}
+}
+
+// add the reference, child and requires edges for the mod and its resources
+func (g *Graph) addModEdges(mod *modconfig.Mod, dependencyMods modconfig.ModMap) {
+	modID := modName(mod)
+
+	// requires edges
+	if mod.Requires != nil {
+		for _, requiredMod := range mod.Requires.Mods {
+			// if the dependency mod is loaded, use its mod node - otherwise add a node for the dependency
+			toID := fmt.Sprintf("mod.%s", requiredMod.Name)
+			if depMod, ok := dependencyMods[requiredMod.Name]; ok {
+				toID = modName(depMod)
+			} else if _, ok := g.nodeMap[toID]; !ok {
+				g.nodeMap[toID] = &Node{ID: toID, Name: requiredMod.Name, Type: modconfig.BlockTypeMod, Mod: requiredMod.Name}
+			}
+			g.addEdge(modID, toID, EdgeTypeRequires, requiredMod.VersionString)
+		}
+	}
+
+	// child edges
+	// (a reference from a parent to its child, e.g. in the benchmark 'children' attribute, is shown as a child edge)
+	childEdges := make(map[string]bool)
+	var parents []modconfig.ModTreeItem
+	for _, b := range mod.Benchmarks {
+		parents = append(parents, b)
+	}
+	for _, r := range mod.Reports {
+		parents = append(parents, r)
+	}
+	for _, p := range mod.Panels {
+		parents = append(parents, p)
+	}
+	for _, parent := range parents {
+		for _, child := range parent.GetChildren() {
+			childModName := mod.ShortName
+			if resource, ok := child.(modconfig.HclResource); ok && resource.GetMod() != nil {
+				childModName = resource.GetMod().ShortName
+			}
+			from, to := nodeID(mod.ShortName, parent.Name()), nodeID(childModName, child.Name())
+			g.addEdge(from, to, EdgeTypeChild, "")
+			childEdges[from+"|"+to] = true
+		}
+	}
+
+	// reference edges
+	references := append([]*modconfig.ResourceReference{}, mod.References...)
+	for _, q := range mod.Queries {
+		references = append(references, q.References...)
+	}
+	for _, c := range mod.Controls {
+		references = append(references, c.References...)
+	}
+	for _, b := range mod.Benchmarks {
+		references = append(references, b.References...)
+	}
+	for _, reference := range references {
+		toID, ok := referenceTargetID(mod.ShortName, reference.To)
+		fromID := nodeID(mod.ShortName, reference.From)
+		if !ok || childEdges[fromID+"|"+toID] {
+			continue
+		}
+		g.addEdge(fromID, toID, EdgeTypeReference, reference.Attribute)
+	}
+	// tests reference their control
+	for _, t := range mod.Tests {
+		if toID, ok := referenceTargetID(mod.ShortName, t.ControlName); ok {
+			g.addEdge(nodeID(mod.ShortName, t.Name()), toID, EdgeTypeReference, "control")
+		}
+	}
+}
+
+func (g *Graph) addEdge(from, to, edgeType, label string) {
+	// only add edges between known nodes - ignore self references
+	if _, ok := g.nodeMap[from]; !ok || from == to {
+		return
+	}
+	if _, ok := g.nodeMap[to]; !ok {
+		return
+	}
+	key := strings.Join([]string{from, to, edgeType, label}, "|")
+	if g.edgeMap[key] {
+		return
+	}
+	g.edgeMap[key] = true
+	g.Edges = append(g.Edges, &Edge{From: from, To: to, Type: edgeType, Label: label})
+}
+
+// build a subgraph containing the given nodes and all nodes they depend on
+// if includeDependents is set, also include all nodes which depend on the given nodes
+func (g *Graph) subgraph(roots []string, includeDependents bool) *Graph {
+	include := make(map[string]bool)
+	g.walk(roots, include, func(e *Edge) (string, string) { return e.From, e.To })
+	if includeDependents {
+		dependents := make(map[string]bool)
+		g.walk(roots, dependents, func(e *Edge) (string, string) { return e.To, e.From })
+		for id := range dependents {
+			include[id] = true
+		}
+	}
+
+	res := newGraph(g.ModName)
+	for id := range include {
+		res.nodeMap[id] = g.nodeMap[id]
+	}
+	for _, e := range g.Edges {
+		if include[e.From] && include[e.To] {
+			res.addEdge(e.From, e.To, e.Type, e.Label)
+		}
+	}
+	res.sort()
+	return res
+}
+
+// add the given nodes and all nodes reachable from them to the visited map
+// the direction function returns the source and target of an edge, for the direction being walked
+func (g *Graph) walk(roots []string, visited map[string]bool, direction func(*Edge) (string, string)) {
+	adjacent := make(map[string][]string)
+	for _, e := range g.Edges {
+		from, to := direction(e)
+		adjacent[from] = append(adjacent[from], to)
+	}
+	pending := append([]string{}, roots...)
+	for len(pending) > 0 {
+		id := pending[0]
+		pending = pending[1:]
+		if visited[id] {
+			continue
+		}
+		visited[id] = true
+		pending = append(pending, adjacent[id]...)
+	}
+}
+
+// resolve a resource name to a node id
+// the name may be unqualified (in which case it refers to a workspace mod resource) or qualified by mod
+func (g *Graph) resolveName(name string) (string, bool) {
+	for _, id := range []string{nodeID(g.ModName, name), name, nodeID(name, fmt.Sprintf("mod.%s", name))} {
+		if _, ok := g.nodeMap[id]; ok {
+			return id, true
+		}
+	}
+	return "", false
+}
+
+// order the nodes by id, and the edges by source and target
+func (g *Graph) sort() {
+	g.Nodes = make([]*Node, 0, len(g.nodeMap))
+	for _, node := range g.nodeMap {
+		g.Nodes = append(g.Nodes, node)
+	}
+	sort.Slice(g.Nodes, func(i, j int) bool {
+		return g.Nodes[i].ID < g.Nodes[j].ID
+	})
+	sort.SliceStable(g.Edges, func(i, j int) bool {
+		a, b := g.Edges[i], g.Edges[j]
+		if a.From != b.From {
+			return a.From < b.From
+		}
+		if a.To != b.To {
+			return a.To < b.To
+		}
+		return a.Type < b.Type
+	})
+}
+
+// return the name of a mod node, e.g. mod.aws_compliance
+// NOTE: Mod.Name() is not used as this includes the version of dependency mods
+func modName(mod *modconfig.Mod) string {
+	return fmt.Sprintf("mod.%s", mod.ShortName)
+}
+
+// return the node id for a resource - the resource name qualified by the mod name
+// for mods, this is the mod name, e.g. mod.aws_compliance
+func nodeID(modName, name string) string {
+	if strings.HasPrefix(name, "mod.") {
+		return name
+	}
+	return fmt.Sprintf("%s.%s", modName, name)
+}
+
+// return the node id of the target of a reference made from a resource of the given mod
+// references may be to a resource in the same mod, e.g. query.q1, or a dependency mod, e.g. m1.query.q1
+// property references, e.g. var.config.region, refer to the resource node
+func referenceTargetID(modName, to string) (string, bool) {
+	parts := strings.Split(to, ".")
+	if len(parts) >= 2 && helpers.StringSliceContains(referenceTypes, parts[0]) {
+		return nodeID(modName, strings.Join(parts[:2], ".")), true
+	}
+	if len(parts) >= 3 && helpers.StringSliceContains(referenceTypes, parts[1]) {
+		return strings.Join(parts[:3], "."), true
+	}
+	return "", false
+}
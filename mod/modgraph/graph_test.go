@@ -0,0 +1,208 @@
+package modgraph
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/turbot/steampipe/workspace"
+)
+
+type referenceTargetTest struct {
+	to         string
+	expectedID string
+	expectedOk bool
+}
+
+var testCasesReferenceTarget = map[string]referenceTargetTest{
+	"query": {
+		to:         "query.q1",
+		expectedID: "m1.query.q1",
+		expectedOk: true,
+	},
+	"variable property": {
+		to:         "var.config.region",
+		expectedID: "m1.var.config",
+		expectedOk: true,
+	},
+	"dependency mod query": {
+		to:         "dep.query.shared",
+		expectedID: "dep.query.shared",
+		expectedOk: true,
+	},
+	"param": {
+		to:         "param.p1",
+		expectedOk: false,
+	},
+}
+
+func TestReferenceTargetID(t *testing.T) {
+	for name, test := range testCasesReferenceTarget {
+		id, ok := referenceTargetID("m1", test.to)
+		if ok != test.expectedOk || id != test.expectedID {
+			t.Errorf("Test: '%s' FAILED : expected '%s' (%v), got '%s' (%v)", name, test.expectedID, test.expectedOk, id, ok)
+		}
+	}
+}
+
+type buildGraphTest struct {
+	// the resource to build the graph for - if empty, the graph of the workspace is built
+	resource        string
+	expectedDOT     string
+	expectedMermaid string
+	// compact json, or "ERROR"
+	expectedJSON string
+}
+
+var testCasesBuildGraph = map[string]buildGraphTest{
+	"workspace": {
+		expectedDOT: `digraph "mod.m1" {
+  rankdir=LR;
+  "dep.query.shared" [label="dep.query.shared", shape=cylinder];
+  "m1.benchmark.b1" [label="benchmark.b1", shape=box3d];
+  "m1.control.c1" [label="control.c1", shape=box];
+  "m1.control.c2" [label="control.c2", shape=box];
+  "m1.query.q1" [label="query.q1", shape=cylinder];
+  "mod.dep" [label="mod.dep", shape=folder];
+  "mod.m1" [label="mod.m1", shape=folder];
+  "m1.benchmark.b1" -> "m1.control.c1" [style=dashed];
+  "m1.benchmark.b1" -> "m1.control.c2" [style=dashed];
+  "m1.control.c1" -> "m1.query.q1" [label="query"];
+  "m1.control.c2" -> "dep.query.shared" [label="query"];
+  "mod.m1" -> "mod.dep" [style=bold, label="1.0"];
+}
+`,
+		expectedMermaid: `graph LR
+  n0["dep.query.shared"]
+  n1["benchmark.b1"]
+  n2["control.c1"]
+  n3["control.c2"]
+  n4["query.q1"]
+  n5["mod.dep"]
+  n6["mod.m1"]
+  n1 -.-> n2
+  n1 -.-> n3
+  n2 -->|"query"| n4
+  n3 -->|"query"| n0
+  n6 ==>|"1.0"| n5
+`,
+		expectedJSON: `{"mod":"m1","nodes":[{"id":"dep.query.shared","name":"query.shared","type":"query","mod":"dep"},{"id":"m1.benchmark.b1","name":"benchmark.b1","type":"benchmark","mod":"m1","title":"B1"},{"id":"m1.control.c1","name":"control.c1","type":"control","mod":"m1","title":"C1"},{"id":"m1.control.c2","name":"control.c2","type":"control","mod":"m1"},{"id":"m1.query.q1","name":"query.q1","type":"query","mod":"m1","title":"Q1"},{"id":"mod.dep","name":"mod.dep","type":"mod","mod":"dep","title":"Dep"},{"id":"mod.m1","name":"mod.m1","type":"mod","mod":"m1","title":"M1"}],"edges":[{"from":"m1.benchmark.b1","to":"m1.control.c1","type":"child"},{"from":"m1.benchmark.b1","to":"m1.control.c2","type":"child"},{"from":"m1.control.c1","to":"m1.query.q1","type":"reference","label":"query"},{"from":"m1.control.c2","to":"dep.query.shared","type":"reference","label":"query"},{"from":"mod.m1","to":"mod.dep","type":"requires","label":"1.0"}]}`,
+	},
+	"resource dependents": {
+		resource: "query.q1",
+		expectedDOT: `digraph "mod.m1" {
+  rankdir=LR;
+  "m1.benchmark.b1" [label="benchmark.b1", shape=box3d];
+  "m1.control.c1" [label="control.c1", shape=box];
+  "m1.query.q1" [label="query.q1", shape=cylinder];
+  "m1.benchmark.b1" -> "m1.control.c1" [style=dashed];
+  "m1.control.c1" -> "m1.query.q1" [label="query"];
+}
+`,
+		expectedMermaid: `graph LR
+  n0["benchmark.b1"]
+  n1["control.c1"]
+  n2["query.q1"]
+  n0 -.-> n1
+  n1 -->|"query"| n2
+`,
+		expectedJSON: `{"mod":"m1","nodes":[{"id":"m1.benchmark.b1","name":"benchmark.b1","type":"benchmark","mod":"m1","title":"B1"},{"id":"m1.control.c1","name":"control.c1","type":"control","mod":"m1","title":"C1"},{"id":"m1.query.q1","name":"query.q1","type":"query","mod":"m1","title":"Q1"}],"edges":[{"from":"m1.benchmark.b1","to":"m1.control.c1","type":"child"},{"from":"m1.control.c1","to":"m1.query.q1","type":"reference","label":"query"}]}`,
+	},
+	"resource dependencies": {
+		resource: "benchmark.b1",
+		expectedDOT: `digraph "mod.m1" {
+  rankdir=LR;
+  "dep.query.shared" [label="dep.query.shared", shape=cylinder];
+  "m1.benchmark.b1" [label="benchmark.b1", shape=box3d];
+  "m1.control.c1" [label="control.c1", shape=box];
+  "m1.control.c2" [label="control.c2", shape=box];
+  "m1.query.q1" [label="query.q1", shape=cylinder];
+  "m1.benchmark.b1" -> "m1.control.c1" [style=dashed];
+  "m1.benchmark.b1" -> "m1.control.c2" [style=dashed];
+  "m1.control.c1" -> "m1.query.q1" [label="query"];
+  "m1.control.c2" -> "dep.query.shared" [label="query"];
+}
+`,
+		expectedMermaid: `graph LR
+  n0["dep.query.shared"]
+  n1["benchmark.b1"]
+  n2["control.c1"]
+  n3["control.c2"]
+  n4["query.q1"]
+  n1 -.-> n2
+  n1 -.-> n3
+  n2 -->|"query"| n4
+  n3 -->|"query"| n0
+`,
+		expectedJSON: `{"mod":"m1","nodes":[{"id":"dep.query.shared","name":"query.shared","type":"query","mod":"dep"},{"id":"m1.benchmark.b1","name":"benchmark.b1","type":"benchmark","mod":"m1","title":"B1"},{"id":"m1.control.c1","name":"control.c1","type":"control","mod":"m1","title":"C1"},{"id":"m1.control.c2","name":"control.c2","type":"control","mod":"m1"},{"id":"m1.query.q1","name":"query.q1","type":"query","mod":"m1","title":"Q1"}],"edges":[{"from":"m1.benchmark.b1","to":"m1.control.c1","type":"child"},{"from":"m1.benchmark.b1","to":"m1.control.c2","type":"child"},{"from":"m1.control.c1","to":"m1.query.q1","type":"reference","label":"query"},{"from":"m1.control.c2","to":"dep.query.shared","type":"reference","label":"query"}]}`,
+	},
+	"dependency mod resource": {
+		resource: "dep.query.shared",
+		expectedDOT: `digraph "mod.m1" {
+  rankdir=LR;
+  "dep.query.shared" [label="dep.query.shared", shape=cylinder];
+  "m1.benchmark.b1" [label="benchmark.b1", shape=box3d];
+  "m1.control.c2" [label="control.c2", shape=box];
+  "m1.benchmark.b1" -> "m1.control.c2" [style=dashed];
+  "m1.control.c2" -> "dep.query.shared" [label="query"];
+}
+`,
+		expectedMermaid: `graph LR
+  n0["dep.query.shared"]
+  n1["benchmark.b1"]
+  n2["control.c2"]
+  n1 -.-> n2
+  n2 -->|"query"| n0
+`,
+		expectedJSON: `{"mod":"m1","nodes":[{"id":"dep.query.shared","name":"query.shared","type":"query","mod":"dep"},{"id":"m1.benchmark.b1","name":"benchmark.b1","type":"benchmark","mod":"m1","title":"B1"},{"id":"m1.control.c2","name":"control.c2","type":"control","mod":"m1"}],"edges":[{"from":"m1.benchmark.b1","to":"m1.control.c2","type":"child"},{"from":"m1.control.c2","to":"dep.query.shared","type":"reference","label":"query"}]}`,
+	},
+	"unknown resource": {
+		resource:     "query.missing",
+		expectedJSON: "ERROR",
+	},
+}
+
+func TestBuildGraph(t *testing.T) {
+	workspacePath, err := filepath.Abs("test_data/dependency_mod")
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	w, err := workspace.Load(workspacePath)
+	if err != nil {
+		t.Fatalf("failed to load workspace: %v", err)
+	}
+
+	for name, test := range testCasesBuildGraph {
+		g := Build(w)
+		var err error
+		if test.resource != "" {
+			g, err = BuildForResource(w, test.resource)
+		}
+		if err != nil {
+			if test.expectedJSON != "ERROR" {
+				t.Errorf("Test: '%s' FAILED with unexpected error: %v", name, err)
+			}
+			continue
+		}
+		if test.expectedJSON == "ERROR" {
+			t.Errorf("Test: '%s' FAILED - expected error", name)
+			continue
+		}
+
+		var dot, mermaid, jsonOutput, compactJSON bytes.Buffer
+		if err := WriteDOT(&dot, g); err != nil || dot.String() != test.expectedDOT {
+			t.Errorf("Test: '%s' FAILED : \nexpected DOT:\n%s\ngot:\n%s (%v)", name, test.expectedDOT, dot.String(), err)
+		}
+		if err := WriteMermaid(&mermaid, g); err != nil || mermaid.String() != test.expectedMermaid {
+			t.Errorf("Test: '%s' FAILED : \nexpected Mermaid:\n%s\ngot:\n%s (%v)", name, test.expectedMermaid, mermaid.String(), err)
+		}
+		if err := WriteJSON(&jsonOutput, g); err != nil {
+			t.Errorf("Test: '%s' FAILED : failed to write JSON: %v", name, err)
+			continue
+		}
+		if err := json.Compact(&compactJSON, jsonOutput.Bytes()); err != nil || compactJSON.String() != test.expectedJSON {
+			t.Errorf("Test: '%s' FAILED : \nexpected JSON:\n%s\ngot:\n%s (%v)", name, test.expectedJSON, compactJSON.String(), err)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package modgraph
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// output formats
+const (
+	OutputFormatDOT     = "dot"
+	OutputFormatMermaid = "mermaid"
+	OutputFormatJSON    = "json"
+)
+
+// the DOT node shape for each resource type
+var dotShapes = map[string]string{
+	"mod":       "folder",
+	"benchmark": "box3d",
+	"control":   "box",
+	"query":     "cylinder",
+	"report":    "tab",
+	"panel":     "component",
+	"var":       "ellipse",
+	"local":     "ellipse",
+	"test":      "note",
+}
+
+// WriteDOT writes the graph in Graphviz DOT format
+func WriteDOT(w io.Writer, g *Graph) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "digraph %s {\n", dotQuote(fmt.Sprintf("mod.%s", g.ModName)))
+	b.WriteString("  rankdir=LR;\n")
+	for _, node := range g.Nodes {
+		shape, ok := dotShapes[node.Type]
+		if !ok {
+			shape = "box"
+		}
+		fmt.Fprintf(&b, "  %s [label=%s, shape=%s];\n", dotQuote(node.ID), dotQuote(g.label(node)), shape)
+	}
+	for _, edge := range g.Edges {
+		var attrs []string
+		switch edge.Type {
+		case EdgeTypeChild:
+			attrs = append(attrs, "style=dashed")
+		case EdgeTypeRequires:
+			attrs = append(attrs, "style=bold")
+		}
+		if edge.Label != "" {
+			attrs = append(attrs, fmt.Sprintf("label=%s", dotQuote(edge.Label)))
+		}
+		fmt.Fprintf(&b, "  %s -> %s", dotQuote(edge.From), dotQuote(edge.To))
+		if len(attrs) > 0 {
+			fmt.Fprintf(&b, " [%s]", strings.Join(attrs, ", "))
+		}
+		b.WriteString(";\n")
+	}
+	b.WriteString("}\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteMermaid writes the graph as a Mermaid flowchart
+func WriteMermaid(w io.Writer, g *Graph) error {
+	var b strings.Builder
+	b.WriteString("graph LR\n")
+
+	// mermaid node ids may not contain dots - use the node index
+	ids := make(map[string]string, len(g.Nodes))
+	for i, node := range g.Nodes {
+		ids[node.ID] = fmt.Sprintf("n%d", i)
+		fmt.Fprintf(&b, "  %s[%s]\n", ids[node.ID], mermaidQuote(g.label(node)))
+	}
+	for _, edge := range g.Edges {
+		arrow := "-->"
+		switch edge.Type {
+		case EdgeTypeChild:
+			arrow = "-.->"
+		case EdgeTypeRequires:
+			arrow = "==>"
+		}
+		if edge.Label != "" {
+			arrow = fmt.Sprintf("%s|%s|", arrow, mermaidQuote(edge.Label))
+		}
+		fmt.Fprintf(&b, "  %s %s %s\n", ids[edge.From], arrow, ids[edge.To])
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteJSON writes the graph as JSON lists of nodes and edges
+func WriteJSON(w io.Writer, g *Graph) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(g)
+}
+
+// the display label of a node - resources of dependency mods are qualified with the mod name
+func (g *Graph) label(node *Node) string {
+	if node.Mod == g.ModName || node.Type == "mod" {
+		return node.Name
+	}
+	return node.ID
+}
+
+func dotQuote(s string) string {
+	return fmt.Sprintf(`"%s"`, strings.ReplaceAll(s, `"`, `\"`))
+}
+
+func mermaidQuote(s string) string {
+	return fmt.Sprintf(`"%s"`, strings.ReplaceAll(s, `"`, "#quot;"))
+}
@@ -0,0 +1,239 @@
+package modinit
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+
+	goVersion "github.com/hashicorp/go-version"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/turbot/go-kit/helpers"
+	"github.com/turbot/steampipe/constants"
+	"github.com/turbot/steampipe/ociinstaller"
+	"github.com/turbot/steampipe/ociinstaller/versionfile"
+	"github.com/turbot/steampipe/steampipeconfig/modconfig"
+	"github.com/turbot/steampipe/workspace"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// the extensions of template files which are rendered as go templates - all other files are copied as is
+var renderedExtensions = []string{constants.ModDataExtension, constants.VariablesExtension, ".md"}
+
+// templateData is the data available to template files
+type templateData struct {
+	ModName string
+	Title   string
+}
+
+// pluginRequirement is a plugin to add to the requires block of the mod
+type pluginRequirement struct {
+	name    string
+	version string
+}
+
+// Init creates a mod in the workspace folder from the given template
+// - the mod.sp file is generated (unless the template provides one), with a requires block for all installed plugins
+// - a steampipe.spvars file is generated for the variables declared by the mod
+// returns the paths of the files written, relative to the workspace
+func Init(workspacePath, templateName string) ([]string, error) {
+	modFilePath := filepath.Join(workspacePath, constants.WorkspaceModFileName)
+	if helpers.FileExists(modFilePath) {
+		return nil, fmt.Errorf("a mod already exists in %s", workspacePath)
+	}
+
+	t, err := getTemplate(templateName)
+	if err != nil {
+		return nil, err
+	}
+
+	absPath, err := filepath.Abs(workspacePath)
+	if err != nil {
+		return nil, err
+	}
+	modName := modNameFromPath(absPath)
+	data := &templateData{ModName: modName, Title: titleFromModName(modName)}
+
+	// build the content of all files
+	files := make(map[string]string)
+	for path, content := range t.files {
+		if helpers.StringSliceContains(renderedExtensions, filepath.Ext(path)) {
+			content, err = renderTemplateFile(path, content, data)
+			if err != nil {
+				return nil, err
+			}
+		}
+		files[path] = content
+	}
+	if _, ok := files[constants.WorkspaceModFileName]; !ok {
+		plugins, err := getInstalledPluginRequirements()
+		if err != nil {
+			return nil, err
+		}
+		files[constants.WorkspaceModFileName] = modFileContent(data, plugins)
+	}
+
+	// do not overwrite any existing files
+	var paths []string
+	for path := range files {
+		if helpers.FileExists(filepath.Join(workspacePath, filepath.FromSlash(path))) {
+			return nil, fmt.Errorf("cannot initialise mod: %s already exists", path)
+		}
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		if err := writeFile(filepath.Join(workspacePath, filepath.FromSlash(path)), files[path]); err != nil {
+			return paths, err
+		}
+	}
+
+	// now the mod is written, generate the variables file
+	varsFilePath := constants.DefaultVarsFilePath(workspacePath)
+	if helpers.FileExists(varsFilePath) {
+		return paths, nil
+	}
+	variables, err := workspace.LoadVariableDefinitions(workspacePath)
+	if err != nil {
+		return paths, fmt.Errorf("failed to load mod variables: %s", err.Error())
+	}
+	if len(variables) == 0 {
+		return paths, nil
+	}
+	if err := writeFile(varsFilePath, varsFileContent(variables)); err != nil {
+		return paths, err
+	}
+	return append(paths, constants.DefaultVarsFileName), nil
+}
+
+func renderTemplateFile(path, content string, data *templateData) (string, error) {
+	tmpl, err := template.New(path).Parse(content)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse template file %s: %s", path, err.Error())
+	}
+	var b bytes.Buffer
+	if err := tmpl.Execute(&b, data); err != nil {
+		return "", fmt.Errorf("failed to render template file %s: %s", path, err.Error())
+	}
+	return b.String(), nil
+}
+
+// generate the mod.sp file, requiring the given plugins
+func modFileContent(data *templateData, plugins []*pluginRequirement) string {
+	f := hclwrite.NewEmptyFile()
+	modBody := f.Body().AppendNewBlock(modconfig.BlockTypeMod, []string{data.ModName}).Body()
+	modBody.SetAttributeValue("title", cty.StringVal(data.Title))
+	modBody.SetAttributeValue("description", cty.StringVal(fmt.Sprintf("%s mod.", data.Title)))
+
+	if len(plugins) > 0 {
+		modBody.AppendNewline()
+		requiresBody := modBody.AppendNewBlock("requires", nil).Body()
+		for _, p := range plugins {
+			requiresBody.AppendNewBlock("plugin", []string{p.name}).Body().SetAttributeValue("version", cty.StringVal(p.version))
+		}
+	}
+	return string(hclwrite.Format(f.Bytes()))
+}
+
+// generate the steampipe.spvars file - a commented assignment for each variable, with its default value if it has one
+func varsFileContent(variables map[string]*modconfig.Variable) string {
+	var names []string
+	for name := range variables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	b.WriteString("# Values for the variables of the mod - uncomment and set a value to override the default.\n")
+	for _, name := range names {
+		v := variables[name]
+		b.WriteString("\n")
+		if v.Description != "" {
+			fmt.Fprintf(&b, "# %s\n", v.Description)
+		}
+		fmt.Fprintf(&b, "# Type: %s\n", v.Type.FriendlyName())
+		value := "<required>"
+		if v.Default != cty.NilVal && !v.Sensitive {
+			value = strings.TrimSpace(string(hclwrite.TokensForValue(v.Default).Bytes()))
+		}
+		fmt.Fprintf(&b, "# %s = %s\n", v.ShortName, value)
+	}
+	return b.String()
+}
+
+// build the plugin requirements from the plugin version file
+// if multiple versions of a plugin are installed, the lowest version is required
+func getInstalledPluginRequirements() ([]*pluginRequirement, error) {
+	versionFile, err := versionfile.LoadPluginVersionFile()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load plugin version file: %s", err.Error())
+	}
+	return pluginRequirements(versionFile), nil
+}
+
+func pluginRequirements(versionFile *versionfile.PluginVersionFile) []*pluginRequirement {
+	versions := make(map[string]*goVersion.Version)
+	for imageRef, installed := range versionFile.Plugins {
+		// ignore plugins without a semver version, e.g. locally built plugins
+		v, err := goVersion.NewVersion(installed.Version)
+		if err != nil {
+			continue
+		}
+		org, name, _ := ociinstaller.NewSteampipeImageRef(imageRef).GetOrgNameAndStream()
+		// plugins of the default org are required by name only
+		if org != ociinstaller.DefaultImageOrg {
+			name = fmt.Sprintf("%s/%s", org, name)
+		}
+		if existing, ok := versions[name]; !ok || v.LessThan(existing) {
+			versions[name] = v
+		}
+	}
+
+	var res []*pluginRequirement
+	for name, v := range versions {
+		res = append(res, &pluginRequirement{name: name, version: v.String()})
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].name < res[j].name
+	})
+	return res
+}
+
+var invalidModNameChars = regexp.MustCompile(`[^a-z0-9_]+`)
+
+// build a valid mod name from the workspace folder name, e.g. 'my-mod' becomes 'my_mod'
+func modNameFromPath(workspacePath string) string {
+	name := invalidModNameChars.ReplaceAllString(strings.ToLower(filepath.Base(workspacePath)), "_")
+	name = strings.Trim(name, "_")
+	if name == "" {
+		return constants.WorkspaceDefaultModName
+	}
+	if name[0] >= '0' && name[0] <= '9' {
+		name = "mod_" + name
+	}
+	return name
+}
+
+// build a title from the mod name, e.g. 'my_mod' becomes 'My Mod'
+func titleFromModName(modName string) string {
+	words := strings.Split(modName, "_")
+	for i, word := range words {
+		if word != "" {
+			words[i] = strings.ToUpper(word[:1]) + word[1:]
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+func writeFile(path, content string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, []byte(content), 0644)
+}
@@ -0,0 +1,47 @@
+package modinit
+
+import (
+	"testing"
+)
+
+type modNameTest struct {
+	path          string
+	expectedName  string
+	expectedTitle string
+}
+
+var testCasesModName = map[string]modNameTest{
+	"simple": {
+		path:          "/work/aws_compliance",
+		expectedName:  "aws_compliance",
+		expectedTitle: "Aws Compliance",
+	},
+	"hyphens and upper case": {
+		path:          "/work/My-Mod",
+		expectedName:  "my_mod",
+		expectedTitle: "My Mod",
+	},
+	"leading digit": {
+		path:          "/work/2021-checks",
+		expectedName:  "mod_2021_checks",
+		expectedTitle: "Mod 2021 Checks",
+	},
+	"no valid characters": {
+		path:          "/work/---",
+		expectedName:  "local",
+		expectedTitle: "Local",
+	},
+}
+
+func TestModNameFromPath(t *testing.T) {
+	for name, test := range testCasesModName {
+		modName := modNameFromPath(test.path)
+		if modName != test.expectedName {
+			t.Errorf("Test: '%s' FAILED : expected name '%s', got '%s'", name, test.expectedName, modName)
+			continue
+		}
+		if title := titleFromModName(modName); title != test.expectedTitle {
+			t.Errorf("Test: '%s' FAILED : expected title '%s', got '%s'", name, test.expectedTitle, title)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package modinit
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/turbot/steampipe/constants"
+)
+
+// built in template names
+const (
+	TemplateDefault = "default"
+	TemplateEmpty   = "empty"
+)
+
+// the files of the built in templates, keyed by path relative to the workspace
+// NOTE: the mod.sp file is generated, unless the template provides one
+var builtInTemplates = map[string]map[string]string{
+	TemplateDefault: {
+		"variables.sp": `variable "greeting" {
+  type        = string
+  description = "The greeting returned by the example query."
+  default     = "hello"
+}
+`,
+		"query.sp": `query "example" {
+  title       = "Example query"
+  description = "An example query with a parameter."
+  sql         = "select $1::text as greeting"
+
+  param "greeting" {
+    description = "The greeting to return."
+    default     = var.greeting
+  }
+}
+`,
+		"control.sp": `control "example" {
+  title       = "Example control"
+  description = "An example control - controls return a row per resource, with a resource, status and reason column."
+  sql         = <<-EOT
+    select
+      '{{.ModName}}' as resource,
+      'ok' as status,
+      'The {{.ModName}} mod is configured.' as reason
+  EOT
+}
+`,
+		"benchmark.sp": `benchmark "example" {
+  title       = "{{.Title}} Example Benchmark"
+  description = "An example benchmark - benchmarks group controls and other benchmarks."
+  children = [
+    control.example
+  ]
+}
+`,
+	},
+	TemplateEmpty: {},
+}
+
+// the files of a mod template
+type modTemplate struct {
+	name string
+	// map of file path (relative to the workspace) to file content
+	files map[string]string
+}
+
+// return the template with the given name
+// this may be a built in template, a local template in the steampipe templates directory, or the path to a template directory
+func getTemplate(name string) (*modTemplate, error) {
+	if files, ok := builtInTemplates[name]; ok {
+		return &modTemplate{name: name, files: files}, nil
+	}
+
+	for _, templateDir := range []string{name, filepath.Join(constants.TemplatesDir(), name)} {
+		if info, err := os.Stat(templateDir); err == nil && info.IsDir() {
+			return loadTemplateDir(name, templateDir)
+		}
+	}
+	return nil, fmt.Errorf("template '%s' not found - must be one of %s, a template in %s, or a template directory", name, builtInTemplateNames(), constants.TemplatesDir())
+}
+
+// load all files of a local template directory
+func loadTemplateDir(name, templateDir string) (*modTemplate, error) {
+	t := &modTemplate{name: name, files: make(map[string]string)}
+	err := filepath.Walk(templateDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			// do not copy the workspace data directory of a template
+			if info.Name() == constants.WorkspaceDataDir {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		relPath, err := filepath.Rel(templateDir, path)
+		if err != nil {
+			return err
+		}
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		t.files[filepath.ToSlash(relPath)] = string(data)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load template '%s': %s", name, err.Error())
+	}
+	return t, nil
+}
+
+func builtInTemplateNames() string {
+	var names []string
+	for name := range builtInTemplates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return fmt.Sprintf("%v", names)
+}
@@ -0,0 +1,85 @@
+package modtest
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/turbot/steampipe/db/db_common"
+	"github.com/turbot/steampipe/steampipeconfig/modconfig"
+	"github.com/zclconf/go-cty/cty"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+)
+
+// the temporary schema - this is always searched first for tables, so fixture tables shadow the connection tables
+const tempSchema = "pg_temp"
+
+// build the SQL to create a temporary table for the fixture and insert the fixture rows
+func fixtureSetupSql(fixture *modconfig.TestFixture) ([]string, error) {
+	tableName := db_common.PgEscapeName(fixture.Table)
+
+	columnDefs := make([]string, len(fixture.ColumnNames))
+	escapedColumns := make([]string, len(fixture.ColumnNames))
+	for i, column := range fixture.ColumnNames {
+		escapedColumns[i] = db_common.PgEscapeName(column)
+		columnDefs[i] = fmt.Sprintf("%s %s", escapedColumns[i], fixture.Columns[column])
+	}
+
+	queries := []string{
+		fmt.Sprintf("drop table if exists %s.%s", tempSchema, tableName),
+		fmt.Sprintf("create temporary table %s (%s)", tableName, strings.Join(columnDefs, ", ")),
+	}
+	if len(fixture.Rows) == 0 {
+		return queries, nil
+	}
+
+	rowValues := make([]string, len(fixture.Rows))
+	for i, row := range fixture.Rows {
+		values := make([]string, len(fixture.ColumnNames))
+		for j, column := range fixture.ColumnNames {
+			value, ok := row[column]
+			if !ok {
+				value = cty.NullVal(cty.DynamicPseudoType)
+			}
+			literal, err := fixtureValueLiteral(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid value for column '%s' of fixture '%s': %v", column, fixture.Table, err)
+			}
+			values[j] = literal
+		}
+		rowValues[i] = fmt.Sprintf("(%s)", strings.Join(values, ", "))
+	}
+	queries = append(queries, fmt.Sprintf("insert into %s (%s) values %s", tableName, strings.Join(escapedColumns, ", "), strings.Join(rowValues, ", ")))
+	return queries, nil
+}
+
+// build the SQL to drop the temporary table for the fixture
+func fixtureTeardownSql(fixture *modconfig.TestFixture) string {
+	return fmt.Sprintf("drop table if exists %s.%s", tempSchema, db_common.PgEscapeName(fixture.Table))
+}
+
+// convert a fixture value into a postgres literal
+// strings are escaped, objects and lists are converted to JSON
+func fixtureValueLiteral(v cty.Value) (string, error) {
+	if v.IsNull() {
+		return "null", nil
+	}
+	if !v.IsWhollyKnown() {
+		return "", fmt.Errorf("value is not known")
+	}
+	switch v.Type() {
+	case cty.String:
+		return db_common.PgEscapeString(v.AsString()), nil
+	case cty.Number:
+		return v.AsBigFloat().Text('f', -1), nil
+	case cty.Bool:
+		if v.True() {
+			return "true", nil
+		}
+		return "false", nil
+	}
+	buf, err := ctyjson.Marshal(v, v.Type())
+	if err != nil {
+		return "", err
+	}
+	return db_common.PgEscapeString(string(buf)), nil
+}
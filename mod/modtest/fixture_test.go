@@ -0,0 +1,166 @@
+package modtest
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/turbot/steampipe/steampipeconfig/modconfig"
+	"github.com/zclconf/go-cty/cty"
+)
+
+type fixtureSetupSqlTest struct {
+	fixture *modconfig.TestFixture
+	// the expected statements, or nil if an error is expected
+	expected []string
+}
+
+var testCasesFixtureSetupSql = map[string]fixtureSetupSqlTest{
+	"inferred column types": {
+		fixture: &modconfig.TestFixture{
+			Table:       "aws_s3_bucket",
+			Columns:     map[string]string{"name": "text", "size": "numeric", "versioning_enabled": "boolean"},
+			ColumnNames: []string{"name", "size", "versioning_enabled"},
+			Rows: []map[string]cty.Value{
+				{"name": cty.StringVal("b1"), "size": cty.NumberIntVal(10), "versioning_enabled": cty.True},
+			},
+		},
+		expected: []string{
+			`drop table if exists pg_temp."aws_s3_bucket"`,
+			`create temporary table "aws_s3_bucket" ("name" text, "size" numeric, "versioning_enabled" boolean)`,
+			`insert into "aws_s3_bucket" ("name", "size", "versioning_enabled") values ($steampipe_escape$b1$steampipe_escape$, 10, true)`,
+		},
+	},
+	"explicit column types": {
+		fixture: &modconfig.TestFixture{
+			Table:       "aws_s3_bucket",
+			Columns:     map[string]string{"created": "timestamp with time zone", "size": "numeric(10,2)"},
+			ColumnNames: []string{"created", "size"},
+			Rows: []map[string]cty.Value{
+				{"created": cty.StringVal("2021-01-01T00:00:00Z"), "size": cty.NumberFloatVal(1.5)},
+			},
+		},
+		expected: []string{
+			`drop table if exists pg_temp."aws_s3_bucket"`,
+			`create temporary table "aws_s3_bucket" ("created" timestamp with time zone, "size" numeric(10,2))`,
+			`insert into "aws_s3_bucket" ("created", "size") values ($steampipe_escape$2021-01-01T00:00:00Z$steampipe_escape$, 1.5)`,
+		},
+	},
+	"quoted table and column names": {
+		fixture: &modconfig.TestFixture{
+			Table:       `my "table"`,
+			Columns:     map[string]string{`a "b"`: "text"},
+			ColumnNames: []string{`a "b"`},
+			Rows: []map[string]cty.Value{
+				{`a "b"`: cty.StringVal("it's")},
+			},
+		},
+		expected: []string{
+			`drop table if exists pg_temp."my ""table"""`,
+			`create temporary table "my ""table""" ("a ""b""" text)`,
+			`insert into "my ""table""" ("a ""b""") values ($steampipe_escape$it's$steampipe_escape$)`,
+		},
+	},
+	"null and missing values": {
+		fixture: &modconfig.TestFixture{
+			Table:       "t1",
+			Columns:     map[string]string{"a": "text", "b": "text"},
+			ColumnNames: []string{"a", "b"},
+			Rows: []map[string]cty.Value{
+				{"a": cty.NullVal(cty.String)},
+				{"b": cty.StringVal("x")},
+			},
+		},
+		expected: []string{
+			`drop table if exists pg_temp."t1"`,
+			`create temporary table "t1" ("a" text, "b" text)`,
+			`insert into "t1" ("a", "b") values (null, null), (null, $steampipe_escape$x$steampipe_escape$)`,
+		},
+	},
+	"list and object values": {
+		fixture: &modconfig.TestFixture{
+			Table:       "t1",
+			Columns:     map[string]string{"tags": "jsonb", "regions": "jsonb"},
+			ColumnNames: []string{"regions", "tags"},
+			Rows: []map[string]cty.Value{
+				{
+					"regions": cty.TupleVal([]cty.Value{cty.StringVal("us-east-1"), cty.StringVal("eu-west-2")}),
+					"tags":    cty.ObjectVal(map[string]cty.Value{"owner": cty.StringVal("me"), "count": cty.NumberIntVal(2)}),
+				},
+			},
+		},
+		expected: []string{
+			`drop table if exists pg_temp."t1"`,
+			`create temporary table "t1" ("regions" jsonb, "tags" jsonb)`,
+			`insert into "t1" ("regions", "tags") values ($steampipe_escape$["us-east-1","eu-west-2"]$steampipe_escape$, $steampipe_escape${"count":2,"owner":"me"}$steampipe_escape$)`,
+		},
+	},
+	"no rows": {
+		fixture: &modconfig.TestFixture{
+			Table:       "t1",
+			Columns:     map[string]string{"a": "text"},
+			ColumnNames: []string{"a"},
+		},
+		expected: []string{
+			`drop table if exists pg_temp."t1"`,
+			`create temporary table "t1" ("a" text)`,
+		},
+	},
+	"unknown value": {
+		fixture: &modconfig.TestFixture{
+			Table:       "t1",
+			Columns:     map[string]string{"a": "text"},
+			ColumnNames: []string{"a"},
+			Rows:        []map[string]cty.Value{{"a": cty.UnknownVal(cty.String)}},
+		},
+		expected: nil,
+	},
+}
+
+func TestFixtureSetupSql(t *testing.T) {
+	for name, test := range testCasesFixtureSetupSql {
+		queries, err := fixtureSetupSql(test.fixture)
+		if err != nil {
+			if test.expected != nil {
+				t.Errorf("Test: '%s' FAILED with unexpected error: %v", name, err)
+			}
+			continue
+		}
+		if test.expected == nil {
+			t.Errorf("Test: '%s' FAILED - expected error", name)
+			continue
+		}
+		if !reflect.DeepEqual(queries, test.expected) {
+			t.Errorf("Test: '%s' FAILED : \nexpected:\n %v\ngot:\n %v", name, test.expected, queries)
+		}
+	}
+}
+
+type fixtureValueLiteralTest struct {
+	value    cty.Value
+	expected string
+}
+
+var testCasesFixtureValueLiteral = map[string]fixtureValueLiteralTest{
+	"string":        {value: cty.StringVal("a'b"), expected: "$steampipe_escape$a'b$steampipe_escape$"},
+	"integer":       {value: cty.NumberIntVal(42), expected: "42"},
+	"float":         {value: cty.NumberFloatVal(0.25), expected: "0.25"},
+	"bool":          {value: cty.False, expected: "false"},
+	"null":          {value: cty.NullVal(cty.DynamicPseudoType), expected: "null"},
+	"list":          {value: cty.ListVal([]cty.Value{cty.StringVal("a")}), expected: `$steampipe_escape$["a"]$steampipe_escape$`},
+	"map":           {value: cty.MapVal(map[string]cty.Value{"k": cty.StringVal("v")}), expected: `$steampipe_escape${"k":"v"}$steampipe_escape$`},
+	"nested object": {value: cty.ObjectVal(map[string]cty.Value{"a": cty.ObjectVal(map[string]cty.Value{"b": cty.True})}), expected: `$steampipe_escape${"a":{"b":true}}$steampipe_escape$`},
+	"empty string":  {value: cty.StringVal(""), expected: "$steampipe_escape$$steampipe_escape$"},
+}
+
+func TestFixtureValueLiteral(t *testing.T) {
+	for name, test := range testCasesFixtureValueLiteral {
+		literal, err := fixtureValueLiteral(test.value)
+		if err != nil {
+			t.Errorf("Test: '%s' FAILED with unexpected error: %v", name, err)
+			continue
+		}
+		if literal != test.expected {
+			t.Errorf("Test: '%s' FAILED : expected %s, got %s", name, test.expected, literal)
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package modtest
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/turbot/steampipe/utils"
+)
+
+// output formats
+const (
+	OutputFormatText  = "text"
+	OutputFormatJUnit = "junit"
+)
+
+// WriteText writes the result of each test, followed by a summary line
+func WriteText(w io.Writer, report *Report) error {
+	for _, res := range report.Results {
+		var status string
+		switch {
+		case res.Passed():
+			status = color.GreenString("PASS ")
+		case res.Failed():
+			status = color.RedString("FAIL ")
+		default:
+			status = color.RedString("ERROR")
+		}
+		fmt.Fprintf(w, "%s %s (%s)\n", status, res.Test.Name(), formatDuration(res.Duration))
+		for _, line := range resultDetails(res) {
+			fmt.Fprintf(w, "      %s\n", line)
+		}
+	}
+	if len(report.Results) > 0 {
+		fmt.Fprintln(w)
+	}
+
+	total := len(report.Results)
+	_, err := fmt.Fprintf(w, "%d %s: %d passed, %d failed, %d %s (%s)\n",
+		total, utils.Pluralize("test", total),
+		report.Passed(),
+		report.Failed(),
+		report.Errors(), utils.Pluralize("error", report.Errors()),
+		formatDuration(report.Duration))
+	return err
+}
+
+// return a line for each problem with the test result
+func resultDetails(res *TestResult) []string {
+	if res.Error != nil {
+		return []string{res.Error.Error()}
+	}
+	var lines []string
+	for _, expectation := range res.Missing {
+		lines = append(lines, fmt.Sprintf("missing:    %s", expectation.String()))
+	}
+	for _, row := range res.Unexpected {
+		lines = append(lines, fmt.Sprintf("unexpected: %s: %s (%s)", row.Status, row.Resource, row.Reason))
+	}
+	return lines
+}
+
+func formatDuration(d time.Duration) string {
+	return d.Round(time.Millisecond).String()
+}
+
+// JUnit XML report elements
+// https://llg.cubic.org/docs/junit/
+
+type junitTestSuites struct {
+	XMLName  xml.Name          `xml:"testsuites"`
+	Name     string            `xml:"name,attr"`
+	Tests    int               `xml:"tests,attr"`
+	Failures int               `xml:"failures,attr"`
+	Errors   int               `xml:"errors,attr"`
+	Time     string            `xml:"time,attr"`
+	Suites   []*junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string           `xml:"name,attr"`
+	Tests     int              `xml:"tests,attr"`
+	Failures  int              `xml:"failures,attr"`
+	Errors    int              `xml:"errors,attr"`
+	Time      string           `xml:"time,attr"`
+	TestCases []*junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	File      string        `xml:"file,attr,omitempty"`
+	Line      int           `xml:"line,attr,omitempty"`
+	Failure   *junitProblem `xml:"failure,omitempty"`
+	Error     *junitProblem `xml:"error,omitempty"`
+}
+
+type junitProblem struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// WriteJUnit writes the report as JUnit XML, with a test suite for the mod and a test case for each test
+func WriteJUnit(w io.Writer, report *Report) error {
+	suite := &junitTestSuite{
+		Name:     report.ModName,
+		Tests:    len(report.Results),
+		Failures: report.Failed(),
+		Errors:   report.Errors(),
+		Time:     junitTime(report.Duration),
+	}
+	for _, res := range report.Results {
+		testCase := &junitTestCase{
+			Name:      res.Test.Name(),
+			ClassName: fmt.Sprintf("%s.%s", report.ModName, res.Test.ControlName),
+			Time:      junitTime(res.Duration),
+		}
+		if metadata := res.Test.GetMetadata(); metadata != nil {
+			testCase.File = metadata.FileName
+			testCase.Line = metadata.StartLineNumber
+		}
+		details := strings.Join(resultDetails(res), "\n")
+		switch {
+		case res.Error != nil:
+			testCase.Error = &junitProblem{Message: res.Error.Error(), Type: "error", Text: details}
+		case res.Failed():
+			message := fmt.Sprintf("%d missing, %d unexpected %s", len(res.Missing), len(res.Unexpected), utils.Pluralize("row", len(res.Unexpected)))
+			testCase.Failure = &junitProblem{Message: message, Type: "assertion", Text: details}
+		}
+		suite.TestCases = append(suite.TestCases, testCase)
+	}
+
+	suites := &junitTestSuites{
+		Name:     "steampipe",
+		Tests:    suite.Tests,
+		Failures: suite.Failures,
+		Errors:   suite.Errors,
+		Time:     suite.Time,
+		Suites:   []*junitTestSuite{suite},
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(suites); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintln(w)
+	return err
+}
+
+// JUnit times are in seconds
+func junitTime(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
@@ -0,0 +1,101 @@
+package modtest
+
+import (
+	"time"
+
+	"github.com/turbot/steampipe/control/controlexecute"
+	"github.com/turbot/steampipe/steampipeconfig/modconfig"
+)
+
+// TestResult is the result of running a single test
+type TestResult struct {
+	Test     *modconfig.ControlTest
+	Duration time.Duration
+	// set if the test could not be run, e.g. the fixture data could not be loaded or the control SQL failed
+	Error error
+	// expected rows which were not returned by the control
+	Missing []*modconfig.TestExpectation
+	// rows returned by the control which were not expected
+	Unexpected []*controlexecute.ResultRow
+}
+
+// Passed returns whether the test ran and the control results matched the expected rows
+func (r *TestResult) Passed() bool {
+	return r.Error == nil && len(r.Missing) == 0 && len(r.Unexpected) == 0
+}
+
+// Failed returns whether the test ran but the control results did not match the expected rows
+func (r *TestResult) Failed() bool {
+	return r.Error == nil && !r.Passed()
+}
+
+// compare the control results with the expected rows
+// the order of the results is not significant, but duplicate rows must be expected the same number of times
+func (r *TestResult) compare(expected []*modconfig.TestExpectation, rows []*controlexecute.ResultRow) {
+	matched := make([]bool, len(rows))
+	for _, expectation := range expected {
+		found := false
+		for i, row := range rows {
+			if !matched[i] && expectationMatches(expectation, row) {
+				matched[i] = true
+				found = true
+				break
+			}
+		}
+		if !found {
+			r.Missing = append(r.Missing, expectation)
+		}
+	}
+	for i, row := range rows {
+		if !matched[i] {
+			r.Unexpected = append(r.Unexpected, row)
+		}
+	}
+}
+
+func expectationMatches(expectation *modconfig.TestExpectation, row *controlexecute.ResultRow) bool {
+	if expectation.Resource != row.Resource || expectation.Status != row.Status {
+		return false
+	}
+	return expectation.Reason == nil || *expectation.Reason == row.Reason
+}
+
+// Report is the result of running the tests of a mod
+type Report struct {
+	ModName  string
+	Results  []*TestResult
+	Duration time.Duration
+}
+
+// Passed returns the number of tests which passed
+func (r *Report) Passed() int {
+	count := 0
+	for _, res := range r.Results {
+		if res.Passed() {
+			count++
+		}
+	}
+	return count
+}
+
+// Failed returns the number of tests whose control results did not match the expected rows
+func (r *Report) Failed() int {
+	count := 0
+	for _, res := range r.Results {
+		if res.Failed() {
+			count++
+		}
+	}
+	return count
+}
+
+// Errors returns the number of tests which could not be run
+func (r *Report) Errors() int {
+	count := 0
+	for _, res := range r.Results {
+		if res.Error != nil {
+			count++
+		}
+	}
+	return count
+}
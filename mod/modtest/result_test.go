@@ -0,0 +1,74 @@
+package modtest
+
+import (
+	"testing"
+
+	"github.com/turbot/steampipe/control/controlexecute"
+	"github.com/turbot/steampipe/steampipeconfig/modconfig"
+	"github.com/turbot/steampipe/utils"
+)
+
+type compareTest struct {
+	expected           []*modconfig.TestExpectation
+	rows               []*controlexecute.ResultRow
+	expectedMissing    int
+	expectedUnexpected int
+}
+
+var testCasesCompare = map[string]compareTest{
+	"match in any order": {
+		expected: []*modconfig.TestExpectation{
+			{Resource: "r1", Status: "ok"},
+			{Resource: "r2", Status: "alarm"},
+		},
+		rows: []*controlexecute.ResultRow{
+			{Resource: "r2", Status: "alarm", Reason: "bad"},
+			{Resource: "r1", Status: "ok", Reason: "good"},
+		},
+	},
+	"status mismatch": {
+		expected: []*modconfig.TestExpectation{
+			{Resource: "r1", Status: "ok"},
+		},
+		rows: []*controlexecute.ResultRow{
+			{Resource: "r1", Status: "alarm"},
+		},
+		expectedMissing:    1,
+		expectedUnexpected: 1,
+	},
+	"reason mismatch": {
+		expected: []*modconfig.TestExpectation{
+			{Resource: "r1", Status: "ok", Reason: utils.ToStringPointer("good")},
+		},
+		rows: []*controlexecute.ResultRow{
+			{Resource: "r1", Status: "ok", Reason: "bad"},
+		},
+		expectedMissing:    1,
+		expectedUnexpected: 1,
+	},
+	"duplicate row": {
+		expected: []*modconfig.TestExpectation{
+			{Resource: "r1", Status: "ok"},
+		},
+		rows: []*controlexecute.ResultRow{
+			{Resource: "r1", Status: "ok"},
+			{Resource: "r1", Status: "ok"},
+		},
+		expectedUnexpected: 1,
+	},
+	"no rows expected": {
+		expected: []*modconfig.TestExpectation{},
+		rows:     nil,
+	},
+}
+
+func TestCompare(t *testing.T) {
+	for name, test := range testCasesCompare {
+		res := &TestResult{}
+		res.compare(test.expected, test.rows)
+		if len(res.Missing) != test.expectedMissing || len(res.Unexpected) != test.expectedUnexpected {
+			t.Errorf("Test: '%s' FAILED : expected %d missing and %d unexpected, got %d missing and %d unexpected",
+				name, test.expectedMissing, test.expectedUnexpected, len(res.Missing), len(res.Unexpected))
+		}
+	}
+}
@@ -0,0 +1,185 @@
+package modtest
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/turbot/steampipe/control/controlexecute"
+	"github.com/turbot/steampipe/db/db_common"
+	"github.com/turbot/steampipe/query/queryresult"
+	"github.com/turbot/steampipe/steampipeconfig/modconfig"
+	"github.com/turbot/steampipe/workspace"
+)
+
+// the name of the prepared statement used to run the control under test
+// this is created after the fixture tables, so the control SQL resolves the fixture tables rather than the connection tables
+const testStatementName = "steampipe_mod_test"
+
+// Run runs the tests of the workspace mod with the given names (or all tests if no names are given)
+//
+// NOTE: the fixture tables are temporary tables, which are only visible to the database session which created them.
+// The client must therefore execute all queries using the same session - this is the case for the steampipe db client,
+// which limits the connection pool to a single connection
+func Run(ctx context.Context, w *workspace.Workspace, client db_common.Client, names []string) (*Report, error) {
+	tests, err := getTests(w.Mod, names)
+	if err != nil {
+		return nil, err
+	}
+
+	startTime := time.Now()
+	report := &Report{ModName: w.Mod.ShortName}
+	for _, test := range tests {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		report.Results = append(report.Results, runTest(ctx, w, client, test))
+	}
+	report.Duration = time.Since(startTime)
+	return report, nil
+}
+
+// return the tests with the given names, sorted by name
+// names may be given either with or without the 'test.' prefix
+func getTests(mod *modconfig.Mod, names []string) ([]*modconfig.ControlTest, error) {
+	var tests []*modconfig.ControlTest
+	if len(names) == 0 {
+		for _, test := range mod.Tests {
+			tests = append(tests, test)
+		}
+	} else {
+		for _, name := range names {
+			if !strings.HasPrefix(name, fmt.Sprintf("%s.", modconfig.BlockTypeTest)) {
+				name = fmt.Sprintf("%s.%s", modconfig.BlockTypeTest, name)
+			}
+			test, ok := mod.Tests[name]
+			if !ok {
+				return nil, fmt.Errorf("%s not found in mod %s", name, mod.ShortName)
+			}
+			tests = append(tests, test)
+		}
+	}
+	sort.Slice(tests, func(i, j int) bool {
+		return tests[i].Name() < tests[j].Name()
+	})
+	return tests, nil
+}
+
+func runTest(ctx context.Context, w *workspace.Workspace, client db_common.Client, test *modconfig.ControlTest) *TestResult {
+	log.Printf("[TRACE] running %s", test.Name())
+	startTime := time.Now()
+	res := &TestResult{Test: test}
+	defer func() {
+		res.Duration = time.Since(startTime)
+	}()
+
+	rows, err := executeTest(ctx, w, client, test)
+	if err != nil {
+		res.Error = err
+		return res
+	}
+	res.compare(test.Expect, rows)
+	return res
+}
+
+// load the fixtures, run the control and return the control results
+func executeTest(ctx context.Context, w *workspace.Workspace, client db_common.Client, test *modconfig.ControlTest) ([]*controlexecute.ResultRow, error) {
+	control, ok := w.Controls[test.ControlName]
+	if !ok {
+		return nil, fmt.Errorf("%s not found", test.ControlName)
+	}
+	sql, args, err := getControlSql(w, control)
+	if err != nil {
+		return nil, err
+	}
+
+	// put the temporary schema first in the search path, so the fixture tables shadow the connection tables
+	searchPath, err := client.GetCurrentSearchPath()
+	if err != nil {
+		return nil, err
+	}
+	if err := setSearchPath(ctx, client, append([]string{tempSchema}, searchPath...)); err != nil {
+		return nil, err
+	}
+	defer cleanup(client, setSearchPathSql(searchPath))
+
+	for _, fixture := range test.Fixtures {
+		setupSql, err := fixtureSetupSql(fixture)
+		if err != nil {
+			return nil, err
+		}
+		defer cleanup(client, fixtureTeardownSql(fixture))
+		if _, err := client.ExecuteSync(ctx, strings.Join(setupSql, ";\n"), true); err != nil {
+			return nil, fmt.Errorf("failed to load fixture '%s': %v", fixture.Table, err)
+		}
+	}
+
+	// prepare the control SQL now the fixture tables exist, so it is planned against them
+	if _, err := client.ExecuteSync(ctx, fmt.Sprintf("prepare %s as %s", testStatementName, sql), true); err != nil {
+		return nil, fmt.Errorf("failed to prepare %s: %v", control.Name(), err)
+	}
+	defer cleanup(client, fmt.Sprintf("deallocate %s", testStatementName))
+
+	result, err := client.ExecuteSync(ctx, fmt.Sprintf("execute %s%s", testStatementName, args), true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to run %s: %v", control.Name(), err)
+	}
+
+	var rows []*controlexecute.ResultRow
+	for _, r := range result.Rows {
+		row, err := controlexecute.NewResultRow(control, r.(*queryresult.RowResult), result.ColTypes)
+		if err != nil {
+			return nil, fmt.Errorf("%s returned an invalid result: %v", control.Name(), err)
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// return the SQL of the control (or the query it refers to), and the resolved args string
+func getControlSql(w *workspace.Workspace, control *modconfig.Control) (string, string, error) {
+	source, err := w.ResolveControlQuerySource(control)
+	if err != nil {
+		return "", "", err
+	}
+	var sql *string
+	switch s := source.(type) {
+	case *modconfig.Control:
+		sql = s.SQL
+	case *modconfig.Query:
+		sql = s.SQL
+	}
+	if sql == nil || strings.TrimSpace(*sql) == "" {
+		return "", "", fmt.Errorf("%s has no SQL", control.Name())
+	}
+
+	queryArgs := control.Args
+	if queryArgs == nil {
+		queryArgs = modconfig.NewQueryArgs()
+	}
+	args, err := queryArgs.ResolveAsString(source)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to resolve args for %s: %v", control.Name(), err)
+	}
+
+	return strings.TrimSuffix(strings.TrimSpace(*sql), ";"), args, nil
+}
+
+func setSearchPath(ctx context.Context, client db_common.Client, searchPath []string) error {
+	_, err := client.ExecuteSync(ctx, setSearchPathSql(searchPath), true)
+	return err
+}
+
+func setSearchPathSql(searchPath []string) string {
+	return fmt.Sprintf("set search_path to %s", strings.Join(db_common.PgEscapeSearchPath(searchPath), ","))
+}
+
+// run a cleanup query - this uses a background context, so cleanup is done even if the test context is cancelled
+func cleanup(client db_common.Client, query string) {
+	if _, err := client.ExecuteSync(context.Background(), query, true); err != nil {
+		log.Printf("[WARN] mod test cleanup query '%s' failed: %v", query, err)
+	}
+}
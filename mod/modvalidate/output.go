@@ -0,0 +1,93 @@
+package modvalidate
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/fatih/color"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/turbot/steampipe/utils"
+)
+
+// output formats
+const (
+	OutputFormatText = "text"
+	OutputFormatJSON = "json"
+)
+
+// Diagnostic is the JSON representation of a validation diagnostic
+type Diagnostic struct {
+	Severity  string `json:"severity"`
+	Summary   string `json:"summary"`
+	Detail    string `json:"detail,omitempty"`
+	FileName  string `json:"file_name,omitempty"`
+	StartLine int    `json:"start_line,omitempty"`
+	EndLine   int    `json:"end_line,omitempty"`
+}
+
+// Result is the JSON representation of the validation result
+type Result struct {
+	Errors      int           `json:"errors"`
+	Warnings    int           `json:"warnings"`
+	Diagnostics []*Diagnostic `json:"diagnostics"`
+}
+
+func NewResult(diags hcl.Diagnostics) *Result {
+	res := &Result{Diagnostics: []*Diagnostic{}}
+	for _, diag := range diags {
+		d := &Diagnostic{
+			Severity: "error",
+			Summary:  diag.Summary,
+			Detail:   diag.Detail,
+		}
+		if diag.Severity == hcl.DiagWarning {
+			d.Severity = "warning"
+			res.Warnings++
+		} else {
+			res.Errors++
+		}
+		if diag.Subject != nil {
+			d.FileName = diag.Subject.Filename
+			d.StartLine = diag.Subject.Start.Line
+			d.EndLine = diag.Subject.End.Line
+		}
+		res.Diagnostics = append(res.Diagnostics, d)
+	}
+	return res
+}
+
+// WriteText writes the diagnostics, followed by a summary line
+func WriteText(w io.Writer, diags hcl.Diagnostics) error {
+	res := NewResult(diags)
+	for _, d := range res.Diagnostics {
+		severity := color.RedString("Error")
+		if d.Severity == "warning" {
+			severity = color.YellowString("Warning")
+		}
+		fmt.Fprintf(w, "%s: %s\n", severity, d.Summary)
+		if d.Detail != "" {
+			fmt.Fprintf(w, "  %s\n", d.Detail)
+		}
+		if d.FileName != "" {
+			fmt.Fprintf(w, "  on %s line %d\n", d.FileName, d.StartLine)
+		}
+		fmt.Fprintln(w)
+	}
+
+	if len(res.Diagnostics) == 0 {
+		_, err := fmt.Fprintln(w, "Validation succeeded - no problems found")
+		return err
+	}
+	_, err := fmt.Fprintf(w, "Validation found %d %s and %d %s\n",
+		res.Errors, utils.Pluralize("error", res.Errors),
+		res.Warnings, utils.Pluralize("warning", res.Warnings))
+	return err
+}
+
+// WriteJSON writes the diagnostics as a JSON Result
+func WriteJSON(w io.Writer, diags hcl.Diagnostics) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(NewResult(diags))
+}
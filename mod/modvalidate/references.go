@@ -0,0 +1,79 @@
+package modvalidate
+
+import (
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/turbot/go-kit/helpers"
+	"github.com/turbot/steampipe/steampipeconfig/hclhelpers"
+	"github.com/turbot/steampipe/steampipeconfig/modconfig"
+)
+
+// the resource types whose references are collected
+var referencedTypes = []string{"var", modconfig.BlockTypeQuery, modconfig.BlockTypeControl, modconfig.BlockTypeBenchmark}
+
+// matches a call to the var() sql function, e.g. var('region')
+var sqlVariableRegex = regexp.MustCompile(`(?i)\bvar\s*\(\s*'([^']+)'\s*\)`)
+
+// parse the given hcl files and return the names of all resources referenced by any expression
+// for example var.v1, query.q1 or (for a mod qualified reference) m1.query.q1
+// NOTE: unlike the references stored by the parser, this includes references from all block types, e.g. locals and reports
+func getReferencedNames(files []string) (map[string]bool, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+	referenced := make(map[string]bool)
+
+	for _, filePath := range files {
+		data, err := ioutil.ReadFile(filePath)
+		if err != nil {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  fmt.Sprintf("failed to read file %s", filePath),
+				Detail:   err.Error(),
+			})
+			continue
+		}
+		file, moreDiags := hclsyntax.ParseConfig(data, filePath, hcl.Pos{Line: 1, Column: 1})
+		if moreDiags.HasErrors() {
+			// syntax errors are reported when the workspace is loaded
+			continue
+		}
+
+		hclsyntax.VisitAll(file.Body.(*hclsyntax.Body), func(node hclsyntax.Node) hcl.Diagnostics {
+			if expr, ok := node.(*hclsyntax.ScopeTraversalExpr); ok {
+				for _, name := range referencedNamesFromTraversal(expr.Traversal) {
+					referenced[name] = true
+				}
+			}
+			return nil
+		})
+	}
+	return referenced, diags
+}
+
+// return the names of the resource referenced by a traversal
+// for a mod qualified reference, both the qualified and unqualified names are returned
+func referencedNamesFromTraversal(traversal hcl.Traversal) []string {
+	parts := strings.Split(hclhelpers.TraversalAsString(traversal), ".")
+	if len(parts) >= 2 && helpers.StringSliceContains(referencedTypes, parts[0]) {
+		return []string{strings.Join(parts[:2], ".")}
+	}
+	if len(parts) >= 3 && helpers.StringSliceContains(referencedTypes, parts[1]) {
+		return []string{strings.Join(parts[:3], "."), strings.Join(parts[1:3], ".")}
+	}
+	return nil
+}
+
+// add references to variables made by var() calls in the given SQL
+func addSqlVariableReferences(sql *string, referenced map[string]bool) {
+	if sql == nil {
+		return
+	}
+	for _, match := range sqlVariableRegex.FindAllStringSubmatch(*sql, -1) {
+		name := strings.TrimPrefix(match[1], "var.")
+		referenced[fmt.Sprintf("var.%s", name)] = true
+	}
+}
@@ -0,0 +1,65 @@
+package modvalidate
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+)
+
+type referencedNamesTest struct {
+	traversal string
+	expected  []string
+}
+
+var testCasesReferencedNames = map[string]referencedNamesTest{
+	"variable": {
+		traversal: "var.region",
+		expected:  []string{"var.region"},
+	},
+	"variable property": {
+		traversal: "var.config.region",
+		expected:  []string{"var.config"},
+	},
+	"query": {
+		traversal: "query.q1",
+		expected:  []string{"query.q1"},
+	},
+	"mod qualified control": {
+		traversal: "m1.control.c1",
+		expected:  []string{"m1.control.c1", "control.c1"},
+	},
+	"local": {
+		traversal: "local.l1",
+		expected:  nil,
+	},
+	"unqualified": {
+		traversal: "steampipe",
+		expected:  nil,
+	},
+}
+
+func TestReferencedNamesFromTraversal(t *testing.T) {
+	for name, test := range testCasesReferencedNames {
+		traversal, diags := hclsyntax.ParseTraversalAbs([]byte(test.traversal), "", hcl.Pos{Line: 1, Column: 1})
+		if diags.HasErrors() {
+			t.Fatalf("Test: '%s' FAILED : failed to parse traversal: %s", name, diags.Error())
+		}
+		names := referencedNamesFromTraversal(traversal)
+		if !reflect.DeepEqual(names, test.expected) {
+			t.Errorf("Test: '%s' FAILED : expected %v, got %v", name, test.expected, names)
+		}
+	}
+}
+
+func TestAddSqlVariableReferences(t *testing.T) {
+	sql := "select * from aws_s3_bucket where region = var('region') and account_id = VAR( 'account' )"
+	referenced := make(map[string]bool)
+	addSqlVariableReferences(&sql, referenced)
+
+	expected := map[string]bool{"var.region": true, "var.account": true}
+	if !reflect.DeepEqual(referenced, expected) {
+		t.Errorf("Test: 'var() references' FAILED : expected %v, got %v", expected, referenced)
+	}
+}
@@ -0,0 +1,331 @@
+package modvalidate
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/turbot/go-kit/types"
+	"github.com/turbot/steampipe/steampipeconfig/modconfig"
+	"github.com/turbot/steampipe/utils"
+	"github.com/turbot/steampipe/workspace"
+)
+
+// a rule validates the loaded workspace, returning a diagnostic for each problem found
+// NOTE: rules only validate the resources of the workspace mod - dependency mods are not validated
+type rule func(w *workspace.Workspace) hcl.Diagnostics
+
+var rules = []rule{
+	validateReferences,
+	validateControlColumns,
+	validateDuplicateTitles,
+	validateBenchmarkChildren,
+	validateUnused,
+	validateRequiredPlugins,
+}
+
+// the columns which must be returned by control SQL
+var requiredControlColumns = []string{"reason", "resource", "status"}
+
+// map of each required column to the regex which matches it as a whole word
+var requiredControlColumnRegexes = wordRegexes(requiredControlColumns)
+
+// matches a select of all columns, e.g. 'select *' or 'select t.*'
+var selectAllRegex = regexp.MustCompile(`(?i)\bselect\s+(distinct\s+)?(\w+\.)?\*`)
+
+// build a map of each word to a case insensitive regex which matches it as a whole word
+func wordRegexes(words []string) map[string]*regexp.Regexp {
+	res := make(map[string]*regexp.Regexp, len(words))
+	for _, word := range words {
+		res[word] = regexp.MustCompile(fmt.Sprintf(`(?i)\b%s\b`, regexp.QuoteMeta(word)))
+	}
+	return res
+}
+
+// check that all references made by resources of the workspace mod resolve to a resource
+func validateReferences(w *workspace.Workspace) hcl.Diagnostics {
+	var diags hcl.Diagnostics
+	for _, reference := range getModReferences(w.Mod) {
+		parsedName, err := modconfig.ParseResourceName(reference.To)
+		if err != nil {
+			continue
+		}
+		var found bool
+		switch parsedName.ItemType {
+		case modconfig.BlockTypeQuery:
+			_, found = w.Queries[reference.To]
+		case modconfig.BlockTypeControl:
+			_, found = w.Controls[reference.To]
+		case modconfig.BlockTypeBenchmark:
+			_, found = w.Benchmarks[reference.To]
+		case "var":
+			_, found = w.Variables[reference.To]
+		default:
+			// other references (e.g. to params and locals) are validated when the mod is decoded
+			continue
+		}
+		if !found {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  fmt.Sprintf("Unresolved reference to %s", reference.To),
+				Detail:   fmt.Sprintf("%s '%s' references %s in its '%s' attribute, but this is not defined.", reference.BlockType, reference.BlockName, reference.To, reference.Attribute),
+				Subject:  metadataRange(reference.GetMetadata()),
+			})
+		}
+	}
+	return diags
+}
+
+// check that the SQL of each control returns the columns required for a control result
+// NOTE: this is a static check of the SQL text - a query which selects all columns is not checked
+func validateControlColumns(w *workspace.Workspace) hcl.Diagnostics {
+	var diags hcl.Diagnostics
+	for _, control := range w.Mod.Controls {
+		sql := control.SQL
+		if sql == nil && control.Query != nil {
+			sql = control.Query.SQL
+		}
+		if sql == nil {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  fmt.Sprintf("%s has no SQL", control.Name()),
+				Detail:   "A control must set either 'sql' or 'query'.",
+				Subject:  &control.DeclRange,
+			})
+			continue
+		}
+		if selectAllRegex.MatchString(*sql) {
+			continue
+		}
+
+		var missingColumns []string
+		for _, column := range requiredControlColumns {
+			if !requiredControlColumnRegexes[column].MatchString(*sql) {
+				missingColumns = append(missingColumns, column)
+			}
+		}
+		if len(missingColumns) > 0 {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  fmt.Sprintf("%s SQL is missing required %s", control.Name(), utils.Pluralize("column", len(missingColumns))),
+				Detail:   fmt.Sprintf("The control SQL must return the columns %s, but does not return %s.", strings.Join(requiredControlColumns, ", "), strings.Join(missingColumns, ", ")),
+				Subject:  &control.DeclRange,
+			})
+		}
+	}
+	return diags
+}
+
+// check for controls and benchmarks in the workspace mod with the same title
+func validateDuplicateTitles(w *workspace.Workspace) hcl.Diagnostics {
+	var diags hcl.Diagnostics
+
+	controlTitles := make(map[string][]string)
+	controlRanges := make(map[string]*hcl.Range)
+	for _, control := range w.Mod.Controls {
+		if title := types.SafeString(control.Title); title != "" {
+			controlTitles[title] = append(controlTitles[title], control.Name())
+			controlRanges[control.Name()] = &control.DeclRange
+		}
+	}
+	diags = append(diags, duplicateTitleDiagnostics("control", controlTitles, controlRanges)...)
+
+	benchmarkTitles := make(map[string][]string)
+	benchmarkRanges := make(map[string]*hcl.Range)
+	for _, benchmark := range w.Mod.Benchmarks {
+		if title := types.SafeString(benchmark.Title); title != "" {
+			benchmarkTitles[title] = append(benchmarkTitles[title], benchmark.Name())
+			benchmarkRanges[benchmark.Name()] = &benchmark.DeclRange
+		}
+	}
+	diags = append(diags, duplicateTitleDiagnostics("benchmark", benchmarkTitles, benchmarkRanges)...)
+
+	return diags
+}
+
+// return a warning for each resource which has the same title as another resource of the same type
+func duplicateTitleDiagnostics(resourceType string, titles map[string][]string, ranges map[string]*hcl.Range) hcl.Diagnostics {
+	var diags hcl.Diagnostics
+	for title, names := range titles {
+		if len(names) < 2 {
+			continue
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagWarning,
+				Summary:  fmt.Sprintf("Duplicate %s title '%s'", resourceType, title),
+				Detail:   fmt.Sprintf("The title '%s' is used by %d resources: %s.", title, len(names), strings.Join(names, ", ")),
+				Subject:  ranges[name],
+			})
+		}
+	}
+	return diags
+}
+
+// check that all children of benchmarks in the workspace mod are defined
+func validateBenchmarkChildren(w *workspace.Workspace) hcl.Diagnostics {
+	var diags hcl.Diagnostics
+	for _, benchmark := range w.Mod.Benchmarks {
+		for _, childName := range benchmark.ChildNameStrings {
+			_, isControl := w.Controls[childName]
+			_, isBenchmark := w.Benchmarks[childName]
+			if !isControl && !isBenchmark {
+				diags = append(diags, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  fmt.Sprintf("%s has unknown child %s", benchmark.Name(), childName),
+					Detail:   "Benchmark children must be controls or benchmarks.",
+					Subject:  &benchmark.DeclRange,
+				})
+			}
+		}
+	}
+	return diags
+}
+
+// check for variables and queries in the workspace mod which are never referenced
+func validateUnused(w *workspace.Workspace) hcl.Diagnostics {
+	sourceFiles, err := w.SourceFiles()
+	if err != nil {
+		return hcl.Diagnostics{{
+			Severity: hcl.DiagError,
+			Summary:  "Failed to list workspace source files",
+			Detail:   err.Error(),
+		}}
+	}
+	referenced, diags := getReferencedNames(sourceFiles)
+	// variables may also be used in SQL, with the var() function
+	for _, query := range w.Mod.Queries {
+		addSqlVariableReferences(query.SQL, referenced)
+	}
+	for _, control := range w.Mod.Controls {
+		addSqlVariableReferences(control.SQL, referenced)
+	}
+
+	for _, variable := range w.Mod.Variables {
+		if !referenced[variable.Name()] {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagWarning,
+				Summary:  fmt.Sprintf("Unused variable %s", variable.Name()),
+				Detail:   fmt.Sprintf("%s is declared but is not referenced by any resource.", variable.Name()),
+				Subject:  &variable.DeclRange,
+			})
+		}
+	}
+	for _, query := range w.Mod.Queries {
+		// queries created from sql files are intended to be run directly
+		if metadata := query.GetMetadata(); metadata != nil && metadata.IsAutoGenerated {
+			continue
+		}
+		if !referenced[query.Name()] {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagWarning,
+				Summary:  fmt.Sprintf("Unused query %s", query.Name()),
+				Detail:   fmt.Sprintf("%s is not referenced by any control or other resource.", query.Name()),
+				Subject:  &query.DeclRange,
+			})
+		}
+	}
+	return diags
+}
+
+// check that the plugins required by the workspace mod are installed, with a version satisfying the requirement
+func validateRequiredPlugins(w *workspace.Workspace) hcl.Diagnostics {
+	if w.Mod.Requires == nil || len(w.Mod.Requires.Plugins) == 0 {
+		return nil
+	}
+	installedPlugins, err := w.GetInstalledPlugins()
+	if err != nil {
+		return hcl.Diagnostics{{
+			Severity: hcl.DiagError,
+			Summary:  "Failed to list installed plugins",
+			Detail:   err.Error(),
+		}}
+	}
+
+	var diags hcl.Diagnostics
+	for _, requiredPlugin := range w.Mod.Requires.Plugins {
+		installedVersion, ok := installedPlugins[requiredPlugin.ShortName()]
+		if !ok {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  fmt.Sprintf("Required plugin %s is not installed", requiredPlugin.ShortName()),
+				Detail:   fmt.Sprintf("%s requires %s. Install it with 'steampipe plugin install %s'.", w.Mod.Name(), requiredPlugin.FullName(), requiredPlugin.ShortName()),
+				Subject:  &w.Mod.DeclRange,
+			})
+			continue
+		}
+		if requiredPlugin.Version != nil && installedVersion.LessThan(requiredPlugin.Version) {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  fmt.Sprintf("Required plugin %s version is not satisfied", requiredPlugin.ShortName()),
+				Detail:   fmt.Sprintf("%s requires version %s of %s, but version %s is installed.", w.Mod.Name(), requiredPlugin.Version.String(), requiredPlugin.ShortName(), installedVersion.String()),
+				Subject:  &w.Mod.DeclRange,
+			})
+		}
+	}
+	return diags
+}
+
+// return the references made by all resources of the mod
+func getModReferences(mod *modconfig.Mod) []*modconfig.ResourceReference {
+	references := append([]*modconfig.ResourceReference{}, mod.References...)
+	for _, query := range mod.Queries {
+		references = append(references, query.References...)
+	}
+	for _, control := range mod.Controls {
+		references = append(references, control.References...)
+	}
+	for _, benchmark := range mod.Benchmarks {
+		references = append(references, benchmark.References...)
+	}
+	return references
+}
+
+// convert resource metadata into a source range, covering all lines of the resource
+// the file is read to set the byte offsets of the range - if this fails, only the lines and columns are set
+func metadataRange(metadata *modconfig.ResourceMetadata) *hcl.Range {
+	if metadata == nil || metadata.FileName == "" {
+		return nil
+	}
+	r := &hcl.Range{
+		Filename: metadata.FileName,
+		Start:    hcl.Pos{Line: metadata.StartLineNumber, Column: 1},
+		End:      hcl.Pos{Line: metadata.EndLineNumber, Column: 1},
+	}
+	src, err := ioutil.ReadFile(metadata.FileName)
+	if err != nil {
+		return r
+	}
+	r.Start.Byte, _ = lineBounds(src, r.Start.Line)
+	lineStart, lineEnd := lineBounds(src, r.End.Line)
+	r.End.Byte = lineEnd
+	r.End.Column = utf8.RuneCount(src[lineStart:lineEnd]) + 1
+	return r
+}
+
+// return the byte offsets of the start and end of the given (1 based) line, excluding the line ending
+// if the line is beyond the end of the source, both offsets are the length of the source
+func lineBounds(src []byte, line int) (int, int) {
+	start := 0
+	for i := 1; i < line; i++ {
+		next := bytes.IndexByte(src[start:], '\n')
+		if next == -1 {
+			return len(src), len(src)
+		}
+		start += next + 1
+	}
+	end := len(src)
+	if next := bytes.IndexByte(src[start:], '\n'); next != -1 {
+		end = start + next
+	}
+	if end > start && src[end-1] == '\r' {
+		end--
+	}
+	return start, end
+}
@@ -0,0 +1,57 @@
+package modvalidate
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/turbot/steampipe/steampipeconfig/modconfig"
+)
+
+type metadataRangeTest struct {
+	startLine int
+	endLine   int
+	expected  hcl.Range
+}
+
+const metadataRangeSource = "query \"q1\" {\r\n  sql = \"select 'é'\"\n}\n"
+
+var testCasesMetadataRange = map[string]metadataRangeTest{
+	"single line": {
+		startLine: 1,
+		endLine:   1,
+		expected:  hcl.Range{Start: hcl.Pos{Line: 1, Column: 1, Byte: 0}, End: hcl.Pos{Line: 1, Column: 13, Byte: 12}},
+	},
+	"multiple lines": {
+		startLine: 1,
+		endLine:   3,
+		expected:  hcl.Range{Start: hcl.Pos{Line: 1, Column: 1, Byte: 0}, End: hcl.Pos{Line: 3, Column: 2, Byte: 37}},
+	},
+	"multibyte character": {
+		startLine: 2,
+		endLine:   2,
+		expected:  hcl.Range{Start: hcl.Pos{Line: 2, Column: 1, Byte: 14}, End: hcl.Pos{Line: 2, Column: 21, Byte: 35}},
+	},
+}
+
+func TestMetadataRange(t *testing.T) {
+	dir, err := ioutil.TempDir("", "metadata_range")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	fileName := filepath.Join(dir, "query.sp")
+	if err := ioutil.WriteFile(fileName, []byte(metadataRangeSource), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, test := range testCasesMetadataRange {
+		r := metadataRange(&modconfig.ResourceMetadata{FileName: fileName, StartLineNumber: test.startLine, EndLineNumber: test.endLine})
+		test.expected.Filename = fileName
+		if r == nil || *r != test.expected {
+			t.Errorf("Test: '%s' FAILED : expected %#v, got %#v", name, test.expected, r)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package modvalidate
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/turbot/steampipe/steampipeconfig/modconfig"
+	"github.com/turbot/steampipe/steampipeconfig/parse"
+	"github.com/turbot/steampipe/workspace"
+)
+
+// Validate loads the workspace at the given path and returns all diagnostics
+// - any errors loading the workspace mod (e.g. hcl syntax or decode errors)
+// - the results of the semantic validation rules, if the workspace loaded successfully
+func Validate(workspacePath string) hcl.Diagnostics {
+	w, err := workspace.Load(workspacePath)
+	if err != nil {
+		return LoadErrorDiagnostics(err)
+	}
+	defer w.Close()
+
+	return ValidateWorkspace(w)
+}
+
+// ValidateWorkspace returns the results of the semantic validation rules for a loaded workspace
+func ValidateWorkspace(w *workspace.Workspace) hcl.Diagnostics {
+	var diags hcl.Diagnostics
+	for _, rule := range rules {
+		diags = append(diags, rule(w)...)
+	}
+	return sortDiagnostics(diags)
+}
+
+// LoadErrorDiagnostics converts an error loading the workspace into diagnostics
+// if the error was caused by hcl diagnostics, return these, so they are reported with their source ranges
+func LoadErrorDiagnostics(err error) hcl.Diagnostics {
+	var diagsErr *parse.DiagnosticsError
+	if errors.As(err, &diagsErr) {
+		return sortDiagnostics(diagsErr.Diags)
+	}
+	var missingVariablesErr modconfig.MissingVariableError
+	if errors.As(err, &missingVariablesErr) {
+		var diags hcl.Diagnostics
+		for _, v := range missingVariablesErr.MissingVariables {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  fmt.Sprintf("No value for required variable %s", v.Name()),
+				Detail:   "Set a value using --var, --var-file or the steampipe.spvars file.",
+				Subject:  v.GetDeclRange(),
+			})
+		}
+		return sortDiagnostics(diags)
+	}
+	return hcl.Diagnostics{{
+		Severity: hcl.DiagError,
+		Summary:  "Failed to load workspace",
+		Detail:   err.Error(),
+	}}
+}
+
+// order diagnostics by file and position - diagnostics with no range are first
+func sortDiagnostics(diags hcl.Diagnostics) hcl.Diagnostics {
+	sort.SliceStable(diags, func(i, j int) bool {
+		a, b := diags[i].Subject, diags[j].Subject
+		if a == nil || b == nil {
+			return a == nil && b != nil
+		}
+		if a.Filename != b.Filename {
+			return a.Filename < b.Filename
+		}
+		return a.Start.Byte < b.Start.Byte
+	})
+	return diags
+}
@@ -87,27 +87,72 @@ func init() {
 			title:       constants.CmdOutput,
 			handler:     setViperConfigFromArg(constants.ArgOutput),
 			validator:   composeValidator(exactlyNArgs(1), validatorFromArgsOf(constants.CmdOutput)),
-			description: "Set output format: csv, json or table",
+			description: "Set output format: csv, json, line, table, markdown, html, yaml or ndjson",
 			args: []metaQueryArg{
 				{value: constants.ArgJSON, description: "Set output to JSON"},
 				{value: constants.ArgCSV, description: "Set output to CSV"},
 				{value: constants.ArgTable, description: "Set output to Table"},
 				{value: constants.ArgLine, description: "Set output to Line"},
+				{value: constants.ArgMarkdown, description: "Set output to Markdown"},
+				{value: constants.ArgHTML, description: "Set output to HTML"},
+				{value: constants.ArgYAML, description: "Set output to YAML"},
+				{value: constants.ArgNDJSON, description: "Set output to newline delimited JSON"},
 			},
 			completer: completerFromArgsOf(constants.CmdOutput),
 		},
 		constants.CmdCache: {
 			title:       constants.CmdCache,
 			handler:     cacheControl,
-			validator:   composeValidator(exactlyNArgs(1), validatorFromArgsOf(constants.CmdCache)),
-			description: "Enable, disable or clear the query cache",
+			validator:   cacheValidator,
+			description: "Enable, disable or clear the query cache, set the cache ttl or show the cache status",
 			args: []metaQueryArg{
 				{value: constants.ArgOn, description: "Turn on caching"},
 				{value: constants.ArgOff, description: "Turn off caching"},
-				{value: constants.ArgClear, description: "Clear the cache"},
+				{value: constants.ArgClear, description: "Clear the cache, or the cache for a connection or table: .cache clear <connection>[.<table>]"},
+				{value: constants.ArgTTL, description: "Set the cache ttl: .cache ttl <seconds>"},
+				{value: constants.ArgStatus, description: "Show the cache entries, size and hit rate for each connection"},
 			},
 			completer: completerFromArgsOf(constants.CmdCache),
 		},
+		constants.CmdHistory: {
+			title:       constants.CmdHistory,
+			handler:     showHistory,
+			validator:   anyArgs,
+			description: "List the query history, or search it by passing in a filter string",
+		},
+		constants.CmdExport: {
+			title:       constants.CmdExport,
+			handler:     exportLastResult,
+			validator:   exportValidator,
+			description: "Export the result of the last query to a file: .export <file> [csv|json|markdown|html|yaml|ndjson]",
+		},
+		constants.CmdTee: {
+			title:       constants.CmdTee,
+			handler:     setTee,
+			validator:   teeValidator,
+			description: "Append the results of all subsequent queries to a file: .tee on <file> or .tee off",
+			args: []metaQueryArg{
+				{value: constants.ArgOn, description: "Start writing query results to a file"},
+				{value: constants.ArgOff, description: "Stop writing query results to a file"},
+			},
+			completer: completerFromArgsOf(constants.CmdTee),
+		},
+		constants.CmdExplain: {
+			title:       constants.CmdExplain,
+			handler:     explainQuery,
+			validator:   atLeastNArgs(1),
+			description: "Run a query and show the time taken and rows returned by each connection and table it scans",
+		},
+		constants.CmdVar: {
+			title:       constants.CmdVar,
+			handler:     setVariable,
+			validator:   varValidator,
+			description: "Set the value of a mod variable for this session",
+			args: []metaQueryArg{
+				{value: constants.ArgSet, description: "Set a variable value: .var set <name> <value>"},
+			},
+			completer: completerFromArgsOf(constants.CmdVar),
+		},
 		constants.CmdInspect: {
 			title:       constants.CmdInspect,
 			handler:     inspect,
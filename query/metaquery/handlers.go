@@ -1,9 +1,11 @@
 package metaquery
 
 import (
+	"context"
 	"fmt"
 	"regexp"
 	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/c-bata/go-prompt"
@@ -13,9 +15,14 @@ import (
 	typeHelpers "github.com/turbot/go-kit/types"
 	"github.com/turbot/steampipe/cmdconfig"
 	"github.com/turbot/steampipe/constants"
+	"github.com/turbot/steampipe/db/db_common"
 	"github.com/turbot/steampipe/display"
+	"github.com/turbot/steampipe/query/queryhistory"
+	"github.com/turbot/steampipe/query/queryplan"
+	"github.com/turbot/steampipe/query/queryresult"
 	"github.com/turbot/steampipe/schema"
 	"github.com/turbot/steampipe/steampipeconfig"
+	"github.com/turbot/steampipe/utils"
 )
 
 var commonCmds = []string{constants.CmdHelp, constants.CmdInspect, constants.CmdExit}
@@ -27,6 +34,10 @@ type QueryExecutor interface {
 	CacheOn() error
 	CacheOff() error
 	CacheClear() error
+	CacheClearConnection(connection, table string) error
+	CacheSetTTL(ttlSeconds int) error
+	CacheStatus(ctx context.Context) ([]*db_common.CacheStatus, error)
+	ExecuteSync(ctx context.Context, query string, disableSpinner bool) (*queryresult.SyncQueryResult, error)
 }
 
 // HandlerInput :: input interface for the metaquery handler
@@ -37,6 +48,9 @@ type HandlerInput struct {
 	Connections *steampipeconfig.ConnectionDataMap
 	Prompt      *prompt.Prompt
 	ClosePrompt func()
+	History     *queryhistory.QueryHistory
+	// the result of the last query executed - may be nil
+	LastResult *queryresult.SyncQueryResult
 }
 type PromptControl interface {
 	Clear()
@@ -47,10 +61,10 @@ func (h *HandlerInput) args() []string {
 	return getArguments(h.Query)
 }
 
-type handler func(input *HandlerInput) error
+type handler func(ctx context.Context, input *HandlerInput) error
 
 // Handle :: handle metaquery.
-func Handle(input *HandlerInput) error {
+func Handle(ctx context.Context, input *HandlerInput) error {
 	input.Query = strings.TrimSuffix(input.Query, ";")
 	var s = strings.Fields(input.Query)
 
@@ -61,10 +75,10 @@ func Handle(input *HandlerInput) error {
 	}
 
 	handlerFunction = metaQueryObj.handler
-	return handlerFunction(input)
+	return handlerFunction(ctx, input)
 }
 
-func setOrGetSearchPath(input *HandlerInput) error {
+func setOrGetSearchPath(ctx context.Context, input *HandlerInput) error {
 	if len(input.args()) == 0 {
 		currentPath, err := input.Executor.GetCurrentSearchPath()
 		if err != nil {
@@ -96,7 +110,7 @@ func setOrGetSearchPath(input *HandlerInput) error {
 	return nil
 }
 
-func setSearchPathPrefix(input *HandlerInput) error {
+func setSearchPathPrefix(ctx context.Context, input *HandlerInput) error {
 	arg := input.args()[0]
 	paths := []string{}
 	split := strings.Split(arg, ",")
@@ -112,41 +126,100 @@ func setSearchPathPrefix(input *HandlerInput) error {
 }
 
 // set the ArgHeader viper key with the boolean value evaluated from arg[0]
-func setHeader(input *HandlerInput) error {
+func setHeader(ctx context.Context, input *HandlerInput) error {
 	cmdconfig.Viper().Set(constants.ArgHeader, typeHelpers.StringToBool(input.args()[0]))
 	return nil
 }
 
 // set the ArgMulti viper key with the boolean value evaluated from arg[0]
-func setMultiLine(input *HandlerInput) error {
+func setMultiLine(ctx context.Context, input *HandlerInput) error {
 	cmdconfig.Viper().Set(constants.ArgMultiLine, typeHelpers.StringToBool(input.args()[0]))
 	return nil
 }
 
 // controls the cache in the connected FDW
-func cacheControl(input *HandlerInput) error {
-	command := input.args()[0]
+func cacheControl(ctx context.Context, input *HandlerInput) error {
+	args := input.args()
+	command := args[0]
 	switch command {
 	case constants.ArgOn:
 		return input.Executor.CacheOn()
 	case constants.ArgOff:
 		return input.Executor.CacheOff()
 	case constants.ArgClear:
+		// if a connection (or connection.table) is specified, just clear the cache for that
+		if len(args) > 1 {
+			connection, table := parseCacheTarget(args[1])
+			return input.Executor.CacheClearConnection(connection, table)
+		}
 		return input.Executor.CacheClear()
+	case constants.ArgTTL:
+		// the validator has already checked this is a valid number
+		ttl, _ := strconv.Atoi(args[1])
+		return input.Executor.CacheSetTTL(ttl)
+	case constants.ArgStatus:
+		return showCacheStatus(ctx, input)
 	}
 
 	return fmt.Errorf("invalid command")
 }
 
+// split a cache target of the form <connection>[.<table>]
+func parseCacheTarget(target string) (connection, table string) {
+	parts := strings.SplitN(target, ".", 2)
+	if len(parts) == 2 {
+		return parts[0], parts[1]
+	}
+	return parts[0], ""
+}
+
+// display the cache entries, size and hit rate for each connection
+func showCacheStatus(ctx context.Context, input *HandlerInput) error {
+	statuses, err := input.Executor.CacheStatus(ctx)
+	if err != nil {
+		return err
+	}
+	if len(statuses) == 0 {
+		fmt.Println("The cache is empty")
+		return nil
+	}
+	header := []string{"connection", "entries", "size", "hit rate"}
+	var rows [][]string
+	for _, status := range statuses {
+		rows = append(rows, []string{
+			status.Connection,
+			strconv.Itoa(status.Entries),
+			formatBytes(status.Size),
+			fmt.Sprintf("%.1f%%", status.HitRate()),
+		})
+	}
+	display.ShowWrappedTable(header, rows, false)
+	return nil
+}
+
+// format a size in bytes using the largest appropriate unit
+func formatBytes(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(size)/float64(div), "KMGTPE"[exp])
+}
+
 // set the ArgHeader viper key with the boolean value evaluated from arg[0]
-func setTiming(input *HandlerInput) error {
+func setTiming(ctx context.Context, input *HandlerInput) error {
 	cmdconfig.Viper().Set(constants.ArgTimer, typeHelpers.StringToBool(input.args()[0]))
 	return nil
 }
 
 // set the value of `viperKey` in `viper` with the value from `args[0]`
 func setViperConfigFromArg(viperKey string) handler {
-	return func(input *HandlerInput) error {
+	return func(ctx context.Context, input *HandlerInput) error {
 		cmdconfig.Viper().Set(viperKey, input.args()[0])
 		return nil
 	}
@@ -154,20 +227,20 @@ func setViperConfigFromArg(viperKey string) handler {
 
 // set the value of `viperKey` in `viper` with a static value
 func setViperConfig(viperKey string, value interface{}) handler {
-	return func(input *HandlerInput) error {
+	return func(ctx context.Context, input *HandlerInput) error {
 		cmdconfig.Viper().Set(viperKey, value)
 		return nil
 	}
 }
 
 // exit
-func doExit(input *HandlerInput) error {
+func doExit(ctx context.Context, input *HandlerInput) error {
 	input.ClosePrompt()
 	return nil
 }
 
 // help
-func doHelp(input *HandlerInput) error {
+func doHelp(ctx context.Context, input *HandlerInput) error {
 	commonCmdRows := getMetaQueryHelpRows(commonCmds, false)
 	var advanceCmds []string
 	for cmd := range metaQueryDefinitions {
@@ -209,7 +282,7 @@ func getMetaQueryHelpRows(cmds []string, arrange bool) [][]string {
 }
 
 // list all the tables in the schema
-func listTables(input *HandlerInput) error {
+func listTables(ctx context.Context, input *HandlerInput) error {
 
 	if len(input.args()) == 0 {
 		schemas := input.Schema.GetSchemas()
@@ -260,9 +333,9 @@ To get information about the columns in a table, run %s
 }
 
 // inspect
-func inspect(input *HandlerInput) error {
+func inspect(ctx context.Context, input *HandlerInput) error {
 	if len(input.args()) == 0 {
-		return listConnections(input)
+		return listConnections(ctx, input)
 	}
 	// arg can be one of <connection_name> or <connection_name>.<table_name>
 	tableOrConnection := input.args()[0]
@@ -302,7 +375,7 @@ To get information about the columns in a table, run %s
 	return inspectTable(split[0], split[1], input)
 }
 
-func listConnections(input *HandlerInput) error {
+func listConnections(ctx context.Context, input *HandlerInput) error {
 	header := []string{"connection", "plugin"}
 	rows := [][]string{}
 
@@ -358,7 +431,99 @@ func inspectConnection(connectionName string, input *HandlerInput) bool {
 	return true
 }
 
-func clearScreen(input *HandlerInput) error {
+// list the query history, optionally filtered by a search string
+func showHistory(ctx context.Context, input *HandlerInput) error {
+	filter := strings.Join(input.args(), " ")
+	display.ShowQueryHistory(input.History.Search(filter))
+	return nil
+}
+
+// export the result of the last query to a file
+// the format is taken from args[1] if given, otherwise it is inferred from the file name
+func exportLastResult(ctx context.Context, input *HandlerInput) error {
+	if input.LastResult == nil {
+		return fmt.Errorf("there is no query result to export")
+	}
+	args := input.args()
+	fileName := args[0]
+	format, err := getExportFormat(args)
+	if err != nil {
+		return err
+	}
+	if err := display.ExportResult(input.LastResult.Stream(), fileName, format, false); err != nil {
+		return err
+	}
+	fmt.Printf("Exported %d %s to %s\n", len(input.LastResult.Rows), utils.Pluralize("row", len(input.LastResult.Rows)), fileName)
+	return nil
+}
+
+// set the ArgTee viper key to the file name given in args[1], or clear it if tee is being turned off
+func setTee(ctx context.Context, input *HandlerInput) error {
+	args := input.args()
+	if args[0] == constants.ArgOff {
+		cmdconfig.Viper().Set(constants.ArgTee, "")
+		return nil
+	}
+	cmdconfig.Viper().Set(constants.ArgTee, args[1])
+	return nil
+}
+
+// run EXPLAIN ANALYZE for the query passed as the argument and display a summary of the foreign table scans
+func explainQuery(ctx context.Context, input *HandlerInput) error {
+	query := strings.TrimSpace(strings.TrimPrefix(input.Query, constants.CmdExplain))
+	plan, err := queryplan.Explain(ctx, input.Executor, query)
+	if err != nil {
+		return err
+	}
+	display.ShowQueryPlan(plan)
+	return nil
+}
+
+// set the session value of a mod variable: .var set <name> <value>
+// the steampipe_variable introspection table is updated, so the new value is returned by the var() sql function,
+// and the value is added to the variable args, so it is retained if the workspace is reloaded
+func setVariable(ctx context.Context, input *HandlerInput) error {
+	args := input.args()
+	name := strings.TrimPrefix(args[1], "var.")
+	value := strings.Join(args[2:], " ")
+
+	// string variables are stored as a json string - for all other types, the value must be valid json
+	// the values of sensitive variables are redacted
+	sql := fmt.Sprintf(`update %s set
+  value = case when sensitive then to_jsonb(%s::text) when var_type = 'string' then to_jsonb(%s::text) else %s::jsonb end,
+  value_source = 'session',
+  value_source_file_name = null,
+  value_source_start_line_number = null,
+  value_source_end_line_number = null
+where name = %s
+returning name, sensitive`,
+		constants.IntrospectionTableVariable,
+		db_common.PgEscapeString(utils.SensitiveValueRedacted),
+		db_common.PgEscapeString(value),
+		db_common.PgEscapeString(value),
+		db_common.PgEscapeString(fmt.Sprintf("var.%s", name)))
+
+	result, err := input.Executor.ExecuteSync(ctx, sql, true)
+	if err != nil {
+		return err
+	}
+	if len(result.Rows) == 0 {
+		return fmt.Errorf("variable '%s' is not defined", name)
+	}
+	row := result.Rows[0].(*queryresult.RowResult)
+	if row.Error != nil {
+		return row.Error
+	}
+	if sensitive, _ := row.Data[1].(bool); sensitive {
+		utils.AddSensitiveValues(value)
+	}
+
+	variableArgs := append(cmdconfig.Viper().GetStringSlice(constants.ArgVariable), fmt.Sprintf("%s=%s", name, value))
+	cmdconfig.Viper().Set(constants.ArgVariable, variableArgs)
+	return nil
+}
+
+func clearScreen(ctx context.Context, input *HandlerInput) error {
 	input.Prompt.ClearScreen()
 	return nil
 }
@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/c-bata/go-prompt"
+	"github.com/turbot/steampipe/display"
 )
 
 // IsMetaQuery :: returns true if the query is a metaquery, false otherwise
@@ -39,3 +40,12 @@ func PromptSuggestions() []prompt.Suggest {
 func getArguments(query string) []string {
 	return strings.Fields(strings.TrimSpace(query))[1:]
 }
+
+// return the export format for the '.export <file> [format]' metaquery args
+// if no format is given, infer it from the file name
+func getExportFormat(args []string) (string, error) {
+	if len(args) > 1 {
+		return args[1], display.ValidateExportFormat(args[1])
+	}
+	return display.InferExportFormatFromFileName(args[0])
+}
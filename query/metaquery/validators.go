@@ -2,10 +2,12 @@ package metaquery
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/turbot/steampipe/cmdconfig"
 	"github.com/turbot/steampipe/constants"
+	"github.com/turbot/steampipe/display"
 
 	"github.com/turbot/go-kit/helpers"
 )
@@ -105,6 +107,19 @@ var atMostNArgs = func(n int) validator {
 	}
 }
 
+var atLeastNArgs = func(n int) validator {
+	return func(val string) ValidationResult {
+		args := strings.Fields(strings.TrimSpace(val))
+		numArgs := len(args)
+		if numArgs < n {
+			return ValidationResult{
+				Err: fmt.Errorf("command needs at least %d argument(s) - got %d", n, numArgs),
+			}
+		}
+		return ValidationResult{ShouldRun: true}
+	}
+}
+
 var exactlyNArgs = func(n int) validator {
 	return func(val string) ValidationResult {
 		args := strings.Fields(strings.TrimSpace(val))
@@ -122,6 +137,97 @@ var exactlyNArgs = func(n int) validator {
 
 var noArgs = exactlyNArgs(0)
 
+var anyArgs = func(val string) ValidationResult {
+	return ValidationResult{ShouldRun: true}
+}
+
+// validate the arguments of the '.export <file> [format]' metaquery
+var exportValidator = func(val string) ValidationResult {
+	args := strings.Fields(strings.TrimSpace(val))
+	if len(args) == 0 || len(args) > 2 {
+		return ValidationResult{
+			Err: fmt.Errorf("command needs 1 or 2 argument(s) - got %d", len(args)),
+		}
+	}
+	if _, err := getExportFormat(args); err != nil {
+		return ValidationResult{Err: err}
+	}
+	return ValidationResult{ShouldRun: true}
+}
+
+// validate the arguments of the '.tee on <file>' and '.tee off' metaqueries
+// if there are no arguments, show the current tee status
+var teeValidator = func(val string) ValidationResult {
+	args := strings.Fields(strings.TrimSpace(val))
+	if len(args) == 0 {
+		if teeFile := cmdconfig.Viper().GetString(constants.ArgTee); teeFile != "" {
+			return ValidationResult{
+				Message: fmt.Sprintf(`tee is %s, writing to %s. You can disable it with: %s `,
+					constants.Bold(constants.ArgOn),
+					teeFile,
+					constants.Bold(fmt.Sprintf("%s %s", constants.CmdTee, constants.ArgOff))),
+			}
+		}
+		return ValidationResult{
+			Message: fmt.Sprintf(`tee is %s. You can enable it with: %s `,
+				constants.Bold(constants.ArgOff),
+				constants.Bold(fmt.Sprintf("%s %s <file>", constants.CmdTee, constants.ArgOn))),
+		}
+	}
+	if res := validatorFromArgsOf(constants.CmdTee)(args[0]); res.Err != nil {
+		return res
+	}
+	if args[0] == constants.ArgOff {
+		return exactlyNArgs(1)(val)
+	}
+	if len(args) != 2 {
+		return ValidationResult{
+			Err: fmt.Errorf("command needs 2 argument(s) - got %d", len(args)),
+		}
+	}
+	if _, err := display.InferExportFormatFromFileName(args[1]); err != nil {
+		return ValidationResult{Err: err}
+	}
+	return ValidationResult{ShouldRun: true}
+}
+
+// validate the arguments of the '.cache' metaquery
+// clear may be followed by a <connection>[.<table>] and ttl must be followed by a number of seconds
+var cacheValidator = func(val string) ValidationResult {
+	args := strings.Fields(strings.TrimSpace(val))
+	if len(args) == 0 {
+		return ValidationResult{Err: fmt.Errorf("command needs at least 1 argument(s) - got 0")}
+	}
+	if res := validatorFromArgsOf(constants.CmdCache)(args[0]); res.Err != nil {
+		return res
+	}
+	switch args[0] {
+	case constants.ArgClear:
+		return atMostNArgs(2)(val)
+	case constants.ArgTTL:
+		if res := exactlyNArgs(2)(val); res.Err != nil {
+			return res
+		}
+		if ttl, err := strconv.Atoi(args[1]); err != nil || ttl <= 0 {
+			return ValidationResult{Err: fmt.Errorf("cache ttl must be a positive number of seconds - got %s", args[1])}
+		}
+		return ValidationResult{ShouldRun: true}
+	default:
+		return exactlyNArgs(1)(val)
+	}
+}
+
+var varValidator = func(val string) ValidationResult {
+	args := strings.Fields(strings.TrimSpace(val))
+	if len(args) == 0 {
+		return ValidationResult{Err: fmt.Errorf("command needs at least 3 argument(s) - got 0")}
+	}
+	if res := validatorFromArgsOf(constants.CmdVar)(args[0]); res.Err != nil {
+		return res
+	}
+	return atLeastNArgs(3)(val)
+}
+
 var allowedArgValues = func(caseSensitive bool, allowedValues ...string) validator {
 	return func(val string) ValidationResult {
 		if !caseSensitive {
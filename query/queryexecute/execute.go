@@ -10,6 +10,7 @@ import (
 	"github.com/turbot/steampipe/constants"
 	"github.com/turbot/steampipe/display"
 	"github.com/turbot/steampipe/interactive"
+	"github.com/turbot/steampipe/query/queryplan"
 	"github.com/turbot/steampipe/utils"
 )
 
@@ -30,6 +31,10 @@ func RunInteractiveSession(initChan *chan *db_common.QueryInitData) {
 }
 
 func RunBatchSession(ctx context.Context, initDataChan chan *db_common.QueryInitData) int {
+	// parse the export args before waiting for init, so we fail fast if they are invalid
+	exporter, err := newResultExporter()
+	utils.FailOnError(err)
+
 	// wait for init
 	initData := <-initDataChan
 	if err := initData.Result.Error; err != nil {
@@ -47,21 +52,23 @@ func RunBatchSession(ctx context.Context, initDataChan chan *db_common.QueryInit
 
 	failures := 0
 	if len(initData.Queries) > 0 {
+		// if there is more than one query, the results of each query are exported to a separate file, unless combined
+		exporter.multiQuery = len(initData.Queries) > 1
 		// if we have resolved any queries, run them
-		failures = executeQueries(ctx, initData.Queries, initData.Client)
+		failures = executeQueries(ctx, initData.Queries, initData.Client, exporter)
 	}
 	// set global exit code
 	return failures
 }
 
-func executeQueries(ctx context.Context, queries []string, client db_common.Client) int {
+func executeQueries(ctx context.Context, queries []string, client db_common.Client, exporter *resultExporter) int {
 	utils.LogTime("queryexecute.executeQueries start")
 	defer utils.LogTime("queryexecute.executeQueries end")
 
 	// run all queries
 	failures := 0
 	for i, q := range queries {
-		if err := executeQuery(ctx, q, client); err != nil {
+		if err := executeQuery(ctx, q, client, exporter, i); err != nil {
 			failures++
 			utils.ShowWarning(fmt.Sprintf("executeQueries: query %d of %d failed: %v", i+1, len(queries), err))
 		}
@@ -74,22 +81,42 @@ func executeQueries(ctx context.Context, queries []string, client db_common.Clie
 	return failures
 }
 
-func executeQuery(ctx context.Context, queryString string, client db_common.Client) error {
+func executeQuery(ctx context.Context, queryString string, client db_common.Client, exporter *resultExporter, queryIdx int) error {
 	utils.LogTime("query.execute.executeQuery start")
 	defer utils.LogTime("query.execute.executeQuery end")
 
+	// if the analyze flag is set, show the query plan summary rather than the results
+	if viper.GetBool(constants.ArgAnalyze) {
+		return analyzeQuery(ctx, queryString, client)
+	}
+
 	// the db executor sends result data over resultsStreamer
 	resultsStreamer, err := db_common.ExecuteQuery(ctx, queryString, client)
 	if err != nil {
 		return err
 	}
 
-	// print the data as it comes
+	// print the data as it comes, streaming it to any export files as well
+	var exportErrors []error
 	for r := range resultsStreamer.Results {
+		r, exportErrorChan := exporter.export(r, queryIdx)
 		display.ShowOutput(r)
+		for err := range exportErrorChan {
+			exportErrors = append(exportErrors, err)
+		}
 		// signal to the resultStreamer that we are done with this result
 		resultsStreamer.AllResultsRead()
 	}
+	return utils.CombineErrors(exportErrors...)
+}
+
+// run EXPLAIN ANALYZE for the query and display a summary of the foreign table scans
+func analyzeQuery(ctx context.Context, queryString string, client db_common.Client) error {
+	plan, err := queryplan.Explain(ctx, client, queryString)
+	if err != nil {
+		return err
+	}
+	display.ShowQueryPlan(plan)
 	return nil
 }
 
@@ -0,0 +1,111 @@
+package queryexecute
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/viper"
+	"github.com/turbot/go-kit/helpers"
+	"github.com/turbot/steampipe/constants"
+	"github.com/turbot/steampipe/display"
+	"github.com/turbot/steampipe/query/queryresult"
+)
+
+// exportTarget :: a file which query results are exported to, and the format to write
+type exportTarget struct {
+	Format   string
+	FileName string
+}
+
+// resultExporter :: streams query results to the files specified by the --export arg
+type resultExporter struct {
+	targets []exportTarget
+	// set when running more than one query - unless the results are combined,
+	// the results of each query are written to a separate file
+	multiQuery bool
+	// if set, the results of all queries are written to the same file, for the formats which allow this
+	combine bool
+	// the files which have been written to - the first write to a file truncates it, subsequent writes append
+	written map[string]bool
+}
+
+// newResultExporter parses the --export args into export targets
+// each arg may either be a file name, in which case the format is inferred from the extension,
+// or of the form <format>:<file name>
+func newResultExporter() (*resultExporter, error) {
+	exporter := &resultExporter{
+		combine: viper.GetBool(constants.ArgExportCombined),
+		written: make(map[string]bool),
+	}
+	for _, export := range viper.GetStringSlice(constants.ArgExport) {
+		export = strings.TrimSpace(export)
+		if len(export) == 0 {
+			// if this is an empty string, ignore
+			continue
+		}
+
+		var format, fileName string
+		var err error
+		if parts := strings.SplitN(export, ":", 2); len(parts) == 2 && display.ValidateExportFormat(parts[0]) == nil {
+			format = parts[0]
+			fileName = parts[1]
+		} else {
+			fileName = export
+			if format, err = display.InferExportFormatFromFileName(fileName); err != nil {
+				return nil, err
+			}
+		}
+		if fileName, err = helpers.Tildefy(fileName); err != nil {
+			return nil, err
+		}
+		exporter.targets = append(exporter.targets, exportTarget{Format: format, FileName: fileName})
+	}
+	return exporter, nil
+}
+
+// export streams the result to all export targets
+// it returns a Result streaming the same rows, which must be read in full (i.e. displayed),
+// and a channel which receives any export errors and is closed once all exports are complete
+func (e *resultExporter) export(result *queryresult.Result, queryIdx int) (*queryresult.Result, chan error) {
+	errorChan := make(chan error, len(e.targets))
+	if len(e.targets) == 0 {
+		close(errorChan)
+		return result, errorChan
+	}
+
+	results := result.Tee(len(e.targets) + 1)
+	doneChan := make(chan bool, len(e.targets))
+	for i, target := range e.targets {
+		fileName := e.fileName(target, queryIdx)
+		appendToFile := e.written[fileName]
+		e.written[fileName] = true
+
+		go func(exportResult *queryresult.Result, target exportTarget, fileName string) {
+			// ExportResult reads the result in full even if it fails, so the other teed results are not blocked
+			if err := display.ExportResult(exportResult, fileName, target.Format, appendToFile); err != nil {
+				errorChan <- fmt.Errorf("failed to export to %s: %v", fileName, err)
+			}
+			doneChan <- true
+		}(results[i+1], target, fileName)
+	}
+	// close the error channel once all exports are complete
+	go func() {
+		for range e.targets {
+			<-doneChan
+		}
+		close(errorChan)
+	}()
+
+	return results[0], errorChan
+}
+
+// return the file to write the results of the given query to
+// if we are exporting per query, add the query number to the file name
+func (e *resultExporter) fileName(target exportTarget, queryIdx int) string {
+	if !e.multiQuery || (e.combine && display.CanCombineExports(target.Format)) {
+		return target.FileName
+	}
+	extension := filepath.Ext(target.FileName)
+	return fmt.Sprintf("%s-%d%s", strings.TrimSuffix(target.FileName, extension), queryIdx+1, extension)
+}
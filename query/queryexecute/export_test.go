@@ -0,0 +1,55 @@
+package queryexecute
+
+import (
+	"testing"
+
+	"github.com/turbot/steampipe/constants"
+)
+
+type exportFileNameTest struct {
+	multiQuery bool
+	combine    bool
+	target     exportTarget
+	expected   []string
+}
+
+var testCasesExportFileName = map[string]exportFileNameTest{
+	"single query": {
+		target:   exportTarget{Format: constants.ArgJSON, FileName: "out.json"},
+		expected: []string{"out.json"},
+	},
+	"multiple queries": {
+		multiQuery: true,
+		target:     exportTarget{Format: constants.ArgCSV, FileName: "out.csv"},
+		expected:   []string{"out-1.csv", "out-2.csv"},
+	},
+	"multiple queries combined": {
+		multiQuery: true,
+		combine:    true,
+		target:     exportTarget{Format: constants.ArgCSV, FileName: "out.csv"},
+		expected:   []string{"out.csv", "out.csv"},
+	},
+	"multiple queries combined html": {
+		multiQuery: true,
+		combine:    true,
+		target:     exportTarget{Format: constants.ArgHTML, FileName: "out.html"},
+		expected:   []string{"out.html", "out.html"},
+	},
+	"multiple queries combined json": {
+		multiQuery: true,
+		combine:    true,
+		target:     exportTarget{Format: constants.ArgJSON, FileName: "out.json"},
+		expected:   []string{"out-1.json", "out-2.json"},
+	},
+}
+
+func TestExportFileName(t *testing.T) {
+	for name, test := range testCasesExportFileName {
+		exporter := &resultExporter{multiQuery: test.multiQuery, combine: test.combine}
+		for queryIdx, expected := range test.expected {
+			if fileName := exporter.fileName(test.target, queryIdx); fileName != expected {
+				t.Errorf("Test: '%s' FAILED : expected %s for query %d, got %s", name, expected, queryIdx+1, fileName)
+			}
+		}
+	}
+}
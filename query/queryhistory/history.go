@@ -2,35 +2,69 @@ package queryhistory
 
 import (
 	"encoding/json"
+	"log"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
+	"unicode/utf8"
 
+	"github.com/spf13/viper"
 	"github.com/turbot/steampipe/constants"
 )
 
+// HistoryEntry :: a single query stored in the history, along with metadata about its execution
+type HistoryEntry struct {
+	Query     string        `json:"query"`
+	Timestamp time.Time     `json:"timestamp"`
+	Workspace string        `json:"workspace,omitempty"`
+	Duration  time.Duration `json:"duration,omitempty"`
+	RowCount  int           `json:"row_count,omitempty"`
+	Error     string        `json:"error,omitempty"`
+}
+
+// Matches returns whether the query or error of the entry contains the filter string (case insensitive)
+func (e *HistoryEntry) Matches(filter string) bool {
+	filter = strings.ToLower(filter)
+	return strings.Contains(strings.ToLower(e.Query), filter) || strings.Contains(strings.ToLower(e.Error), filter)
+}
+
 // QueryHistory :: struct for working with history in the interactive mode
 type QueryHistory struct {
-	history []string
+	history []*HistoryEntry
+	// the path of the file the history is persisted to
+	path string
 }
 
 // New creates a new QueryHistory object
+// if the workspace-history option is set, the history is stored in the workspace data folder,
+// otherwise it is stored in the steampipe internal folder
 func New() *QueryHistory {
-	history := new(QueryHistory)
+	history := &QueryHistory{path: historyFilePath()}
 	history.load()
 	return history
 }
 
-// Push adds a string to the history queue trimming to maxHistorySize if necessary
-func (q *QueryHistory) Push(query string) {
+// Push adds a query to the history queue trimming to maxHistorySize if necessary
+// it returns the history entry, or nil if the query was not stored,
+// so the caller may populate the execution metadata once the query has run
+func (q *QueryHistory) Push(query string) *HistoryEntry {
 	if len(strings.TrimSpace(query)) == 0 {
 		// do not store a blank query
-		return
+		return nil
+	}
+
+	entry := &HistoryEntry{
+		Query:     query,
+		Timestamp: time.Now(),
+		Workspace: viper.GetString(constants.ArgWorkspace),
 	}
 
 	// do a strict compare to see if we have this same exact query as the most recent history item
-	if lastElement := q.Peek(); lastElement != nil && (*lastElement) == query {
-		return
+	// if so, replace it, so the metadata reflects the latest execution
+	if lastElement := q.Peek(); lastElement != nil && lastElement.Query == query {
+		q.history[len(q.history)-1] = entry
+		return entry
 	}
 
 	// limit the history length to HistorySize
@@ -40,16 +74,17 @@ func (q *QueryHistory) Push(query string) {
 	}
 
 	// append the new entry
-	q.history = append(q.history, query)
+	q.history = append(q.history, entry)
+	return entry
 }
 
 // Peek returns the last element of the history stack.
 // returns nil if there is no history
-func (q *QueryHistory) Peek() *string {
+func (q *QueryHistory) Peek() *HistoryEntry {
 	if len(q.history) == 0 {
 		return nil
 	}
-	return &q.history[len(q.history)-1]
+	return q.history[len(q.history)-1]
 }
 
 // Persist writes the history to the filesystem
@@ -59,8 +94,10 @@ func (q *QueryHistory) Persist() error {
 	defer func() {
 		file.Close()
 	}()
-	path := filepath.Join(constants.InternalDir(), constants.HistoryFile)
-	file, err = os.Create(path)
+	if err = os.MkdirAll(filepath.Dir(q.path), 0755); err != nil {
+		return err
+	}
+	file, err = os.Create(q.path)
 	if err != nil {
 		return err
 	}
@@ -73,21 +110,101 @@ func (q *QueryHistory) Persist() error {
 	return jsonEncoder.Encode(q.history)
 }
 
-// Get returns the full history
+// Get returns the full history as a list of query strings
 func (q *QueryHistory) Get() []string {
+	res := make([]string, len(q.history))
+	for i, entry := range q.history {
+		res[i] = entry.Query
+	}
+	return res
+}
+
+// Entries returns all history entries
+func (q *QueryHistory) Entries() []*HistoryEntry {
 	return q.history
 }
 
+// Search returns all history entries which match the filter string
+// if the filter is empty, all entries are returned
+func (q *QueryHistory) Search(filter string) []*HistoryEntry {
+	if filter == "" {
+		return q.history
+	}
+	var res []*HistoryEntry
+	for _, entry := range q.history {
+		if entry.Matches(filter) {
+			res = append(res, entry)
+		}
+	}
+	return res
+}
+
+// FuzzySearch returns the distinct queries in the history which match the search term, most recent first
+// queries which contain the term are returned ahead of queries which only contain the characters of the term in order
+// if the term is empty, all queries are returned
+func (q *QueryHistory) FuzzySearch(term string) []string {
+	term = strings.ToLower(term)
+	var exactMatches, fuzzyMatches []string
+	found := make(map[string]bool)
+	for i := len(q.history) - 1; i >= 0; i-- {
+		query := q.history[i].Query
+		if found[query] {
+			continue
+		}
+		lowerQuery := strings.ToLower(query)
+		if strings.Contains(lowerQuery, term) {
+			exactMatches = append(exactMatches, query)
+			found[query] = true
+		} else if fuzzyMatch(lowerQuery, term) {
+			fuzzyMatches = append(fuzzyMatches, query)
+			found[query] = true
+		}
+	}
+	return append(exactMatches, fuzzyMatches...)
+}
+
+// return whether all characters of term appear in text, in order
+func fuzzyMatch(text, term string) bool {
+	for _, c := range term {
+		idx := strings.IndexRune(text, c)
+		if idx == -1 {
+			return false
+		}
+		text = text[idx+utf8.RuneLen(c):]
+	}
+	return true
+}
+
 // loads up the history from the file where it is persisted
 func (q *QueryHistory) load() error {
-	path := filepath.Join(constants.InternalDir(), constants.HistoryFile)
-	file, err := os.Open(path)
+	q.history = []*HistoryEntry{}
+
+	fileBytes, err := os.ReadFile(q.path)
 	if err != nil {
-		q.history = []string{}
 		return err
 	}
-	defer file.Close()
 
-	decoder := json.NewDecoder(file)
-	return decoder.Decode(&q.history)
+	if err := json.Unmarshal(fileBytes, &q.history); err == nil {
+		return nil
+	}
+
+	// the history file may be in the legacy format - a list of query strings
+	// (clear any partially decoded entries first)
+	q.history = []*HistoryEntry{}
+	var legacyHistory []string
+	if err := json.Unmarshal(fileBytes, &legacyHistory); err != nil {
+		log.Printf("[WARN] failed to load query history from %s: %v", q.path, err)
+		return err
+	}
+	for _, query := range legacyHistory {
+		q.history = append(q.history, &HistoryEntry{Query: query})
+	}
+	return nil
+}
+
+func historyFilePath() string {
+	if viper.GetBool(constants.ArgWorkspaceHistory) {
+		return filepath.Join(viper.GetString(constants.ArgWorkspace), constants.WorkspaceDataDir, constants.HistoryFile)
+	}
+	return filepath.Join(constants.InternalDir(), constants.HistoryFile)
 }
@@ -0,0 +1,97 @@
+package queryhistory
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadLegacyHistory(t *testing.T) {
+	dir, err := os.MkdirTemp("", "history")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "history.json")
+	if err := os.WriteFile(path, []byte(`["select 1","select 2"]`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	history := &QueryHistory{path: path}
+	if err := history.load(); err != nil {
+		t.Fatalf("Test: 'legacy history' FAILED : unexpected error %v", err)
+	}
+	got := history.Get()
+	if len(got) != 2 || got[0] != "select 1" || got[1] != "select 2" {
+		t.Errorf("Test: 'legacy history' FAILED : expected [select 1 select 2], got %v", got)
+	}
+
+	// now persist and reload in the new format
+	history.Push("select 3").RowCount = 10
+	if err := history.Persist(); err != nil {
+		t.Fatal(err)
+	}
+	reloaded := &QueryHistory{path: path}
+	if err := reloaded.load(); err != nil {
+		t.Fatalf("Test: 'reload history' FAILED : unexpected error %v", err)
+	}
+	if len(reloaded.Entries()) != 3 || reloaded.Peek().RowCount != 10 {
+		t.Errorf("Test: 'reload history' FAILED : metadata was not persisted")
+	}
+}
+
+func TestSearchHistory(t *testing.T) {
+	history := &QueryHistory{}
+	history.Push("select * from aws_s3_bucket")
+	history.Push("select * from aws_iam_user").Error = "relation does not exist"
+	history.Push("select 1")
+	// pushing the same query again should not add a new entry
+	history.Push("select 1")
+
+	testCases := map[string]int{
+		"":               3,
+		"AWS":            2,
+		"s3":             1,
+		"does not exist": 1,
+		"azure":          0,
+	}
+	for filter, expected := range testCases {
+		if got := len(history.Search(filter)); got != expected {
+			t.Errorf("Test: '%s' FAILED : expected %d entries, got %d", filter, expected, got)
+		}
+	}
+}
+
+func TestFuzzySearchHistory(t *testing.T) {
+	history := &QueryHistory{}
+	history.Push("select * from aws_s3_bucket")
+	history.Push("select 1")
+	history.Push("select * from aws_s3_bucket")
+	history.Push("select name from aws_iam_user")
+
+	testCases := map[string][]string{
+		"s3":  {"select * from aws_s3_bucket"},
+		"IAM": {"select name from aws_iam_user"},
+		// 'aws_user' only matches fuzzily
+		"aws_user": {"select name from aws_iam_user"},
+		// distinct matches are returned most recent first
+		"from": {"select name from aws_iam_user", "select * from aws_s3_bucket"},
+		// exact matches are returned ahead of fuzzy matches
+		"_s":    {"select * from aws_s3_bucket", "select name from aws_iam_user"},
+		"azure": nil,
+	}
+	for term, expected := range testCases {
+		got := history.FuzzySearch(term)
+		if len(got) != len(expected) {
+			t.Errorf("Test: '%s' FAILED : expected %v, got %v", term, expected, got)
+			continue
+		}
+		for i := range expected {
+			if got[i] != expected[i] {
+				t.Errorf("Test: '%s' FAILED : expected %v, got %v", term, expected, got)
+				break
+			}
+		}
+	}
+}
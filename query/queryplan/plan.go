@@ -0,0 +1,157 @@
+package queryplan
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/turbot/steampipe/query/queryresult"
+)
+
+const foreignScanNodeType = "Foreign Scan"
+
+// Executor :: the interface used to run the explain statement
+type Executor interface {
+	ExecuteSync(ctx context.Context, query string, disableSpinner bool) (*queryresult.SyncQueryResult, error)
+}
+
+// QueryPlan :: the result of running EXPLAIN (ANALYZE, FORMAT JSON) for a query
+type QueryPlan struct {
+	Plan *PlanNode `json:"Plan"`
+	// planning and execution times in milliseconds
+	PlanningTime  float64 `json:"Planning Time"`
+	ExecutionTime float64 `json:"Execution Time"`
+}
+
+// PlanNode :: a node of the query plan
+type PlanNode struct {
+	NodeType     string `json:"Node Type"`
+	RelationName string `json:"Relation Name"`
+	Schema       string `json:"Schema"`
+	Alias        string `json:"Alias"`
+	// the actual rows and time (in milliseconds) are per loop
+	ActualRows      float64 `json:"Actual Rows"`
+	ActualTotalTime float64 `json:"Actual Total Time"`
+	ActualLoops     float64 `json:"Actual Loops"`
+	// the conditions postgres checks against the rows returned by the scan
+	// - for a foreign scan, these are rechecked locally so are not necessarily the qualifiers passed to the plugin
+	Filter string      `json:"Filter"`
+	Plans  []*PlanNode `json:"Plans"`
+}
+
+// ForeignScan :: a summary of a foreign table scan
+type ForeignScan struct {
+	Table string
+	Alias string
+	Rows  int
+	// the total time in milliseconds
+	Time float64
+	// the conditions which postgres rechecks against the rows returned by the plugin
+	Filter string
+}
+
+// ConnectionSummary :: a summary of all foreign table scans for a connection
+type ConnectionSummary struct {
+	Connection string
+	Rows       int
+	// the total time in milliseconds
+	Time  float64
+	Scans []*ForeignScan
+}
+
+// Explain runs EXPLAIN (ANALYZE, VERBOSE, FORMAT JSON) for the given query and parses the resultant plan
+// NOTE: VERBOSE is required so the plan includes the schema (i.e. the connection) of each relation
+func Explain(ctx context.Context, executor Executor, query string) (*QueryPlan, error) {
+	query = strings.TrimSuffix(strings.TrimSpace(query), ";")
+	result, err := executor.ExecuteSync(ctx, fmt.Sprintf("EXPLAIN (ANALYZE, VERBOSE, FORMAT JSON) %s", query), false)
+	if err != nil {
+		return nil, err
+	}
+	if len(result.Rows) != 1 {
+		return nil, fmt.Errorf("explain returned %d rows - expected 1", len(result.Rows))
+	}
+	row := result.Rows[0].(*queryresult.RowResult)
+	if row.Error != nil {
+		return nil, row.Error
+	}
+	if len(row.Data) != 1 {
+		return nil, fmt.Errorf("explain returned %d columns - expected 1", len(row.Data))
+	}
+	return Parse(row.Data[0])
+}
+
+// Parse parses the output of EXPLAIN (FORMAT JSON)
+// the value may either be the raw json, or json which has already been unmarshalled
+func Parse(value interface{}) (*QueryPlan, error) {
+	var planBytes []byte
+	switch v := value.(type) {
+	case []byte:
+		planBytes = v
+	case string:
+		planBytes = []byte(v)
+	default:
+		var err error
+		if planBytes, err = json.Marshal(v); err != nil {
+			return nil, err
+		}
+	}
+
+	var plans []*QueryPlan
+	if err := json.Unmarshal(planBytes, &plans); err != nil {
+		return nil, fmt.Errorf("failed to parse query plan: %v", err)
+	}
+	if len(plans) != 1 || plans[0].Plan == nil {
+		return nil, fmt.Errorf("failed to parse query plan: expected a single plan")
+	}
+	return plans[0], nil
+}
+
+// ConnectionSummaries returns a summary of the foreign scans for each connection in the plan,
+// ordered by descending time
+func (p *QueryPlan) ConnectionSummaries() []*ConnectionSummary {
+	summaryMap := make(map[string]*ConnectionSummary)
+	var summaries []*ConnectionSummary
+
+	p.Plan.walk(func(node *PlanNode) {
+		if node.NodeType != foreignScanNodeType {
+			return
+		}
+		summary, ok := summaryMap[node.Schema]
+		if !ok {
+			summary = &ConnectionSummary{Connection: node.Schema}
+			summaryMap[node.Schema] = summary
+			summaries = append(summaries, summary)
+		}
+		scan := &ForeignScan{
+			Table:  node.RelationName,
+			Alias:  node.Alias,
+			Rows:   int(node.ActualRows * node.ActualLoops),
+			Time:   node.ActualTotalTime * node.ActualLoops,
+			Filter: node.Filter,
+		}
+		summary.Scans = append(summary.Scans, scan)
+		summary.Rows += scan.Rows
+		summary.Time += scan.Time
+	})
+
+	sort.SliceStable(summaries, func(i, j int) bool {
+		return summaries[i].Time > summaries[j].Time
+	})
+	for _, summary := range summaries {
+		scans := summary.Scans
+		sort.SliceStable(scans, func(i, j int) bool {
+			return scans[i].Time > scans[j].Time
+		})
+	}
+	return summaries
+}
+
+// call f for this node and all descendants
+func (n *PlanNode) walk(f func(*PlanNode)) {
+	f(n)
+	for _, child := range n.Plans {
+		child.walk(f)
+	}
+}
@@ -0,0 +1,96 @@
+package queryplan
+
+import (
+	"testing"
+)
+
+const testPlan = `[
+  {
+    "Plan": {
+      "Node Type": "Hash Join",
+      "Actual Rows": 2,
+      "Actual Total Time": 30.5,
+      "Actual Loops": 1,
+      "Plans": [
+        {
+          "Node Type": "Foreign Scan",
+          "Relation Name": "aws_s3_bucket",
+          "Schema": "aws",
+          "Alias": "b",
+          "Actual Rows": 10,
+          "Actual Total Time": 20.0,
+          "Actual Loops": 1,
+          "Filter": "(b.region = 'us-east-1'::text)"
+        },
+        {
+          "Node Type": "Hash",
+          "Actual Rows": 3,
+          "Actual Total Time": 4.0,
+          "Actual Loops": 1,
+          "Plans": [
+            {
+              "Node Type": "Foreign Scan",
+              "Relation Name": "aws_iam_user",
+              "Schema": "aws",
+              "Alias": "aws_iam_user",
+              "Actual Rows": 3,
+              "Actual Total Time": 2.0,
+              "Actual Loops": 2
+            },
+            {
+              "Node Type": "Foreign Scan",
+              "Relation Name": "azure_storage_account",
+              "Schema": "azure",
+              "Alias": "azure_storage_account",
+              "Actual Rows": 1,
+              "Actual Total Time": 1.5,
+              "Actual Loops": 1
+            }
+          ]
+        }
+      ]
+    },
+    "Planning Time": 0.5,
+    "Execution Time": 31.0
+  }
+]`
+
+func TestConnectionSummaries(t *testing.T) {
+	plan, err := Parse([]byte(testPlan))
+	if err != nil {
+		t.Fatalf("Test: 'parse plan' FAILED : unexpected error %v", err)
+	}
+	if plan.ExecutionTime != 31.0 {
+		t.Errorf("Test: 'parse plan' FAILED : expected execution time 31, got %v", plan.ExecutionTime)
+	}
+
+	summaries := plan.ConnectionSummaries()
+	if len(summaries) != 2 {
+		t.Fatalf("Test: 'connection summaries' FAILED : expected 2 connections, got %d", len(summaries))
+	}
+	aws := summaries[0]
+	if aws.Connection != "aws" || aws.Rows != 16 || aws.Time != 24.0 || len(aws.Scans) != 2 {
+		t.Errorf("Test: 'aws summary' FAILED : got %+v", aws)
+	}
+	if scan := aws.Scans[0]; scan.Table != "aws_s3_bucket" || scan.Filter != "(b.region = 'us-east-1'::text)" {
+		t.Errorf("Test: 'aws scans' FAILED : expected aws_s3_bucket scan with filter first, got %+v", scan)
+	}
+	if azure := summaries[1]; azure.Connection != "azure" || azure.Rows != 1 {
+		t.Errorf("Test: 'azure summary' FAILED : got %+v", azure)
+	}
+}
+
+func TestParseUnmarshalledPlan(t *testing.T) {
+	// the db client returns json columns already unmarshalled
+	value := []interface{}{map[string]interface{}{
+		"Plan":           map[string]interface{}{"Node Type": "Result"},
+		"Execution Time": 1.0,
+	}}
+	plan, err := Parse(value)
+	if err != nil {
+		t.Fatalf("Test: 'unmarshalled plan' FAILED : unexpected error %v", err)
+	}
+	if len(plan.ConnectionSummaries()) != 0 {
+		t.Errorf("Test: 'unmarshalled plan' FAILED : expected no foreign scans")
+	}
+}
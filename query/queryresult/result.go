@@ -20,6 +20,13 @@ func (r Result) Close() {
 	close(*r.RowChan)
 }
 
+// Drain reads and discards any rows which have not been read
+// this must be called by a reader which stops reading before the row channel is closed, so the writer is not blocked
+func (r Result) Drain() {
+	for range *r.RowChan {
+	}
+}
+
 func (r Result) StreamRow(rowResult []interface{}) {
 	*r.RowChan <- &RowResult{Data: rowResult}
 }
@@ -42,3 +49,17 @@ type SyncQueryResult struct {
 	ColTypes []*sql.ColumnType
 	Duration time.Duration
 }
+
+// Stream returns a Result which streams the rows of the SyncQueryResult
+// this allows a result which has already been read to be displayed or exported again
+func (r *SyncQueryResult) Stream() *Result {
+	res := NewQueryResult(r.ColTypes)
+	go func() {
+		for _, row := range r.Rows {
+			*res.RowChan <- row.(*RowResult)
+		}
+		res.Close()
+		res.Duration <- r.Duration
+	}()
+	return res
+}
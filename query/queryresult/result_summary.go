@@ -0,0 +1,122 @@
+package queryresult
+
+import "time"
+
+// ResultSummary :: the row count, duration and any error of a result which has been fully read
+type ResultSummary struct {
+	RowCount int
+	Duration time.Duration
+	Error    error
+}
+
+// WithSummary returns a Result which streams the same rows as r,
+// and a channel which will receive a summary of the result once all rows have been read from the returned Result
+func (r *Result) WithSummary() (*Result, chan *ResultSummary) {
+	summaryChan := make(chan *ResultSummary, 1)
+	summary := &ResultSummary{}
+
+	res := r.proxy(func(row *RowResult) {
+		if row.Error != nil {
+			summary.Error = row.Error
+		} else {
+			summary.RowCount++
+		}
+	}, func(duration time.Duration) {
+		summary.Duration = duration
+		summaryChan <- summary
+	})
+
+	return res, summaryChan
+}
+
+// WithCapture returns a Result which streams the same rows as r,
+// and a channel which will receive a SyncQueryResult containing all the rows
+// once they have been read from the returned Result
+// if the result has more than maxRows rows, the rows are not kept and the channel receives nil
+func (r *Result) WithCapture(maxRows int) (*Result, chan *SyncQueryResult) {
+	captureChan := make(chan *SyncQueryResult, 1)
+	captured := &SyncQueryResult{ColTypes: r.ColTypes}
+
+	res := r.proxy(func(row *RowResult) {
+		if captured == nil {
+			return
+		}
+		if len(captured.Rows) == maxRows {
+			// too many rows - stop capturing and release the rows captured so far
+			captured = nil
+			return
+		}
+		captured.Rows = append(captured.Rows, row)
+	}, func(duration time.Duration) {
+		if captured != nil {
+			captured.Duration = duration
+		}
+		captureChan <- captured
+	})
+
+	return res, captureChan
+}
+
+// return a Result which streams the same rows as r, calling onRow for each row which is forwarded,
+// and onComplete once all rows have been forwarded and the duration is known
+func (r *Result) proxy(onRow func(*RowResult), onComplete func(time.Duration)) *Result {
+	res := NewQueryResult(r.ColTypes)
+
+	go func() {
+		var err error
+		for row := range *r.RowChan {
+			// once an error has been streamed, the reader will stop reading
+			// - just drain any remaining rows so the source is not blocked
+			if err != nil {
+				continue
+			}
+			err = row.Error
+			onRow(row)
+			*res.RowChan <- row
+		}
+		res.Close()
+
+		// forward the duration on to the new result
+		duration := <-r.Duration
+		res.Duration <- duration
+
+		onComplete(duration)
+	}()
+
+	return res
+}
+
+// Tee returns count Results which each stream the same rows as r
+// every returned Result must be read in full, as each row is only forwarded once all results have received it
+func (r *Result) Tee(count int) []*Result {
+	results := make([]*Result, count)
+	for i := range results {
+		results[i] = NewQueryResult(r.ColTypes)
+	}
+
+	go func() {
+		var err error
+		for row := range *r.RowChan {
+			// once an error has been streamed, the readers will stop reading
+			// - just drain any remaining rows so the source is not blocked
+			if err != nil {
+				continue
+			}
+			err = row.Error
+			for _, res := range results {
+				*res.RowChan <- row
+			}
+		}
+		for _, res := range results {
+			res.Close()
+		}
+
+		// forward the duration on to the new results
+		duration := <-r.Duration
+		for _, res := range results {
+			res.Duration <- duration
+		}
+	}()
+
+	return results
+}
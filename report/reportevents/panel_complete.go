@@ -4,6 +4,8 @@ import "github.com/turbot/steampipe/report/reportinterfaces"
 
 type PanelComplete struct {
 	Panel reportinterfaces.ReportNodeRun
+	// the name of the root node of the execution which the panel is part of
+	Report string
 }
 
 // IsReportEvent implements ReportEvent interface
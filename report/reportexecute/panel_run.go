@@ -18,6 +18,10 @@ type PanelRun struct {
 	Source string          `json:"source,omitempty"`
 	SQL    string          `json:"sql,omitempty"`
 	Data   [][]interface{} `json:"data,omitempty"`
+	// was the data retrieved from the result cache
+	CacheHit bool `json:"cache_hit,omitempty"`
+	// refresh interval in seconds
+	Refresh int `json:"refresh,omitempty"`
 
 	Error error `json:"error,omitempty"`
 
@@ -51,6 +55,10 @@ func NewPanelRun(panel *modconfig.Panel, executionTree *ReportExecutionTree) *Pa
 		r.Height = *panel.Height
 	}
 
+	if panel.Refresh != nil {
+		r.Refresh = *panel.Refresh
+	}
+
 	// if we have sql, set status to ready
 	if panel.SQL != nil {
 		r.runStatus = reportinterfaces.ReportRunReady
@@ -104,7 +112,7 @@ func (r *PanelRun) SetError(err error) {
 func (r *PanelRun) SetComplete() {
 	r.runStatus = reportinterfaces.ReportRunComplete
 	// raise panel complete event
-	r.executionTree.workspace.PublishReportEvent(&reportevents.PanelComplete{Panel: r})
+	r.executionTree.workspace.PublishReportEvent(&reportevents.PanelComplete{Panel: r, Report: r.executionTree.Root.GetName()})
 }
 
 // RunComplete implements ReportNodeRun
@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"sync"
+	"time"
 
 	"github.com/stevenle/topsort"
 	"github.com/turbot/steampipe/db/db_common"
@@ -21,10 +23,14 @@ type ReportExecutionTree struct {
 	panels          map[string]*PanelRun
 	reports         map[string]*ReportRun
 	workspace       *workspace.Workspace
+	// shared cache of panel query results - may be nil, in which case results are not cached
+	cache *ResultCache
+	// lock used to ensure refreshes do not overlap with each other or with the initial execution
+	executeLock sync.Mutex
 }
 
 // NewReportExecutionTree creates a result group from a ModTreeItem
-func NewReportExecutionTree(reportName string, client db_common.Client, workspace *workspace.Workspace) (*ReportExecutionTree, error) {
+func NewReportExecutionTree(reportName string, client db_common.Client, workspace *workspace.Workspace, cache *ResultCache) (*ReportExecutionTree, error) {
 	// now populate the ReportExecutionTree
 	reportExecutionTree := &ReportExecutionTree{
 		client:          client,
@@ -32,6 +38,7 @@ func NewReportExecutionTree(reportName string, client db_common.Client, workspac
 		panels:          make(map[string]*PanelRun),
 		reports:         make(map[string]*ReportRun),
 		workspace:       workspace,
+		cache:           cache,
 	}
 
 	// create the root run node (either a report run or a panel run)
@@ -74,6 +81,9 @@ func (e *ReportExecutionTree) Execute(ctx context.Context) error {
 	log.Println("[TRACE]", "begin ReportExecutionTree.Execute")
 	defer log.Println("[TRACE]", "end ReportExecutionTree.Execute")
 
+	e.executeLock.Lock()
+	defer e.executeLock.Unlock()
+
 	if e.runStatus() == reportinterfaces.ReportRunComplete {
 		// there must be no sql panels to execute
 		log.Println("[TRACE]", "execution tree already complete")
@@ -95,6 +105,45 @@ func (e *ReportExecutionTree) Execute(ctx context.Context) error {
 	return nil
 }
 
+// RefreshIntervals returns a map of the refresh interval for every node in the tree which specifies one, keyed by node name
+func (e *ReportExecutionTree) RefreshIntervals() map[string]time.Duration {
+	res := make(map[string]time.Duration)
+	for name, report := range e.reports {
+		if report.Refresh > 0 {
+			res[name] = time.Duration(report.Refresh) * time.Second
+		}
+	}
+	for name, panel := range e.panels {
+		if panel.Refresh > 0 {
+			res[name] = time.Duration(panel.Refresh) * time.Second
+		}
+	}
+	return res
+}
+
+// Refresh re-executes the named node, and all nodes it depends on
+// each re-executed panel will raise a PanelComplete event
+func (e *ReportExecutionTree) Refresh(ctx context.Context, name string) error {
+	log.Printf("[TRACE] ReportExecutionTree.Refresh %s", name)
+
+	e.executeLock.Lock()
+	defer e.executeLock.Unlock()
+
+	// get the dependency order for this node
+	// (if the node has no dependencies it will not be in the graph, and TopSort will return just the node itself)
+	executionOrder, err := e.dependencyGraph.TopSort(name)
+	if err != nil {
+		return err
+	}
+	for _, nodeName := range executionOrder {
+		// bypass the result cache - the point of a refresh is to fetch fresh data
+		if err := e.executeNode(ctx, nodeName, false); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // AddDependency adds a dependency relationship to our dependency graph
 // the resource has a dependency on an incomplete child resource
 func (e *ReportExecutionTree) AddDependency(resource, dependency string) {
@@ -112,7 +161,12 @@ func (e *ReportExecutionTree) runStatus() reportinterfaces.ReportRunStatus {
 	return e.Root.GetRunStatus()
 }
 
+// ExecuteNode executes the named node, using cached panel query results if available
 func (e *ReportExecutionTree) ExecuteNode(ctx context.Context, name string) error {
+	return e.executeNode(ctx, name, true)
+}
+
+func (e *ReportExecutionTree) executeNode(ctx context.Context, name string, useCache bool) error {
 	parsedName, err := modconfig.ParseResourceName(name)
 	if err != nil {
 		return err
@@ -142,7 +196,7 @@ func (e *ReportExecutionTree) ExecuteNode(ctx context.Context, name string) erro
 		}
 		// if panel has sql execute it
 		if panel.SQL != "" {
-			data, err := e.executePanelSQL(ctx, panel.SQL)
+			data, cacheHit, err := e.executePanelSQL(ctx, panel.SQL, useCache)
 			if err != nil {
 				// set the error status on the panel - this will raise panel error event
 				panel.SetError(err)
@@ -151,6 +205,7 @@ func (e *ReportExecutionTree) ExecuteNode(ctx context.Context, name string) erro
 			}
 
 			panel.Data = data
+			panel.CacheHit = cacheHit
 		}
 		// panel should now be complete, i.e. all it's children should be complete
 		if !panel.ChildrenComplete() {
@@ -165,10 +220,18 @@ func (e *ReportExecutionTree) ExecuteNode(ctx context.Context, name string) erro
 	return fmt.Errorf("invalid block type '%s' passed to ReportExecutionTree.ExecuteNode", name)
 }
 
-func (e *ReportExecutionTree) executePanelSQL(ctx context.Context, query string) ([][]interface{}, error) {
+// executePanelSQL executes the query and returns the result data, and whether the data came from the result cache
+func (e *ReportExecutionTree) executePanelSQL(ctx context.Context, query string, useCache bool) ([][]interface{}, bool, error) {
+	if useCache && e.cache != nil {
+		if data, ok := e.cache.Get(query); ok {
+			log.Printf("[TRACE] panel query result cache hit: %s", query)
+			return data, true, nil
+		}
+	}
+
 	queryResult, err := e.client.ExecuteSync(ctx, query, true)
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
 	var res = make([][]interface{}, len(queryResult.Rows)+1)
 	var columns = make([]interface{}, len(queryResult.ColTypes))
@@ -184,5 +247,8 @@ func (e *ReportExecutionTree) executePanelSQL(ctx context.Context, query string)
 		res[i+1] = rowData
 	}
 
-	return res, nil
+	if e.cache != nil {
+		e.cache.Set(res, query)
+	}
+	return res, false, nil
 }
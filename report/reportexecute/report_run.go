@@ -11,6 +11,8 @@ import (
 type ReportRun struct {
 	Name  string `json:"name"`
 	Title string `json:"title,omitempty"`
+	// refresh interval in seconds
+	Refresh int `json:"refresh,omitempty"`
 
 	// children
 	PanelRuns  []*PanelRun  `json:"panels,omitempty"`
@@ -32,6 +34,9 @@ func NewReportRun(report *modconfig.Report, executionTree *ReportExecutionTree)
 		// if any children have SQL we will set this to reportinterfaces.ReportRunReady instead
 		runStatus: reportinterfaces.ReportRunComplete,
 	}
+	if report.Refresh != nil {
+		r.Refresh = *report.Refresh
+	}
 
 	// create report runs for all children
 	for _, childReport := range report.Reports {
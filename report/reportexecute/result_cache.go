@@ -0,0 +1,106 @@
+package reportexecute
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"github.com/turbot/steampipe/report/reportevents"
+)
+
+// ResultCache is an in-process cache of panel query results, keyed by SQL
+// it is shared by all executions, so clients viewing the same report do not re-run the same queries
+// expired entries are evicted whenever a result is added, and the number of entries is limited to maxEntries
+type ResultCache struct {
+	ttl        time.Duration
+	maxEntries int
+	entries    map[string]*resultCacheEntry
+	lock       sync.Mutex
+}
+
+type resultCacheEntry struct {
+	data    [][]interface{}
+	expires time.Time
+}
+
+func NewResultCache(ttl time.Duration, maxEntries int) *ResultCache {
+	return &ResultCache{
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		entries:    make(map[string]*resultCacheEntry),
+	}
+}
+
+// Get returns the cached result for the given sql, if there is an unexpired entry
+func (c *ResultCache) Get(sql string) ([][]interface{}, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	entry, ok := c.entries[sql]
+	if !ok {
+		return nil, false
+	}
+	if time.Now().After(entry.expires) {
+		delete(c.entries, sql)
+		return nil, false
+	}
+	return entry.data, true
+}
+
+// Set adds the result for the given sql to the cache
+// if the cache is full once expired entries are evicted, the entry which expires soonest is evicted
+func (c *ResultCache) Set(data [][]interface{}, sql string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.evictExpired()
+	if _, ok := c.entries[sql]; !ok && len(c.entries) >= c.maxEntries {
+		c.evictOldest()
+	}
+	c.entries[sql] = &resultCacheEntry{
+		data:    data,
+		expires: time.Now().Add(c.ttl),
+	}
+}
+
+// Clear removes all entries from the cache
+func (c *ResultCache) Clear() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.entries = make(map[string]*resultCacheEntry)
+}
+
+// HandleWorkspaceUpdate implements ReportEventHandler
+// clear the cache whenever the workspace reports have changed
+// NOTE: this must be registered before any handler which re-executes the changed reports
+func (c *ResultCache) HandleWorkspaceUpdate(event reportevents.ReportEvent) {
+	if e, ok := event.(*reportevents.ReportChanged); ok && e.HasChanges() {
+		log.Println("[TRACE] ResultCache clearing cache - workspace reports have changed")
+		c.Clear()
+	}
+}
+
+// remove all expired entries - the lock must be held
+func (c *ResultCache) evictExpired() {
+	now := time.Now()
+	for key, entry := range c.entries {
+		if now.After(entry.expires) {
+			delete(c.entries, key)
+		}
+	}
+}
+
+// remove the entry which expires soonest - as all entries have the same ttl, this is the oldest
+// the lock must be held
+func (c *ResultCache) evictOldest() {
+	var oldestKey string
+	var oldest time.Time
+	for key, entry := range c.entries {
+		if oldestKey == "" || entry.expires.Before(oldest) {
+			oldestKey = key
+			oldest = entry.expires
+		}
+	}
+	delete(c.entries, oldestKey)
+}
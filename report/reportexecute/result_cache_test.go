@@ -0,0 +1,76 @@
+package reportexecute
+
+import (
+	"testing"
+	"time"
+
+	"github.com/turbot/steampipe/report/reportevents"
+	"github.com/turbot/steampipe/steampipeconfig/modconfig"
+)
+
+var testData = [][]interface{}{{"name"}, {"foo"}}
+
+func TestResultCacheGet(t *testing.T) {
+	cache := NewResultCache(time.Minute, 10)
+	cache.Set(testData, "select 1")
+
+	if _, ok := cache.Get("select 1"); !ok {
+		t.Errorf("Test: 'matching sql' FAILED : expected cache hit")
+	}
+	if _, ok := cache.Get("select 2"); ok {
+		t.Errorf("Test: 'different sql' FAILED : expected cache miss")
+	}
+}
+
+func TestResultCacheExpiry(t *testing.T) {
+	cache := NewResultCache(time.Millisecond, 10)
+	cache.Set(testData, "select 1")
+	time.Sleep(5 * time.Millisecond)
+
+	if _, ok := cache.Get("select 1"); ok {
+		t.Errorf("Test: 'expired entry' FAILED : expected cache miss")
+	}
+
+	// expired entries which are never read are evicted when a result is added
+	cache.Set(testData, "select 2")
+	time.Sleep(5 * time.Millisecond)
+	cache.Set(testData, "select 3")
+	if len(cache.entries) != 1 {
+		t.Errorf("Test: 'evict expired on set' FAILED : expected 1 entry, got %d", len(cache.entries))
+	}
+}
+
+func TestResultCacheMaxEntries(t *testing.T) {
+	cache := NewResultCache(time.Minute, 2)
+	cache.Set(testData, "select 1")
+	time.Sleep(time.Millisecond)
+	cache.Set(testData, "select 2")
+	time.Sleep(time.Millisecond)
+	cache.Set(testData, "select 3")
+
+	if len(cache.entries) != 2 {
+		t.Errorf("Test: 'max entries' FAILED : expected 2 entries, got %d", len(cache.entries))
+	}
+	if _, ok := cache.Get("select 1"); ok {
+		t.Errorf("Test: 'max entries' FAILED : expected the oldest entry to be evicted")
+	}
+	if _, ok := cache.Get("select 3"); !ok {
+		t.Errorf("Test: 'max entries' FAILED : expected cache hit for the newest entry")
+	}
+}
+
+func TestResultCacheReportChanged(t *testing.T) {
+	cache := NewResultCache(time.Minute, 10)
+	cache.Set(testData, "select 1")
+
+	// an event with no changes should not clear the cache
+	cache.HandleWorkspaceUpdate(&reportevents.ReportChanged{})
+	if _, ok := cache.Get("select 1"); !ok {
+		t.Errorf("Test: 'empty report changed event' FAILED : expected cache hit")
+	}
+
+	cache.HandleWorkspaceUpdate(&reportevents.ReportChanged{NewPanels: []*modconfig.Panel{{FullName: "panel.p1"}}})
+	if _, ok := cache.Get("select 1"); ok {
+		t.Errorf("Test: 'report changed event' FAILED : expected cache miss")
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"log"
 	"sync"
 
 	"github.com/spf13/viper"
@@ -17,6 +18,7 @@ import (
 	"github.com/turbot/steampipe/constants"
 	"github.com/turbot/steampipe/executionlayer"
 	"github.com/turbot/steampipe/report/reportevents"
+	"github.com/turbot/steampipe/report/reportexecute"
 	"github.com/turbot/steampipe/report/reportinterfaces"
 	"github.com/turbot/steampipe/steampipeconfig/modconfig"
 	"github.com/turbot/steampipe/workspace"
@@ -29,6 +31,10 @@ type Server struct {
 	reportClients map[*melody.Session]*ReportClientInfo
 	webSocket     *melody.Melody
 	workspace     *workspace.Workspace
+	// panel query results shared by all client sessions
+	resultCache *reportexecute.ResultCache
+	// cancel functions for the refresh timers of each executing report, keyed by report name
+	refreshCancelFuncs map[string]context.CancelFunc
 }
 
 type ErrorPayload struct {
@@ -41,6 +47,11 @@ type ExecutionPayload struct {
 	ReportNode reportinterfaces.ReportNodeRun `json:"report_node"`
 }
 
+type PanelCompletePayload struct {
+	Action string                         `json:"action"`
+	Panel  reportinterfaces.ReportNodeRun `json:"panel"`
+}
+
 type ReportClientInfo struct {
 	Report *string
 }
@@ -69,14 +80,18 @@ func NewServer(ctx context.Context) (*Server, error) {
 	var mutex = &sync.Mutex{}
 
 	server := &Server{
-		context:       ctx,
-		dbClient:      dbClient,
-		mutex:         mutex,
-		reportClients: reportClients,
-		webSocket:     webSocket,
-		workspace:     loadedWorkspace,
+		context:            ctx,
+		dbClient:           dbClient,
+		mutex:              mutex,
+		reportClients:      reportClients,
+		webSocket:          webSocket,
+		workspace:          loadedWorkspace,
+		resultCache:        reportexecute.NewResultCache(constants.ReportResultCacheTTL, constants.ReportResultCacheMaxEntries),
+		refreshCancelFuncs: make(map[string]context.CancelFunc),
 	}
 
+	// register the cache handler first, so the cache is cleared before any changed reports are re-executed
+	loadedWorkspace.RegisterReportEventHandler(server.resultCache.HandleWorkspaceUpdate)
 	loadedWorkspace.RegisterReportEventHandler(server.HandleWorkspaceUpdate)
 	err = loadedWorkspace.SetupWatcher(dbClient, nil)
 
@@ -123,13 +138,29 @@ func buildExecutionCompletePayload(event *reportevents.ExecutionComplete) []byte
 	return jsonString
 }
 
+func buildPanelCompletePayload(event *reportevents.PanelComplete) []byte {
+	payload := PanelCompletePayload{
+		Action: "panel_complete",
+		Panel:  event.Panel,
+	}
+	jsonString, _ := json.Marshal(payload)
+	return jsonString
+}
+
 // Starts the API server
 func (s *Server) Start() {
-	go Init(s.context, s.webSocket, s.workspace, s.dbClient, s.reportClients, s.mutex)
+	go Init(s.webSocket, s.workspace, s.executeReport, s.reportClients, s.mutex)
 	StartAPI(s.context, s.webSocket)
 }
 
 func (s *Server) Shutdown() {
+	// stop all refresh timers
+	s.mutex.Lock()
+	for _, cancel := range s.refreshCancelFuncs {
+		cancel()
+	}
+	s.mutex.Unlock()
+
 	// Close the DB client
 	if s.dbClient != nil {
 		s.dbClient.Close()
@@ -188,6 +219,15 @@ func (s *Server) HandleWorkspaceUpdate(event reportevents.ReportEvent) {
 
 	case *reportevents.PanelComplete:
 		fmt.Println("Got panel complete event", *e)
+		payload := buildPanelCompletePayload(e)
+		s.mutex.Lock()
+		for session, repoInfo := range s.reportClients {
+			// If this session is interested in this report, send the updated panel to it
+			if (repoInfo.Report != nil) && *repoInfo.Report == e.Report {
+				session.Write(payload)
+			}
+		}
+		s.mutex.Unlock()
 		break
 
 	case *reportevents.ReportChanged:
@@ -258,7 +298,7 @@ func (s *Server) HandleWorkspaceUpdate(event reportevents.ReportEvent) {
 
 		for _, changedReportName := range changedReportNames {
 			if helpers.StringSliceContains(reportsBeingWatched, changedReportName) {
-				executionlayer.ExecuteReportNode(s.context, changedReportName, s.workspace, s.dbClient)
+				s.executeReport(changedReportName)
 			}
 		}
 
@@ -274,7 +314,7 @@ func (s *Server) HandleWorkspaceUpdate(event reportevents.ReportEvent) {
 
 		for _, newReportName := range newReportNames {
 			if helpers.StringSliceContains(reportsBeingWatched, newReportName) {
-				executionlayer.ExecuteReportNode(s.context, newReportName, s.workspace, s.dbClient)
+				s.executeReport(newReportName)
 			}
 		}
 
@@ -303,3 +343,48 @@ func (s *Server) HandleWorkspaceUpdate(event reportevents.ReportEvent) {
 		break
 	}
 }
+
+// executeReport executes the given report and starts refresh timers for any nodes with a refresh interval
+// any refresh timers from a previous execution of the report are stopped
+func (s *Server) executeReport(reportName string) {
+	s.mutex.Lock()
+	if cancel, ok := s.refreshCancelFuncs[reportName]; ok {
+		cancel()
+		delete(s.refreshCancelFuncs, reportName)
+	}
+	s.mutex.Unlock()
+
+	executionTree, err := executionlayer.ExecuteReportNode(s.context, reportName, s.workspace, s.dbClient, s.resultCache)
+	if err != nil {
+		log.Printf("[WARN] failed to execute report %s: %v", reportName, err)
+		return
+	}
+	if len(executionTree.RefreshIntervals()) == 0 {
+		return
+	}
+
+	refreshCtx, cancel := context.WithCancel(s.context)
+	s.mutex.Lock()
+	// another execution of the report may have started refresh timers while we were executing - stop them
+	if existing, ok := s.refreshCancelFuncs[reportName]; ok {
+		existing()
+	}
+	s.refreshCancelFuncs[reportName] = cancel
+	s.mutex.Unlock()
+
+	executionlayer.RefreshReportNodes(refreshCtx, executionTree, func() bool {
+		return s.isReportWatched(reportName)
+	})
+}
+
+// isReportWatched returns whether any client session is currently viewing the given report
+func (s *Server) isReportWatched(reportName string) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for _, reportClientInfo := range s.reportClients {
+		if reportClientInfo.Report != nil && *reportClientInfo.Report == reportName {
+			return true
+		}
+	}
+	return false
+}
@@ -1,15 +1,12 @@
 package reportserver
 
 import (
-	"context"
 	"encoding/json"
 	"fmt"
 	"sync"
 
 	"gopkg.in/olahol/melody.v1"
 
-	"github.com/turbot/steampipe/db/db_common"
-	"github.com/turbot/steampipe/executionlayer"
 	"github.com/turbot/steampipe/workspace"
 )
 
@@ -31,7 +28,7 @@ type AvailableReportsPayload struct {
 	Reports map[string]string `json:"reports"`
 }
 
-func Init(ctx context.Context, webSocket *melody.Melody, workspace *workspace.Workspace, dbClient db_common.Client, socketSessions map[*melody.Session]*ReportClientInfo, mutex *sync.Mutex) {
+func Init(webSocket *melody.Melody, workspace *workspace.Workspace, executeReport func(reportName string), socketSessions map[*melody.Session]*ReportClientInfo, mutex *sync.Mutex) {
 	// Return list of reports on connect
 	webSocket.HandleConnect(func(session *melody.Session) {
 		fmt.Println("Client connected")
@@ -64,7 +61,7 @@ func Init(ctx context.Context, webSocket *melody.Melody, workspace *workspace.Wo
 				reportClientInfo := socketSessions[session]
 				reportClientInfo.Report = &request.Payload.Report.FullName
 				mutex.Unlock()
-				executionlayer.ExecuteReportNode(ctx, request.Payload.Report.FullName, workspace, dbClient)
+				executeReport(request.Payload.Report.FullName)
 			}
 		}
 	})
@@ -3,9 +3,11 @@ package steampipeconfig
 import (
 	"os"
 	"os/exec"
+	"sort"
 
 	"github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/go-plugin"
+	"github.com/turbot/go-kit/helpers"
 	"github.com/turbot/steampipe-plugin-sdk/grpc"
 	"github.com/turbot/steampipe-plugin-sdk/grpc/proto"
 	pluginshared "github.com/turbot/steampipe-plugin-sdk/grpc/shared"
@@ -105,6 +107,42 @@ func CreateConnectionPlugin(connection *modconfig.Connection, disableLogger bool
 	return c, nil
 }
 
+// KeyColumns :: return a map of table name to the names of the key columns of the table,
+// i.e. the columns which the plugin may use to qualify its get and list calls
+func (p *ConnectionPlugin) KeyColumns() map[string][]string {
+	res := make(map[string][]string)
+	if p.Schema == nil {
+		return res
+	}
+	for tableName, tableSchema := range p.Schema.Schema {
+		var columns []string
+		add := func(names ...string) {
+			for _, name := range names {
+				if name != "" && !helpers.StringSliceContains(columns, name) {
+					columns = append(columns, name)
+				}
+			}
+		}
+		for _, k := range tableSchema.GetCallKeyColumnList {
+			add(k.Name)
+		}
+		for _, k := range tableSchema.ListCallKeyColumnList {
+			add(k.Name)
+		}
+		// plugins built with older versions of the sdk only populate the deprecated key column sets
+		for _, set := range []*proto.KeyColumnsSet{tableSchema.GetCallKeyColumns, tableSchema.ListCallKeyColumns, tableSchema.ListCallOptionalKeyColumns} {
+			if set != nil {
+				add(set.Single)
+				add(set.All...)
+				add(set.Any...)
+			}
+		}
+		sort.Strings(columns)
+		res[tableName] = columns
+	}
+	return res
+}
+
 // SetConnectionConfig sends the connection config
 func SetConnectionConfig(connectionName string, connectionConfig string, pluginClient *grpc.PluginClient) error {
 	req := &proto.SetConnectionConfigRequest{
@@ -0,0 +1,32 @@
+package steampipeconfig
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/turbot/steampipe-plugin-sdk/grpc/proto"
+)
+
+func TestConnectionPluginKeyColumns(t *testing.T) {
+	p := &ConnectionPlugin{Schema: &proto.Schema{Schema: map[string]*proto.TableSchema{
+		"aws_s3_bucket": {
+			GetCallKeyColumnList:  []*proto.KeyColumn{{Name: "name"}},
+			ListCallKeyColumnList: []*proto.KeyColumn{{Name: "region"}, {Name: "name"}},
+		},
+		// a table of a plugin built with an older sdk
+		"aws_ec2_instance": {
+			GetCallKeyColumns:          &proto.KeyColumnsSet{Single: "instance_id"},
+			ListCallOptionalKeyColumns: &proto.KeyColumnsSet{Any: []string{"instance_state", "region"}},
+		},
+		"aws_region": {},
+	}}}
+
+	expected := map[string][]string{
+		"aws_s3_bucket":    {"name", "region"},
+		"aws_ec2_instance": {"instance_id", "instance_state", "region"},
+		"aws_region":       nil,
+	}
+	if keyColumns := p.KeyColumns(); !reflect.DeepEqual(keyColumns, expected) {
+		t.Errorf("Test: 'key columns' FAILED : expected %v, got %v", expected, keyColumns)
+	}
+}
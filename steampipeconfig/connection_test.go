@@ -37,6 +37,7 @@ var testCasesGetConnectionsToUpdate = map[string]getConnectionsToUpdateTest{
 				Plugin:     "hub.steampipe.io/plugins/turbot/connection-test-1@latest",
 				CheckSum:   connectionTest1Checksum,
 				Connection: &modconfig.Connection{Name: "a"},
+				KeyColumns: map[string][]string{},
 			},
 		},
 		expected: &ConnectionUpdates{Update: ConnectionDataMap{}, Delete: ConnectionDataMap{}, RequiredConnections: ConnectionDataMap{
@@ -47,6 +48,34 @@ var testCasesGetConnectionsToUpdate = map[string]getConnectionsToUpdateTest{
 			},
 		}},
 	},
+	"no key columns": {
+		required: []string{
+			`connection "a" {
+  plugin = "connection-test-1"
+}
+`},
+		// state written by an older version has no key columns, so the connection is updated to read them
+		current: ConnectionDataMap{
+			"a": {
+				Plugin:     "hub.steampipe.io/plugins/turbot/connection-test-1@latest",
+				CheckSum:   connectionTest1Checksum,
+				Connection: &modconfig.Connection{Name: "a"},
+			},
+		},
+		expected: &ConnectionUpdates{Update: ConnectionDataMap{
+			"a": {
+				Plugin:     "hub.steampipe.io/plugins/turbot/connection-test-1@latest",
+				CheckSum:   connectionTest1Checksum,
+				Connection: &modconfig.Connection{Name: "a"},
+			},
+		}, Delete: ConnectionDataMap{}, RequiredConnections: ConnectionDataMap{
+			"a": {
+				Plugin:     "hub.steampipe.io/plugins/turbot/connection-test-1@latest",
+				CheckSum:   connectionTest1Checksum,
+				Connection: &modconfig.Connection{Name: "a"},
+			},
+		}},
+	},
 	"no changes multiple in same file same plugin": {
 		required: []string{
 			`connection "a" {
@@ -62,11 +91,13 @@ connection "b" {
 				Plugin:     "hub.steampipe.io/plugins/turbot/connection-test-1@latest",
 				CheckSum:   connectionTest1Checksum,
 				Connection: &modconfig.Connection{Name: "a"},
+				KeyColumns: map[string][]string{},
 			},
 			"b": {
 				Plugin:     "hub.steampipe.io/plugins/turbot/connection-test-1@latest",
 				CheckSum:   connectionTest1Checksum,
 				Connection: &modconfig.Connection{Name: "b"},
+				KeyColumns: map[string][]string{},
 			},
 		},
 		expected: &ConnectionUpdates{Update: ConnectionDataMap{}, Delete: ConnectionDataMap{}, RequiredConnections: ConnectionDataMap{
@@ -97,11 +128,13 @@ connection "b" {
 				Plugin:     "hub.steampipe.io/plugins/turbot/connection-test-1@latest",
 				CheckSum:   connectionTest1Checksum,
 				Connection: &modconfig.Connection{Name: "a"},
+				KeyColumns: map[string][]string{},
 			},
 			"b": {
 				Plugin:     "hub.steampipe.io/plugins/turbot/connection-test-2@latest",
 				CheckSum:   connectionTest2Checksum,
 				Connection: &modconfig.Connection{Name: "b"},
+				KeyColumns: map[string][]string{},
 			},
 		},
 		expected: &ConnectionUpdates{Update: ConnectionDataMap{}, Delete: ConnectionDataMap{}, RequiredConnections: ConnectionDataMap{
@@ -131,11 +164,13 @@ connection "b" {
 				Plugin:     "hub.steampipe.io/plugins/turbot/connection-test-1@latest",
 				CheckSum:   connectionTest1Checksum,
 				Connection: &modconfig.Connection{Name: "a"},
+				KeyColumns: map[string][]string{},
 			},
 			"b": {
 				Plugin:     "hub.steampipe.io/plugins/turbot/connection-test-1@latest",
 				CheckSum:   connectionTest1Checksum,
 				Connection: &modconfig.Connection{Name: "b"},
+				KeyColumns: map[string][]string{},
 			},
 		},
 		expected: &ConnectionUpdates{Update: ConnectionDataMap{}, Delete: ConnectionDataMap{}, RequiredConnections: ConnectionDataMap{
@@ -165,11 +200,13 @@ connection "b" {
 				Plugin:     "hub.steampipe.io/plugins/turbot/connection-test-1@latest",
 				CheckSum:   connectionTest1Checksum,
 				Connection: &modconfig.Connection{Name: "a"},
+				KeyColumns: map[string][]string{},
 			},
 			"b": {
 				Plugin:     "hub.steampipe.io/plugins/turbot/connection-test-2@latest",
 				CheckSum:   connectionTest2Checksum,
 				Connection: &modconfig.Connection{Name: "b"},
+				KeyColumns: map[string][]string{},
 			},
 		},
 		expected: &ConnectionUpdates{Update: ConnectionDataMap{}, Delete: ConnectionDataMap{}, RequiredConnections: ConnectionDataMap{
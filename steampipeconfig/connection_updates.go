@@ -34,6 +34,8 @@ type ConnectionData struct {
 	CheckSum string
 	// the underlying connection object
 	Connection *modconfig.Connection
+	// map of table name to the names of its key columns, read from the plugin schema when the connection is updated
+	KeyColumns map[string][]string
 }
 
 func (p *ConnectionData) Equals(other *ConnectionData) bool {
@@ -97,10 +99,14 @@ func GetConnectionsToUpdate(schemas []string, connectionConfig map[string]*modco
 	// connections to create/update
 	for connection, requiredPlugin := range requiredConnections {
 		current, ok := connectionState[connection]
-		if !ok || !current.Equals(requiredPlugin) {
+		// if the key columns are not set, this is data from an old connection state file - update the connection
+		// so the key columns are read from the plugin schema
+		if !ok || !current.Equals(requiredPlugin) || current.KeyColumns == nil {
 			log.Printf("[TRACE] connection %s is out of date or missing\n", connection)
 			result.Update[connection] = requiredPlugin
+			continue
 		}
+		requiredPlugin.KeyColumns = current.KeyColumns
 	}
 
 	// connections to delete
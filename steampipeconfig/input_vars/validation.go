@@ -0,0 +1,91 @@
+package input_vars
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/terraform/tfdiags"
+	"github.com/turbot/steampipe/steampipeconfig/modconfig"
+	"github.com/turbot/steampipe/steampipeconfig/parse"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+)
+
+// EvaluateVariableValidations evaluates the validation rules of each variable against its final value,
+// i.e. its input value if one was given, otherwise its default
+// this should be called after CheckInputVariables, as values which are not valid for the variable type are skipped
+// the workspace path is used to build the functions available to the validation conditions
+func EvaluateVariableValidations(vcs map[string]*modconfig.Variable, vs InputValues, workspacePath string) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+
+	for name, vc := range vcs {
+		if len(vc.Validations) == 0 {
+			continue
+		}
+		// if no value was input, validate the default
+		rawValue, subject := vc.Default, vc.DeclRange
+		if val, isSet := vs[name]; isSet {
+			rawValue, subject = val.Value, val.SourceRange.ToHCL()
+		}
+		if rawValue == cty.NilVal {
+			// a required variable with no value - this will already have been reported
+			continue
+		}
+		value, err := convert.Convert(rawValue, vc.Type)
+		if err != nil {
+			// CheckInputVariables will already have reported this
+			continue
+		}
+
+		// the validation condition may only refer to the variable itself
+		evalCtx := &hcl.EvalContext{
+			Variables: map[string]cty.Value{
+				"var": cty.ObjectVal(map[string]cty.Value{name: value}),
+			},
+			Functions: parse.ContextFunctions(workspacePath),
+		}
+
+		for _, validation := range vc.Validations {
+			result, moreDiags := validation.Condition.Value(evalCtx)
+			if moreDiags.HasErrors() {
+				log.Printf("[TRACE] evaluating validation condition for variable %s failed", name)
+				diags = diags.Append(moreDiags)
+				continue
+			}
+			if !result.IsKnown() {
+				// we cannot validate an unknown value
+				continue
+			}
+			if result.IsNull() {
+				diags = diags.Append(&hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Invalid variable validation result",
+					Detail:   "Validation condition expression must return either true or false, not null.",
+					Subject:  validation.Condition.Range().Ptr(),
+				})
+				continue
+			}
+			result, err = convert.Convert(result, cty.Bool)
+			if err != nil {
+				diags = diags.Append(&hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Invalid variable validation result",
+					Detail:   fmt.Sprintf("Invalid validation condition result value: %s.", tfdiags.FormatError(err)),
+					Subject:  validation.Condition.Range().Ptr(),
+				})
+				continue
+			}
+
+			if result.False() {
+				diags = diags.Append(&hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  fmt.Sprintf("Invalid value for variable %q", name),
+					Detail:   fmt.Sprintf("%s\n\nThis was checked by the validation rule at %s.", validation.ErrorMessage, validation.DeclRange.String()),
+					Subject:  subject.Ptr(),
+				})
+			}
+		}
+	}
+	return diags
+}
@@ -0,0 +1,65 @@
+package input_vars
+
+import (
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/turbot/steampipe/steampipeconfig/modconfig"
+	"github.com/turbot/steampipe/steampipeconfig/modconfig/var_config"
+	"github.com/zclconf/go-cty/cty"
+)
+
+type evaluateValidationsTest struct {
+	defaultValue cty.Value
+	inputValue   cty.Value
+	expectError  bool
+}
+
+var testCasesEvaluateValidations = map[string]evaluateValidationsTest{
+	"valid default": {
+		defaultValue: cty.StringVal("us-east-1"),
+	},
+	"invalid default": {
+		defaultValue: cty.StringVal("eu-west-2"),
+		expectError:  true,
+	},
+	"valid input overrides invalid default": {
+		defaultValue: cty.StringVal("eu-west-2"),
+		inputValue:   cty.StringVal("us-east-1"),
+	},
+	"invalid input overrides valid default": {
+		defaultValue: cty.StringVal("us-east-1"),
+		inputValue:   cty.StringVal("eu-west-2"),
+		expectError:  true,
+	},
+	"required variable with no value": {
+		defaultValue: cty.NilVal,
+	},
+}
+
+func TestEvaluateVariableValidations(t *testing.T) {
+	condition, diags := hclsyntax.ParseExpression([]byte(`var.region != "eu-west-2"`), "test.sp", hcl.InitialPos)
+	if diags.HasErrors() {
+		t.Fatalf("failed to parse condition: %s", diags.Error())
+	}
+
+	for name, test := range testCasesEvaluateValidations {
+		vcs := map[string]*modconfig.Variable{
+			"region": {
+				Type:        cty.String,
+				Default:     test.defaultValue,
+				Validations: []*var_config.VariableValidation{{Condition: condition, ErrorMessage: "The region must not be eu-west-2."}},
+			},
+		}
+		vs := InputValues{}
+		if test.inputValue != cty.NilVal {
+			vs["region"] = &InputValue{Value: test.inputValue, SourceType: ValueFromCLIArg}
+		}
+
+		diags := EvaluateVariableValidations(vcs, vs, ".")
+		if diags.HasErrors() != test.expectError {
+			t.Errorf("Test: '%s' FAILED : expected error %v, got diags: %v", name, test.expectError, diags.Err())
+		}
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"path/filepath"
 
 	"github.com/hashicorp/hcl/v2"
+	"github.com/spf13/viper"
 	filehelpers "github.com/turbot/go-kit/files"
 	"github.com/turbot/go-kit/helpers"
 	"github.com/turbot/steampipe-plugin-sdk/plugin"
@@ -27,7 +28,7 @@ func LoadSteampipeConfig(workspacePath string, commandName string) (*SteampipeCo
 	defer utils.LogTime("steampipeconfig.LoadSteampipeConfig end")
 
 	_ = ensureDefaultConfigFile(constants.ConfigDir())
-	config, err := loadSteampipeConfig(workspacePath, commandName)
+	config, err := loadSteampipeConfig(workspacePath, commandName, viper.GetString(constants.ArgProfile))
 	if err != nil {
 		return nil, err
 	}
@@ -51,7 +52,7 @@ func ensureDefaultConfigFile(configFolder string) error {
 	return nil
 }
 
-func loadSteampipeConfig(workspacePath string, commandName string) (steampipeConfig *SteampipeConfig, err error) {
+func loadSteampipeConfig(workspacePath string, commandName string, profileName string) (steampipeConfig *SteampipeConfig, err error) {
 	utils.LogTime("steampipeconfig.loadSteampipeConfig start")
 	defer utils.LogTime("steampipeconfig.loadSteampipeConfig end")
 
@@ -80,13 +81,23 @@ func loadSteampipeConfig(workspacePath string, commandName string) (steampipeCon
 
 		// only include workspace.spc from workspace directory
 		include = filehelpers.InclusionsFromFiles([]string{constants.WorkspaceConfigFileName})
-		// update load options to ONLY allow terminal options
-		loadOptions = &loadConfigOptions{include: include, allowedOptions: []string{options.TerminalBlock}}
+		// update load options to ONLY allow terminal options and profiles
+		loadOptions = &loadConfigOptions{include: include, allowedOptions: []string{options.TerminalBlock}, allowProfiles: true}
 		if err := loadConfig(workspacePath, steampipeConfig, loadOptions); err != nil {
 			return nil, fmt.Errorf("failed to load workspace config: %v", err)
 		}
 	}
 
+	// if a profile has been specified, apply it
+	// profiles are only defined in the workspace config, so this fails if there is no workspace
+	if profileName != "" {
+		profile, ok := steampipeConfig.Profiles[profileName]
+		if !ok {
+			return nil, fmt.Errorf("unknown profile '%s' - profiles must be defined in the workspace %s", profileName, constants.WorkspaceConfigFileName)
+		}
+		steampipeConfig.ApplyProfile(profile)
+	}
+
 	// now set default options on all connections without options set
 	steampipeConfig.setDefaultConnectionOptions()
 
@@ -102,6 +113,7 @@ func loadSteampipeConfig(workspacePath string, commandName string) (steampipeCon
 type loadConfigOptions struct {
 	include        []string
 	allowedOptions []string
+	allowProfiles  bool
 }
 
 func loadConfig(configFolder string, steampipeConfig *SteampipeConfig, opts *loadConfigOptions) error {
@@ -173,6 +185,20 @@ func loadConfig(configFolder string, steampipeConfig *SteampipeConfig, opts *loa
 			// if options are already set, this will merge the new options over the top of the existing options
 			// i.e. new options have precedence
 			steampipeConfig.SetOptions(options)
+
+		case "profile":
+			if !opts.allowProfiles {
+				return fmt.Errorf("profile blocks are only permitted in %s - found in '%s'", constants.WorkspaceConfigFileName, block.TypeRange.Filename)
+			}
+			profile, moreDiags := parse.DecodeProfile(block, fileData)
+			if moreDiags.HasErrors() {
+				diags = append(diags, moreDiags...)
+				continue
+			}
+			if _, alreadyThere := steampipeConfig.Profiles[profile.Name]; alreadyThere {
+				return fmt.Errorf("duplicate profile name: '%s' in '%s'", profile.Name, block.TypeRange.Filename)
+			}
+			steampipeConfig.Profiles[profile.Name] = profile
 		}
 	}
 
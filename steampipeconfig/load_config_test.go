@@ -3,6 +3,7 @@ package steampipeconfig
 import (
 	"fmt"
 	"path/filepath"
+	"reflect"
 	"testing"
 
 	"github.com/turbot/steampipe/steampipeconfig/modconfig"
@@ -264,7 +265,7 @@ func TestLoadConfig(t *testing.T) {
 		constants.SteampipeDir = steampipeDir
 
 		// now load config
-		config, err := loadSteampipeConfig(workspaceDir, "")
+		config, err := loadSteampipeConfig(workspaceDir, "", "")
 		if err != nil {
 			if test.expected != "ERROR" {
 				t.Errorf("Test: '%s'' FAILED with unexpected error: %v", name, err)
@@ -285,6 +286,74 @@ func TestLoadConfig(t *testing.T) {
 	}
 }
 
+type loadConfigProfileTest struct {
+	workspaceDir string
+	profileName  string
+	// map of connection name to plugin
+	expectedConnections map[string]string
+	expectedSearchPath  []string
+	expectError         bool
+}
+
+// resolve the test paths now, as other tests change the working directory
+var profileConfigDir, _ = filepath.Abs("test_data/connection_config/single_connection")
+var profileWorkspaceDir, _ = filepath.Abs("test_data/workspaces/profiles")
+
+var testCasesLoadConfigProfile = map[string]loadConfigProfileTest{
+	"no profile": {
+		workspaceDir:        profileWorkspaceDir,
+		expectedConnections: map[string]string{"a": "test_data/connection-test-1"},
+	},
+	"profile overrides and adds connections": {
+		workspaceDir: profileWorkspaceDir,
+		profileName:  "dev",
+		expectedConnections: map[string]string{
+			"a": "test_data/connection-test-2",
+			"b": "test_data/connection-test-1",
+		},
+		expectedSearchPath: []string{"b", "a"},
+	},
+	"unknown profile": {
+		workspaceDir: profileWorkspaceDir,
+		profileName:  "prod",
+		expectError:  true,
+	},
+	"profile with no workspace": {
+		profileName: "dev",
+		expectError: true,
+	},
+}
+
+func TestLoadConfigProfile(t *testing.T) {
+	constants.SteampipeDir = profileConfigDir
+	for name, test := range testCasesLoadConfigProfile {
+		config, err := loadSteampipeConfig(test.workspaceDir, "", test.profileName)
+		if err != nil {
+			if !test.expectError {
+				t.Errorf("Test: '%s' FAILED with unexpected error: %v", name, err)
+			}
+			continue
+		}
+		if test.expectError {
+			t.Errorf("Test: '%s' FAILED - expected error", name)
+			continue
+		}
+
+		if len(config.Connections) != len(test.expectedConnections) {
+			t.Errorf("Test: '%s' FAILED : expected %d connections, got %d", name, len(test.expectedConnections), len(config.Connections))
+		}
+		for connectionName, plugin := range test.expectedConnections {
+			if connection, ok := config.Connections[connectionName]; !ok || connection.PluginShortName != plugin {
+				t.Errorf("Test: '%s' FAILED : expected connection '%s' to use plugin '%s'", name, connectionName, plugin)
+			}
+		}
+		searchPath, _ := config.ConfigMap()[constants.ArgSearchPath].([]string)
+		if !reflect.DeepEqual(searchPath, test.expectedSearchPath) {
+			t.Errorf("Test: '%s' FAILED : expected search path %v, got %v", name, test.expectedSearchPath, searchPath)
+		}
+	}
+}
+
 // helpers
 func SteampipeConfigEquals(l, r *SteampipeConfig) bool {
 	if l == nil || r == nil {
@@ -5,9 +5,12 @@ import (
 	"os"
 	"path/filepath"
 	"reflect"
+	"sort"
+	"strings"
 	"testing"
 
 	filehelpers "github.com/turbot/go-kit/files"
+	typehelpers "github.com/turbot/go-kit/types"
 	"github.com/turbot/steampipe/constants"
 	"github.com/turbot/steampipe/steampipeconfig/modconfig"
 	"github.com/turbot/steampipe/steampipeconfig/parse"
@@ -560,3 +563,81 @@ func TestLoadModResourceNames(t *testing.T) {
 		}
 	}
 }
+
+type benchmarkIncludeTest struct {
+	severity string
+	tags     map[string]string
+	args     map[string]string
+	parents  []string
+}
+
+// resolve the mod path at init, as other tests change the working directory
+var benchmarkIncludeModPath, _ = filepath.Abs("test_data/mods/benchmark_include")
+
+var testCasesBenchmarkInclude = map[string]benchmarkIncludeTest{
+	"control.b1_c1": {
+		severity: "critical",
+		tags:     map[string]string{"service": "s3", "team": "security", "source": "dep"},
+		args:     map[string]string{"region": "'eu-west-2'"},
+		parents:  []string{"benchmark.b1_sub", "benchmark.b1_top"},
+	},
+	"control.b1_c3": {
+		severity: "high",
+		tags:     map[string]string{"source": "dep"},
+		args:     map[string]string{},
+		parents:  []string{"benchmark.b1"},
+	},
+}
+
+func TestLoadModBenchmarkInclude(t *testing.T) {
+	modPath := benchmarkIncludeModPath
+	runCtx := parse.NewRunContext(modPath, parse.CreateDefaultMod, &filehelpers.ListOptions{
+		Exclude: []string{fmt.Sprintf("**/%s*", constants.WorkspaceDataDir)},
+		Flags:   filehelpers.Files,
+	})
+	mod, err := LoadMod(modPath, runCtx)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	if len(mod.Controls) != len(testCasesBenchmarkInclude) {
+		t.Errorf("Test: 'benchmark_include' FAILED : expected %d controls, got %d", len(testCasesBenchmarkInclude), len(mod.Controls))
+	}
+	for name, test := range testCasesBenchmarkInclude {
+		control, ok := mod.Controls[name]
+		if !ok {
+			t.Errorf("Test: '%s' FAILED : control not found", name)
+			continue
+		}
+		if severity := typehelpers.SafeString(control.Severity); severity != test.severity {
+			t.Errorf("Test: '%s' FAILED : expected severity %s, got %s", name, test.severity, severity)
+		}
+		if !reflect.DeepEqual(control.GetTags(), test.tags) {
+			t.Errorf("Test: '%s' FAILED : expected tags %v, got %v", name, test.tags, control.GetTags())
+		}
+		if !reflect.DeepEqual(control.Args.Args, test.args) {
+			t.Errorf("Test: '%s' FAILED : expected args %v, got %v", name, test.args, control.Args.Args)
+		}
+		parents := control.GetParentNames()
+		sort.Strings(parents)
+		if !reflect.DeepEqual(parents, test.parents) {
+			t.Errorf("Test: '%s' FAILED : expected parents %v, got %v", name, test.parents, parents)
+		}
+	}
+}
+
+var benchmarkIncludeCollisionModPath, _ = filepath.Abs("test_data/mods/benchmark_include_collision")
+
+func TestLoadModBenchmarkIncludeCollision(t *testing.T) {
+	modPath := benchmarkIncludeCollisionModPath
+	runCtx := parse.NewRunContext(modPath, parse.CreateDefaultMod, &filehelpers.ListOptions{
+		Exclude: []string{fmt.Sprintf("**/%s*", constants.WorkspaceDataDir)},
+		Flags:   filehelpers.Files,
+	})
+	_, err := LoadMod(modPath, runCtx)
+	// the error names the benchmark which includes the control
+	expected := "benchmark.b1 includes 'dep.control.c3', which would be added as 'control.b1_c3'"
+	if err == nil || !strings.Contains(err.Error(), expected) {
+		t.Errorf("Test: 'benchmark_include_collision' FAILED : expected error containing %q, got %v", expected, err)
+	}
+}
@@ -30,6 +30,12 @@ type Benchmark struct {
 	Tags          *map[string]string `cty:"tags" hcl:"tags" column:"tags,jsonb"`
 	Title         *string            `cty:"title" hcl:"title" column:"title,text"`
 
+	// benchmarks and controls of dependency mods to include as children of this benchmark,
+	// controls and benchmarks of the included trees to exclude, and overrides to apply to the included controls
+	Include   *hcl.Attribute       `hcl:"include,optional"`
+	Exclude   *hcl.Attribute       `hcl:"exclude,optional"`
+	Overrides []*BenchmarkOverride `hcl:"override,block"`
+
 	// list of all block referenced by the resource
 	References []*ResourceReference
 
@@ -198,6 +204,38 @@ func (b *Benchmark) AddChild(child ModTreeItem) error {
 	return fmt.Errorf("benchmark '%s' has no child '%s'", b.Name(), child.Name())
 }
 
+// AddChildName adds a child name to the benchmark - the child is added when the resource tree is built
+// this is used to add the resources included from dependency mods
+func (b *Benchmark) AddChildName(name string) {
+	if b.ChildNames == nil {
+		b.ChildNames = &[]NamedItem{}
+	}
+	*b.ChildNames = append(*b.ChildNames, NamedItem{Name: name})
+	b.ChildNameStrings = append(b.ChildNameStrings, name)
+	b.children = append(b.children, nil)
+}
+
+// Clone returns a copy of the benchmark with the given short name, belonging to the given mod
+// the copy has no children or parents
+func (b *Benchmark) Clone(shortName string, mod *Mod) *Benchmark {
+	res := &Benchmark{
+		ShortName:     shortName,
+		FullName:      fmt.Sprintf("benchmark.%s", shortName),
+		Description:   b.Description,
+		Documentation: b.Documentation,
+		Title:         b.Title,
+		Mod:           mod,
+		DeclRange:     b.DeclRange,
+	}
+	if b.Tags != nil {
+		res.Tags = copyTags(*b.Tags)
+	}
+	if b.metadata != nil {
+		res.metadata = b.metadata.clone(res.Name(), mod)
+	}
+	return res
+}
+
 // AddParent implements ModTreeItem
 func (b *Benchmark) AddParent(parent ModTreeItem) error {
 	b.parents = append(b.parents, parent)
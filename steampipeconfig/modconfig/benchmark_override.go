@@ -0,0 +1,52 @@
+package modconfig
+
+import "github.com/hashicorp/hcl/v2"
+
+// BenchmarkOverride is a struct representing an 'override' block of a benchmark
+// it overrides the properties of controls included from a dependency mod
+type BenchmarkOverride struct {
+	// the included controls to override - if omitted, the override applies to all included controls
+	Controls *hcl.Attribute `hcl:"controls,optional"`
+	Severity *string        `hcl:"severity"`
+	// tags are merged with the control tags
+	Tags *map[string]string `hcl:"tags"`
+	// named args are merged with the control args, positional args replace them
+	Args *hcl.Attribute `hcl:"args,optional"`
+}
+
+// Apply applies the severity and tags overrides to the control
+// (args must be evaluated so are applied by the parser)
+func (o *BenchmarkOverride) Apply(control *Control) {
+	if o.Severity != nil {
+		control.Severity = o.Severity
+	}
+	if o.Tags != nil {
+		if control.Tags == nil {
+			control.Tags = &map[string]string{}
+		}
+		for k, v := range *o.Tags {
+			(*control.Tags)[k] = v
+		}
+	}
+}
+
+// ApplyArgs applies the args overrides to the control
+func (o *BenchmarkOverride) ApplyArgs(control *Control, args *QueryArgs) {
+	if control.Args == nil {
+		control.Args = NewQueryArgs()
+	}
+	if len(args.ArgsList) > 0 {
+		control.Args.ArgsList = args.ArgsList
+	}
+	for k, v := range args.Args {
+		control.Args.Args[k] = v
+	}
+}
+
+func copyTags(tags map[string]string) *map[string]string {
+	res := make(map[string]string, len(tags))
+	for k, v := range tags {
+		res[k] = v
+	}
+	return &res
+}
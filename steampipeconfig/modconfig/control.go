@@ -165,6 +165,40 @@ func (c *Control) GetParentNames() []string {
 	return parents
 }
 
+// Clone returns a copy of the control with the given short name, belonging to the given mod
+// the copy has no parents
+func (c *Control) Clone(shortName string, mod *Mod) *Control {
+	res := &Control{
+		ShortName:        shortName,
+		FullName:         fmt.Sprintf("control.%s", shortName),
+		Description:      c.Description,
+		Documentation:    c.Documentation,
+		SearchPath:       c.SearchPath,
+		SearchPathPrefix: c.SearchPathPrefix,
+		Severity:         c.Severity,
+		SQL:              c.SQL,
+		Title:            c.Title,
+		Query:            c.Query,
+		Args:             NewQueryArgs(),
+		Params:           c.Params,
+		Mod:              mod,
+		DeclRange:        c.DeclRange,
+	}
+	if c.Tags != nil {
+		res.Tags = copyTags(*c.Tags)
+	}
+	if c.Args != nil {
+		for k, v := range c.Args.Args {
+			res.Args.Args[k] = v
+		}
+		res.Args.ArgsList = append(res.Args.ArgsList, c.Args.ArgsList...)
+	}
+	if c.metadata != nil {
+		res.metadata = c.metadata.clone(res.Name(), mod)
+	}
+	return res
+}
+
 // AddChild implements ModTreeItem - controls cannot have children so just return error
 func (c *Control) AddChild(child ModTreeItem) error {
 	return errors.New("cannot add child to a control")
@@ -0,0 +1,112 @@
+package modconfig
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// ControlTest is a struct representing a Test resource
+// a test runs a control against fixture data and asserts the control results
+type ControlTest struct {
+	ShortName   string
+	FullName    string  `cty:"name"`
+	Description *string `cty:"description"`
+	Title       *string `cty:"title"`
+
+	// the name of the control under test, e.g. control.c1 or m1.control.c1
+	ControlName string
+	// fixture data, loaded into temporary tables which shadow the tables queried by the control
+	Fixtures []*TestFixture
+	// the expected control results
+	Expect []*TestExpectation
+
+	Mod       *Mod `cty:"mod"`
+	DeclRange hcl.Range
+
+	metadata *ResourceMetadata
+}
+
+// TestFixture is the fixture data for a single table
+type TestFixture struct {
+	Table string
+	// optional map of column name to postgres type - if a column type is not given it is inferred from the row values
+	Columns map[string]string
+	Rows    []map[string]cty.Value
+	// the names of all columns, sorted - the order of the row attributes is not retained when they are decoded
+	ColumnNames []string
+	DeclRange   hcl.Range
+}
+
+// TestExpectation is an expected control result row
+type TestExpectation struct {
+	Resource string
+	Status   string
+	// if set, the result reason must also match
+	Reason *string
+}
+
+func (e *TestExpectation) String() string {
+	if e.Reason != nil {
+		return fmt.Sprintf("%s: %s (%s)", e.Status, e.Resource, *e.Reason)
+	}
+	return fmt.Sprintf("%s: %s", e.Status, e.Resource)
+}
+
+func NewControlTest(block *hcl.Block) *ControlTest {
+	return &ControlTest{
+		ShortName: block.Labels[0],
+		FullName:  fmt.Sprintf("test.%s", block.Labels[0]),
+		DeclRange: block.DefRange,
+	}
+}
+
+// Name implements HclResource, ResourceWithMetadata
+func (t *ControlTest) Name() string {
+	return t.FullName
+}
+
+// GetTitle returns the test title, falling back to the name
+func (t *ControlTest) GetTitle() string {
+	if t.Title != nil {
+		return *t.Title
+	}
+	return t.FullName
+}
+
+// GetMetadata implements ResourceWithMetadata
+func (t *ControlTest) GetMetadata() *ResourceMetadata {
+	return t.metadata
+}
+
+// SetMetadata implements ResourceWithMetadata
+func (t *ControlTest) SetMetadata(metadata *ResourceMetadata) {
+	t.metadata = metadata
+}
+
+// OnDecoded implements HclResource
+func (t *ControlTest) OnDecoded(*hcl.Block) hcl.Diagnostics { return nil }
+
+// AddReference implements HclResource
+func (t *ControlTest) AddReference(*ResourceReference) {}
+
+// SetMod implements HclResource
+func (t *ControlTest) SetMod(mod *Mod) {
+	t.Mod = mod
+}
+
+// GetMod implements HclResource
+func (t *ControlTest) GetMod() *Mod {
+	return t.Mod
+}
+
+// CtyValue implements HclResource
+func (t *ControlTest) CtyValue() (cty.Value, error) {
+	return getCtyValue(t)
+}
+
+// GetDeclRange implements HclResource
+func (t *ControlTest) GetDeclRange() *hcl.Range {
+	return &t.DeclRange
+}
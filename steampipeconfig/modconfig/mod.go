@@ -54,6 +54,7 @@ type Mod struct {
 	Panels     map[string]*Panel
 	Variables  map[string]*Variable
 	Locals     map[string]*Local
+	Tests      map[string]*ControlTest
 
 	// flat list of all resources
 	AllResources map[string]HclResource
@@ -80,6 +81,7 @@ func NewMod(shortName, modPath string, defRange hcl.Range) *Mod {
 		Panels:       make(map[string]*Panel),
 		Variables:    make(map[string]*Variable),
 		Locals:       make(map[string]*Local),
+		Tests:        make(map[string]*ControlTest),
 		ModPath:      modPath,
 		DeclRange:    defRange,
 		AllResources: make(map[string]HclResource),
@@ -211,6 +213,17 @@ func (m *Mod) Equals(other *Mod) bool {
 			return false
 		}
 	}
+	// tests
+	for k := range m.Tests {
+		if _, ok := other.Tests[k]; !ok {
+			return false
+		}
+	}
+	for k := range other.Tests {
+		if _, ok := m.Tests[k]; !ok {
+			return false
+		}
+	}
 	return true
 
 }
@@ -432,6 +445,16 @@ func (m *Mod) AddResource(item HclResource) hcl.Diagnostics {
 		} else {
 			m.Locals[name] = r
 		}
+
+	case *ControlTest:
+		name := r.Name()
+		// check for dupes
+		if _, ok := m.Tests[name]; ok {
+			diags = append(diags, duplicateResourceDiagnostics(item))
+			break
+		} else {
+			m.Tests[name] = r
+		}
 	}
 	m.AllResources[item.Name()] = item
 	return diags
@@ -24,6 +24,7 @@ type Panel struct {
 	Source  *string `hcl:"source"`
 	SQL     *string `hcl:"source"`
 	Text    *string `hcl:"text"`
+	Refresh *int    `hcl:"refresh"`
 	Reports []*Report
 	Panels  []*Panel
 
@@ -224,6 +225,13 @@ func (p *Panel) Diff(new *Panel) *ReportTreeItemDiffs {
 	} else if *p.Height != *new.Height {
 		res.AddPropertyDiff("Height")
 	}
+	if p.Refresh == nil || new.Refresh == nil {
+		if !(p.Refresh == nil && new.Refresh == nil) {
+			res.AddPropertyDiff("Refresh")
+		}
+	} else if *p.Refresh != *new.Refresh {
+		res.AddPropertyDiff("Refresh")
+	}
 
 	res.populateChildDiffs(p, new)
 
@@ -13,6 +13,9 @@ type ParamDef struct {
 	Description *string     `cty:"description" json:"description"`
 	RawDefault  interface{} `json:"-"`
 	Default     *string     `cty:"default" json:"default"`
+	// the param type - one of string, number, bool, list or map (may be empty if no type is declared)
+	Type        string             `cty:"type" json:"type,omitempty"`
+	Validations []*ParamValidation `json:"validations,omitempty"`
 
 	// list of all blocks referenced by the resource
 	References []*ResourceReference
@@ -31,11 +34,22 @@ func NewParamDef(block *hcl.Block, parent string) *ParamDef {
 }
 
 func (p ParamDef) String() string {
-	return fmt.Sprintf("Name: %s, Description: %s, Default: %s", p.FullName, typehelpers.SafeString(p.Description), typehelpers.SafeString(p.Default))
+	return fmt.Sprintf("Name: %s, Description: %s, Default: %s, Type: %s", p.FullName, typehelpers.SafeString(p.Description), typehelpers.SafeString(p.Default), p.Type)
 }
 
 func (p ParamDef) Equals(other *ParamDef) bool {
 	return p.Name == other.Name &&
 		typehelpers.SafeString(p.Description) == typehelpers.SafeString(other.Description) &&
-		typehelpers.SafeString(p.Default) == typehelpers.SafeString(other.Default)
+		typehelpers.SafeString(p.Default) == typehelpers.SafeString(other.Default) &&
+		p.Type == other.Type &&
+		paramValidationsEqual(p.Validations, other.Validations)
+}
+
+// PgType returns the postgres type used to bind the param to the prepared statement
+// if the param has no declared type, 'unknown' is returned, meaning postgres infers the type from the query
+func (p ParamDef) PgType() string {
+	if pgType, ok := paramPgTypes[p.Type]; ok {
+		return pgType
+	}
+	return "unknown"
 }
@@ -0,0 +1,334 @@
+package modconfig
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	typehelpers "github.com/turbot/go-kit/types"
+)
+
+// param types
+const (
+	ParamTypeString = "string"
+	ParamTypeNumber = "number"
+	ParamTypeBool   = "bool"
+	ParamTypeList   = "list"
+	ParamTypeMap    = "map"
+)
+
+// map of param type to the postgres type used to bind it to a prepared statement
+var paramPgTypes = map[string]string{
+	ParamTypeString: "text",
+	ParamTypeNumber: "numeric",
+	ParamTypeBool:   "boolean",
+	ParamTypeList:   "text[]",
+	ParamTypeMap:    "jsonb",
+}
+
+// IsValidParamType returns whether the given string is a supported param type
+func IsValidParamType(paramType string) bool {
+	_, ok := paramPgTypes[paramType]
+	return ok
+}
+
+// ParamValidation is a validation rule for a param value, defined in a 'validation' block in the param
+// - AllowedValues restricts the value (or for a list, each element) to the given values
+// - Regex requires the value (or for a list, each element) to match the given regular expression
+// - Min and Max give the range of a number value, the length of a string or list value, or the number of keys of a map value
+// AllowedValues and Regex may not be used for a map param
+type ParamValidation struct {
+	AllowedValues []string `json:"allowed_values,omitempty"`
+	Regex         *string  `json:"regex,omitempty"`
+	Min           *float64 `json:"min,omitempty"`
+	Max           *float64 `json:"max,omitempty"`
+	ErrorMessage  *string  `json:"error_message,omitempty"`
+
+	DeclRange hcl.Range `json:"-"`
+
+	// the compiled Regex, set by CompileRegex
+	compiledRegex *regexp.Regexp
+}
+
+func NewParamValidation(block *hcl.Block) *ParamValidation {
+	return &ParamValidation{
+		DeclRange: block.DefRange,
+	}
+}
+
+// CompileRegex compiles the Regex (if set), so it is not compiled each time a value is validated
+func (v *ParamValidation) CompileRegex() error {
+	if v.Regex == nil {
+		return nil
+	}
+	regex, err := regexp.Compile(*v.Regex)
+	if err != nil {
+		return err
+	}
+	v.compiledRegex = regex
+	return nil
+}
+
+func (v *ParamValidation) Equals(other *ParamValidation) bool {
+	if len(v.AllowedValues) != len(other.AllowedValues) {
+		return false
+	}
+	for i, allowed := range v.AllowedValues {
+		if other.AllowedValues[i] != allowed {
+			return false
+		}
+	}
+	return typehelpers.SafeString(v.Regex) == typehelpers.SafeString(other.Regex) &&
+		float64PtrEqual(v.Min, other.Min) &&
+		float64PtrEqual(v.Max, other.Max) &&
+		typehelpers.SafeString(v.ErrorMessage) == typehelpers.SafeString(other.ErrorMessage)
+}
+
+// validate the parsed param value against this rule
+func (v *ParamValidation) validate(value interface{}) error {
+	// for a list, the allowed values and regex apply to each element
+	var elements []string
+	var length int
+	switch t := value.(type) {
+	case []string:
+		elements = t
+		length = len(t)
+	case string:
+		elements = []string{t}
+		length = len([]rune(t))
+	case map[string]interface{}:
+		length = len(t)
+	case float64:
+		elements = []string{strconv.FormatFloat(t, 'f', -1, 64)}
+	case bool:
+		elements = []string{strconv.FormatBool(t)}
+	}
+
+	regex := v.compiledRegex
+	if regex == nil && v.Regex != nil {
+		// the regex is compiled when the param is decoded - if the validation was not decoded, compile it now
+		var err error
+		if regex, err = regexp.Compile(*v.Regex); err != nil {
+			return err
+		}
+	}
+	for _, element := range elements {
+		if len(v.AllowedValues) > 0 && !isAllowedValue(element, v.AllowedValues) {
+			return fmt.Errorf("value '%s' is not one of the allowed values: %s", element, strings.Join(v.AllowedValues, ", "))
+		}
+		if regex != nil && !regex.MatchString(element) {
+			return fmt.Errorf("value '%s' does not match the regular expression '%s'", element, *v.Regex)
+		}
+	}
+
+	// for numbers, min and max apply to the value - for strings and lists they apply to the length,
+	// and for maps to the number of keys
+	if number, ok := value.(float64); ok {
+		if v.Min != nil && number < *v.Min {
+			return fmt.Errorf("value %s is less than the minimum of %s", elements[0], formatFloat(*v.Min))
+		}
+		if v.Max != nil && number > *v.Max {
+			return fmt.Errorf("value %s is greater than the maximum of %s", elements[0], formatFloat(*v.Max))
+		}
+		return nil
+	}
+	if _, ok := value.(bool); ok {
+		return nil
+	}
+	if _, ok := value.(map[string]interface{}); ok {
+		if v.Min != nil && float64(length) < *v.Min {
+			return fmt.Errorf("number of keys %d is less than the minimum of %s", length, formatFloat(*v.Min))
+		}
+		if v.Max != nil && float64(length) > *v.Max {
+			return fmt.Errorf("number of keys %d is greater than the maximum of %s", length, formatFloat(*v.Max))
+		}
+		return nil
+	}
+	if v.Min != nil && float64(length) < *v.Min {
+		return fmt.Errorf("length %d is less than the minimum of %s", length, formatFloat(*v.Min))
+	}
+	if v.Max != nil && float64(length) > *v.Max {
+		return fmt.Errorf("length %d is greater than the maximum of %s", length, formatFloat(*v.Max))
+	}
+	return nil
+}
+
+// ValidateValue checks a param value (in postgres format) against the param type and validation rules
+// any failure is returned as a diagnostic pointing at the param (or validation block) declaration
+func (p ParamDef) ValidateValue(value string) hcl.Diagnostics {
+	var diags hcl.Diagnostics
+	summary := fmt.Sprintf("Invalid value for parameter '%s'", p.Name)
+
+	parsedValue, err := parsePgParamValue(value, p.Type)
+	if err != nil {
+		return append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  summary,
+			Detail:   err.Error(),
+			Subject:  &p.DeclRange,
+		})
+	}
+
+	for _, validation := range p.Validations {
+		if err := validation.validate(parsedValue); err != nil {
+			detail := err.Error()
+			if validation.ErrorMessage != nil {
+				detail = *validation.ErrorMessage
+			}
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  summary,
+				Detail:   detail,
+				Subject:  &validation.DeclRange,
+			})
+		}
+	}
+	return diags
+}
+
+// validate resolved param values (in the same order as the param defs)
+// empty values are ignored - these are reported as missing params
+func validateParamValues(params []*ParamDef, values []string) hcl.Diagnostics {
+	var diags hcl.Diagnostics
+	for i, param := range params {
+		if i < len(values) && values[i] != "" {
+			diags = append(diags, param.ValidateValue(values[i])...)
+		}
+	}
+	return diags
+}
+
+// parse a param value in postgres format, e.g. 'foo', 10, true, array['a','b'] or '{"a":"b"}'::jsonb
+// if a param type is given, the value is validated as being of that type
+func parsePgParamValue(value, paramType string) (interface{}, error) {
+	value = strings.TrimSpace(value)
+	unquoted, _ := unquotePgString(value)
+
+	switch paramType {
+	case ParamTypeNumber:
+		number, err := strconv.ParseFloat(unquoted, 64)
+		if err != nil {
+			return nil, fmt.Errorf("expected a number but got %s", value)
+		}
+		return number, nil
+	case ParamTypeBool:
+		b, err := strconv.ParseBool(unquoted)
+		if err != nil {
+			return nil, fmt.Errorf("expected a bool but got %s", value)
+		}
+		return b, nil
+	case ParamTypeList:
+		elements, ok := parsePgArray(value)
+		if !ok {
+			return nil, fmt.Errorf("expected a list but got %s", value)
+		}
+		return elements, nil
+	case ParamTypeMap:
+		var m map[string]interface{}
+		if err := json.Unmarshal([]byte(unquoted), &m); err != nil {
+			return nil, fmt.Errorf("expected a map but got %s", value)
+		}
+		return m, nil
+	default:
+		return unquoted, nil
+	}
+}
+
+// if the value is a quoted postgres string (optionally with a type cast), return the unescaped string contents
+func unquotePgString(value string) (string, bool) {
+	if !strings.HasPrefix(value, "'") {
+		return value, false
+	}
+	closingQuoteIdx := strings.LastIndex(value, "'")
+	if closingQuoteIdx == 0 {
+		return value, false
+	}
+	if suffix := value[closingQuoteIdx+1:]; suffix != "" && !strings.HasPrefix(suffix, "::") {
+		return value, false
+	}
+	return strings.ReplaceAll(value[1:closingQuoteIdx], "''", "'"), true
+}
+
+// parse a postgres array, either in constructor form, array['a','b'], or as an array literal, '{a,b}'
+func parsePgArray(value string) ([]string, bool) {
+	if strings.HasPrefix(strings.ToLower(value), "array[") && strings.HasSuffix(value, "]") {
+		inner := strings.TrimSpace(value[len("array[") : len(value)-1])
+		if inner == "" {
+			return []string{}, true
+		}
+		var elements []string
+		for _, element := range splitOutsideQuotes(inner, ',') {
+			unquoted, _ := unquotePgString(strings.TrimSpace(element))
+			elements = append(elements, unquoted)
+		}
+		return elements, true
+	}
+	if literal, ok := unquotePgString(value); ok && strings.HasPrefix(literal, "{") && strings.HasSuffix(literal, "}") {
+		inner := strings.TrimSpace(literal[1 : len(literal)-1])
+		if inner == "" {
+			return []string{}, true
+		}
+		var elements []string
+		for _, element := range splitOutsideQuotes(inner, ',') {
+			elements = append(elements, strings.Trim(strings.TrimSpace(element), `"`))
+		}
+		return elements, true
+	}
+	return nil, false
+}
+
+// split the string on the separator, ignoring separators inside single or double quotes
+func splitOutsideQuotes(s string, separator rune) []string {
+	var res []string
+	var current strings.Builder
+	var quote rune
+	for _, c := range s {
+		switch {
+		case quote != 0 && c == quote:
+			quote = 0
+		case quote == 0 && (c == '\'' || c == '"'):
+			quote = c
+		case quote == 0 && c == separator:
+			res = append(res, current.String())
+			current.Reset()
+			continue
+		}
+		current.WriteRune(c)
+	}
+	return append(res, current.String())
+}
+
+func isAllowedValue(value string, allowedValues []string) bool {
+	for _, allowed := range allowedValues {
+		if value == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+func paramValidationsEqual(a, b []*ParamValidation) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i, v := range a {
+		if !v.Equals(b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func float64PtrEqual(a, b *float64) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
@@ -6,15 +6,18 @@ import (
 )
 
 const (
-	BlockTypeMod       = "mod"
-	BlockTypeQuery     = "query"
-	BlockTypeControl   = "control"
-	BlockTypeBenchmark = "benchmark"
-	BlockTypeReport    = "report"
-	BlockTypePanel     = "panel"
-	BlockTypeLocals    = "locals"
-	BlockTypeVariable  = "variable"
-	BlockTypeParam     = "param"
+	BlockTypeMod        = "mod"
+	BlockTypeQuery      = "query"
+	BlockTypeControl    = "control"
+	BlockTypeBenchmark  = "benchmark"
+	BlockTypeReport     = "report"
+	BlockTypePanel      = "panel"
+	BlockTypeLocals     = "locals"
+	BlockTypeVariable   = "variable"
+	BlockTypeParam      = "param"
+	BlockTypeValidation = "validation"
+	BlockTypeTest       = "test"
+	BlockTypeFixture    = "fixture"
 )
 
 type ParsedResourceName struct {
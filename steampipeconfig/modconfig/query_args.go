@@ -89,6 +89,11 @@ func (q *QueryArgs) ResolveAsString(source PreparedStatementProvider) (string, e
 		return "", err
 	}
 
+	// validate the resolved values against the param types and validation rules
+	if diags := validateParamValues(source.GetParams(), paramStrs); diags.HasErrors() {
+		return "", diags
+	}
+
 	// did we resolve them all?
 	if len(missingParams) > 0 {
 		return "", fmt.Errorf("ResolveAsString failed for %s - failed to resolve value for %d %s: %s",
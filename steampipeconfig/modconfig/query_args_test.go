@@ -100,6 +100,97 @@ var testCasesResolveParams = map[string]resolveParamsTest{
 		paramDefs: nil,
 		expected:  "ERROR",
 	},
+	"typed params with valid values": {
+		args: &QueryArgs{
+			ArgsList: []string{"'val1'", "10", "true", "array['a','b']", `'{"k":"v"}'::jsonb`},
+		},
+		paramDefs: []*ParamDef{
+			{Name: "p1", Type: ParamTypeString},
+			{Name: "p2", Type: ParamTypeNumber},
+			{Name: "p3", Type: ParamTypeBool},
+			{Name: "p4", Type: ParamTypeList},
+			{Name: "p5", Type: ParamTypeMap},
+		},
+		expected: `('val1',10,true,array['a','b'],'{"k":"v"}'::jsonb)`,
+	},
+	"number param with invalid value": {
+		args: &QueryArgs{
+			ArgsList: []string{"'ten'"},
+		},
+		paramDefs: []*ParamDef{
+			{Name: "p1", Type: ParamTypeNumber},
+		},
+		expected: "ERROR",
+	},
+	"list param with invalid value": {
+		args: &QueryArgs{
+			Args: map[string]string{
+				"p1": "'a'",
+			},
+		},
+		paramDefs: []*ParamDef{
+			{Name: "p1", Type: ParamTypeList},
+		},
+		expected: "ERROR",
+	},
+	"params passing validation": {
+		args: &QueryArgs{
+			ArgsList: []string{"'us-east-1'", "5", "array['a','b']"},
+		},
+		paramDefs: []*ParamDef{
+			{Name: "p1", Type: ParamTypeString, Validations: []*ParamValidation{{Regex: utils.ToStringPointer("^us-")}}},
+			{Name: "p2", Type: ParamTypeNumber, Validations: []*ParamValidation{{Min: floatPointer(1), Max: floatPointer(10)}}},
+			{Name: "p3", Type: ParamTypeList, Validations: []*ParamValidation{{AllowedValues: []string{"a", "b", "c"}}}},
+		},
+		expected: "('us-east-1',5,array['a','b'])",
+	},
+	"param failing regex validation": {
+		args: &QueryArgs{
+			ArgsList: []string{"'eu-west-1'"},
+		},
+		paramDefs: []*ParamDef{
+			{Name: "p1", Type: ParamTypeString, Validations: []*ParamValidation{{Regex: utils.ToStringPointer("^us-")}}},
+		},
+		expected: "ERROR",
+	},
+	"default failing max validation": {
+		args: &QueryArgs{},
+		paramDefs: []*ParamDef{
+			{Name: "p1", Type: ParamTypeNumber, Default: utils.ToStringPointer("20"), Validations: []*ParamValidation{{Max: floatPointer(10)}}},
+		},
+		expected: "ERROR",
+	},
+	"list param failing allowed values validation": {
+		args: &QueryArgs{
+			ArgsList: []string{"array['a','d']"},
+		},
+		paramDefs: []*ParamDef{
+			{Name: "p1", Type: ParamTypeList, Validations: []*ParamValidation{{AllowedValues: []string{"a", "b", "c"}}}},
+		},
+		expected: "ERROR",
+	},
+	"map param passing min validation": {
+		args: &QueryArgs{
+			ArgsList: []string{`'{"a":"1","b":"2"}'::jsonb`},
+		},
+		paramDefs: []*ParamDef{
+			{Name: "p1", Type: ParamTypeMap, Validations: []*ParamValidation{{Min: floatPointer(1), Max: floatPointer(2)}}},
+		},
+		expected: `('{"a":"1","b":"2"}'::jsonb)`,
+	},
+	"map param failing max validation": {
+		args: &QueryArgs{
+			ArgsList: []string{`'{"a":"1","b":"2"}'::jsonb`},
+		},
+		paramDefs: []*ParamDef{
+			{Name: "p1", Type: ParamTypeMap, Validations: []*ParamValidation{{Max: floatPointer(1)}}},
+		},
+		expected: "ERROR",
+	},
+}
+
+func floatPointer(f float64) *float64 {
+	return &f
 }
 
 func TestResolveAsString(t *testing.T) {
@@ -13,6 +13,8 @@ type Report struct {
 	FullName  string `cty:"name"`
 	ShortName string
 	Title     *string
+	// refresh interval in seconds
+	Refresh *int
 
 	Reports []*Report //`hcl:"report,block"`
 	Panels  []*Panel  //`hcl:"panel,block"`
@@ -161,6 +163,13 @@ func (r *Report) Diff(new *Report) *ReportTreeItemDiffs {
 	if typehelpers.SafeString(r.Title) != typehelpers.SafeString(new.Title) {
 		res.AddPropertyDiff("Title")
 	}
+	if r.Refresh == nil || new.Refresh == nil {
+		if !(r.Refresh == nil && new.Refresh == nil) {
+			res.AddPropertyDiff("Refresh")
+		}
+	} else if *r.Refresh != *new.Refresh {
+		res.AddPropertyDiff("Refresh")
+	}
 
 	res.populateChildDiffs(r, new)
 	return res
@@ -24,4 +24,21 @@ func (m *ResourceMetadata) SetMod(mod *Mod) {
 	m.ModFullName = mod.FullName
 }
 
+// return a copy of the metadata for a copy of the resource with the given name, belonging to the given mod
+func (m *ResourceMetadata) clone(resourceName string, mod *Mod) *ResourceMetadata {
+	res := *m
+	res.ResourceName = resourceName
+	res.ModName = ""
+	res.ModFullName = ""
+	res.SetMod(mod)
+	return &res
+}
+
 // TODO ADD PATH ltree
+
+func (m *ResourceMetadata) Equals(other *ResourceMetadata) bool {
+	if m == nil || other == nil {
+		return m == other
+	}
+	return *m == *other
+}
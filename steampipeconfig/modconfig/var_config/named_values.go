@@ -25,11 +25,11 @@ type Variable struct {
 	Default     cty.Value
 	Type        cty.Type
 	ParsingMode VariableParsingMode
-	//Validations []*VariableValidation
-	//Sensitive   bool
+	Validations []*VariableValidation
+	Sensitive   bool
 
 	DescriptionSet bool
-	//SensitiveSet   bool
+	SensitiveSet   bool
 
 	DeclRange hcl.Range
 }
@@ -97,11 +97,11 @@ func DecodeVariableBlock(block *hcl.Block, override bool) (*Variable, hcl.Diagno
 		v.ParsingMode = parseMode
 	}
 
-	//if attr, exists := content.Attributes["sensitive"]; exists {
-	//	valDiags := gohcl.DecodeExpression(attr.Expr, nil, &v.Sensitive)
-	//	diags = append(diags, valDiags...)
-	//	v.SensitiveSet = true
-	//}
+	if attr, exists := content.Attributes["sensitive"]; exists {
+		valDiags := gohcl.DecodeExpression(attr.Expr, nil, &v.Sensitive)
+		diags = append(diags, valDiags...)
+		v.SensitiveSet = true
+	}
 
 	if attr, exists := content.Attributes["default"]; exists {
 		val, valDiags := attr.Expr.Value(nil)
@@ -133,10 +133,10 @@ func DecodeVariableBlock(block *hcl.Block, override bool) (*Variable, hcl.Diagno
 	for _, block := range content.Blocks {
 		switch block.Type {
 
-		//case "validation":
-		//	vv, moreDiags := decodeVariableValidationBlock(v.Name, block, override)
-		//	diags = append(diags, moreDiags...)
-		//	v.Validations = append(v.Validations, vv)
+		case "validation":
+			vv, moreDiags := decodeVariableValidationBlock(v.Name, block, override)
+			diags = append(diags, moreDiags...)
+			v.Validations = append(v.Validations, vv)
 
 		default:
 			// The above cases should be exhaustive for all block types
@@ -383,7 +383,7 @@ func decodeVariableValidationBlock(varName string, block *hcl.Block, override bo
 				diags = diags.Append(&hcl.Diagnostic{
 					Severity: hcl.DiagError,
 					Summary:  errSummary,
-					Detail:   "The validation error message must be at least one full sentence starting with an uppercase letter and ending with a period or question mark.\n\nYour given message will be included as part of a larger Terraform error message, written as English prose. For broadly-shared modules we suggest using a similar writing style so that the overall result will be consistent.",
+					Detail:   "The validation error message must be at least one full sentence starting with an uppercase letter and ending with a period or question mark.\n\nYour given message will be included as part of a larger Steampipe error message, written as English prose. For broadly-shared modules we suggest using a similar writing style so that the overall result will be consistent.",
 					Subject:  attr.Expr.Range().Ptr(),
 				})
 			}
@@ -427,6 +427,11 @@ func looksLikeSentences(s string) bool {
 	return last == '.' || last == '?' || last == '!'
 }
 
+// VariableBlockSchema returns the schema of a variable block
+func VariableBlockSchema() *hcl.BodySchema {
+	return variableBlockSchema
+}
+
 var variableBlockSchema = &hcl.BodySchema{
 	Attributes: []hcl.AttributeSchema{
 		{
@@ -2,6 +2,9 @@ package var_config
 
 import (
 	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
 )
 
 func Test_looksLikeSentences(t *testing.T) {
@@ -31,3 +34,37 @@ func Test_looksLikeSentences(t *testing.T) {
 		})
 	}
 }
+
+func TestDecodeVariableBlockValidationAndSensitive(t *testing.T) {
+	src := `
+variable "region" {
+  type      = string
+  sensitive = true
+  validation {
+    condition     = can(regex("^us-", var.region))
+    error_message = "The region must be a US region."
+  }
+}
+`
+	file, diags := hclsyntax.ParseConfig([]byte(src), "test.sp", hcl.InitialPos)
+	if diags.HasErrors() {
+		t.Fatalf("failed to parse config: %s", diags.Error())
+	}
+	content, _ := file.Body.Content(&hcl.BodySchema{
+		Blocks: []hcl.BlockHeaderSchema{{Type: "variable", LabelNames: []string{"name"}}},
+	})
+
+	v, diags := DecodeVariableBlock(content.Blocks[0], false)
+	if diags.HasErrors() {
+		t.Fatalf("unexpected error decoding variable: %s", diags.Error())
+	}
+	if !v.Sensitive || !v.SensitiveSet {
+		t.Errorf("expected variable to be sensitive")
+	}
+	if len(v.Validations) != 1 {
+		t.Fatalf("expected 1 validation, got %d", len(v.Validations))
+	}
+	if v.Validations[0].ErrorMessage != "The region must be a US region." {
+		t.Errorf("unexpected error message: %s", v.Validations[0].ErrorMessage)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/terraform/tfdiags"
 	"github.com/turbot/steampipe/steampipeconfig/modconfig/var_config"
+	"github.com/turbot/steampipe/utils"
 	"github.com/zclconf/go-cty/cty"
 )
 
@@ -18,6 +19,8 @@ type Variable struct {
 	Default        cty.Value `column:"default_value,jsonb"`
 	Type           cty.Type  `column:"var_type,text"`
 	DescriptionSet bool
+	Sensitive      bool `column:"sensitive,boolean"`
+	Validations    []*var_config.VariableValidation
 
 	// set after value resolution `column:"value,jsonb"`
 	Value                      cty.Value `column:"value,jsonb"`
@@ -40,6 +43,8 @@ func NewVariable(v *var_config.Variable) *Variable {
 		Default:     v.Default,
 		Type:        v.Type,
 		ParsingMode: v.ParsingMode,
+		Sensitive:   v.Sensitive,
+		Validations: v.Validations,
 
 		DeclRange: v.DeclRange,
 	}
@@ -50,7 +55,22 @@ func (v *Variable) Equals(other *Variable) bool {
 		v.FullName == other.FullName &&
 		v.Description == other.Description &&
 		v.Default.RawEquals(other.Default) &&
-		v.Value.RawEquals(other.Value)
+		v.Value.RawEquals(other.Value) &&
+		v.Sensitive == other.Sensitive
+}
+
+// Redacted returns a copy of the variable with the value and default replaced by utils.SensitiveValueRedacted
+// if the variable is sensitive - otherwise the variable itself is returned
+func (v *Variable) Redacted() *Variable {
+	if !v.Sensitive {
+		return v
+	}
+	redacted := *v
+	redacted.Value = cty.StringVal(utils.SensitiveValueRedacted)
+	if v.Default != cty.NilVal {
+		redacted.Default = cty.StringVal(utils.SensitiveValueRedacted)
+	}
+	return &redacted
 }
 
 // Name implements HclResource, ResourceWithMetadata
@@ -0,0 +1,42 @@
+package modconfig
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+)
+
+// WorkspaceProfile is a struct representing a named profile, defined in the workspace config,
+// which sets the variable files, search path and connection config used when running against an environment
+type WorkspaceProfile struct {
+	Name string
+	// var files are resolved relative to the file containing the profile
+	VarFiles         []string
+	SearchPath       []string
+	SearchPathPrefix []string
+	// map of connection name to the connection config which overrides it
+	Connections map[string]*Connection
+	DeclRange   hcl.Range
+}
+
+func NewWorkspaceProfile(block *hcl.Block) *WorkspaceProfile {
+	return &WorkspaceProfile{
+		Name:        block.Labels[0],
+		Connections: make(map[string]*Connection),
+		DeclRange:   block.DefRange,
+	}
+}
+
+func (p *WorkspaceProfile) String() string {
+	var connectionNames []string
+	for name := range p.Connections {
+		connectionNames = append(connectionNames, name)
+	}
+	return fmt.Sprintf("Name: %s, VarFiles: %s, SearchPath: %s, SearchPathPrefix: %s, Connections: %s",
+		p.Name,
+		strings.Join(p.VarFiles, ","),
+		strings.Join(p.SearchPath, ","),
+		strings.Join(p.SearchPathPrefix, ","),
+		strings.Join(connectionNames, ","))
+}
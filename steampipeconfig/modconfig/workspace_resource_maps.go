@@ -11,6 +11,12 @@ type WorkspaceResourceMaps struct {
 	References map[string]*ResourceReference
 }
 
+// ResourceTypeReference is the resource type of a ResourceReference - references are not declared by a block
+const ResourceTypeReference = "reference"
+
+// ResourceTypes are the types of the resources held by WorkspaceResourceMaps
+var ResourceTypes = []string{BlockTypeMod, BlockTypeQuery, BlockTypeControl, BlockTypeBenchmark, BlockTypeVariable, ResourceTypeReference}
+
 func NewWorkspaceResourceMaps() *WorkspaceResourceMaps {
 	return &WorkspaceResourceMaps{
 		Mods:       make(map[string]*Mod),
@@ -21,6 +27,39 @@ func NewWorkspaceResourceMaps() *WorkspaceResourceMaps {
 		References: make(map[string]*ResourceReference),
 	}
 }
+
+// ResourceMap returns the resources of the given type (one of ResourceTypes), keyed by name
+func (m *WorkspaceResourceMaps) ResourceMap(resourceType string) map[string]ResourceWithMetadata {
+	res := make(map[string]ResourceWithMetadata)
+	switch resourceType {
+	case BlockTypeMod:
+		for name, mod := range m.Mods {
+			res[name] = mod
+		}
+	case BlockTypeQuery:
+		for name, query := range m.Queries {
+			res[name] = query
+		}
+	case BlockTypeControl:
+		for name, control := range m.Controls {
+			res[name] = control
+		}
+	case BlockTypeBenchmark:
+		for name, benchmark := range m.Benchmarks {
+			res[name] = benchmark
+		}
+	case BlockTypeVariable:
+		for name, variable := range m.Variables {
+			res[name] = variable
+		}
+	case ResourceTypeReference:
+		for name, reference := range m.References {
+			res[name] = reference
+		}
+	}
+	return res
+}
+
 func (m *WorkspaceResourceMaps) Equals(other *WorkspaceResourceMaps) bool {
 	for name, mod := range m.Mods {
 		if otherMod, ok := other.Mods[name]; !ok {
@@ -0,0 +1,142 @@
+package modconfig
+
+// WorkspaceResourceMapsDiff contains the names of the resources which differ between two WorkspaceResourceMaps
+// a resource is included if it has been added, updated or deleted, or if it references such a resource
+// NOTE: names are the keys of the resource maps, so a resource may be included under both its short and long name
+type WorkspaceResourceMapsDiff struct {
+	Prev    *WorkspaceResourceMaps
+	Current *WorkspaceResourceMaps
+
+	Mods       map[string]bool
+	Queries    map[string]bool
+	Controls   map[string]bool
+	Benchmarks map[string]bool
+	Variables  map[string]bool
+	References map[string]bool
+}
+
+// Diff returns the resources which have changed between m and other
+// as well as changes to the resources themselves, changes to their metadata (e.g. line numbers) are included
+func (m *WorkspaceResourceMaps) Diff(other *WorkspaceResourceMaps) *WorkspaceResourceMapsDiff {
+	diff := &WorkspaceResourceMapsDiff{
+		Prev:       m,
+		Current:    other,
+		Mods:       make(map[string]bool),
+		Queries:    make(map[string]bool),
+		Controls:   make(map[string]bool),
+		Benchmarks: make(map[string]bool),
+		Variables:  make(map[string]bool),
+		References: make(map[string]bool),
+	}
+
+	for _, resourceType := range ResourceTypes {
+		resources, otherResources := m.ResourceMap(resourceType), other.ResourceMap(resourceType)
+		changed := diff.ChangedNames(resourceType)
+		for name, resource := range resources {
+			if otherResource, ok := otherResources[name]; !ok || !resourcesEqual(resource, otherResource) {
+				changed[name] = true
+			}
+		}
+		for name := range otherResources {
+			if _, ok := resources[name]; !ok {
+				changed[name] = true
+			}
+		}
+	}
+
+	diff.addDependents()
+	return diff
+}
+
+// ChangedNames returns the names of the changed resources of the given type (one of ResourceTypes)
+func (d *WorkspaceResourceMapsDiff) ChangedNames(resourceType string) map[string]bool {
+	switch resourceType {
+	case BlockTypeMod:
+		return d.Mods
+	case BlockTypeQuery:
+		return d.Queries
+	case BlockTypeControl:
+		return d.Controls
+	case BlockTypeBenchmark:
+		return d.Benchmarks
+	case BlockTypeVariable:
+		return d.Variables
+	case ResourceTypeReference:
+		return d.References
+	}
+	return nil
+}
+
+// HasChanges returns whether any resources have changed
+func (d *WorkspaceResourceMapsDiff) HasChanges() bool {
+	return len(d.Mods)+len(d.Queries)+len(d.Controls)+len(d.Benchmarks)+len(d.Variables)+len(d.References) > 0
+}
+
+// add all resources which reference a changed resource - directly or indirectly
+// for example, if a query changes, a control which uses the query, and a benchmark containing the control are added
+func (d *WorkspaceResourceMapsDiff) addDependents() {
+	// a reference may have been removed in the current maps, so use the references from both maps
+	var references []*ResourceReference
+	for _, reference := range d.Prev.References {
+		references = append(references, reference)
+	}
+	for _, reference := range d.Current.References {
+		references = append(references, reference)
+	}
+
+	// keep iterating until no more dependents are found
+	for added := true; added; {
+		added = false
+		for _, reference := range references {
+			if !d.isChanged(reference.To) || d.isChanged(reference.From) {
+				continue
+			}
+			if changedNames := d.changedNamesForResource(reference.From); changedNames != nil {
+				changedNames[reference.From] = true
+				added = true
+			}
+		}
+	}
+}
+
+func (d *WorkspaceResourceMapsDiff) isChanged(name string) bool {
+	changedNames := d.changedNamesForResource(name)
+	return changedNames != nil && changedNames[name]
+}
+
+// return the map of changed names for the type of the given resource
+func (d *WorkspaceResourceMapsDiff) changedNamesForResource(name string) map[string]bool {
+	parsedName, err := ParseResourceName(name)
+	if err != nil {
+		return nil
+	}
+	switch parsedName.ItemType {
+	case BlockTypeMod, BlockTypeQuery, BlockTypeControl, BlockTypeBenchmark:
+		return d.ChangedNames(parsedName.ItemType)
+	case "var":
+		return d.Variables
+	}
+	return nil
+}
+
+// return whether two resources of the same type are equal, including their metadata
+func resourcesEqual(l, r ResourceWithMetadata) bool {
+	if !l.GetMetadata().Equals(r.GetMetadata()) {
+		return false
+	}
+	switch t := l.(type) {
+	case *Mod:
+		return t.Equals(r.(*Mod))
+	case *Query:
+		return t.Equals(r.(*Query))
+	case *Control:
+		return t.Equals(r.(*Control))
+	case *Benchmark:
+		return t.Equals(r.(*Benchmark))
+	case *Variable:
+		return t.Equals(r.(*Variable))
+	case *ResourceReference:
+		return t.Equals(r.(*ResourceReference))
+	}
+	return false
+}
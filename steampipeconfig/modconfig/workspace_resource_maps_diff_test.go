@@ -0,0 +1,77 @@
+package modconfig
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+type resourceMapsDiffTest struct {
+	prev     *WorkspaceResourceMaps
+	current  *WorkspaceResourceMaps
+	expected []string
+}
+
+var testCasesResourceMapsDiff = map[string]resourceMapsDiffTest{
+	"no changes": {
+		prev:     testResourceMaps("select 1", "query.q1"),
+		current:  testResourceMaps("select 1", "query.q1"),
+		expected: nil,
+	},
+	"query changed": {
+		prev:     testResourceMaps("select 1", ""),
+		current:  testResourceMaps("select 2", ""),
+		expected: []string{"query.q1"},
+	},
+	"query changed with dependent control and benchmark": {
+		prev:     testResourceMaps("select 1", "query.q1"),
+		current:  testResourceMaps("select 2", "query.q1"),
+		expected: []string{"benchmark.b1", "control.c1", "query.q1"},
+	},
+	"reference removed": {
+		prev:     testResourceMaps("select 1", "query.q1"),
+		current:  testResourceMaps("select 1", ""),
+		expected: []string{"ref"},
+	},
+}
+
+func TestWorkspaceResourceMapsDiff(t *testing.T) {
+	for name, test := range testCasesResourceMapsDiff {
+		diff := test.prev.Diff(test.current)
+
+		var changed []string
+		for _, names := range []map[string]bool{diff.Mods, diff.Queries, diff.Controls, diff.Benchmarks, diff.Variables} {
+			for n := range names {
+				changed = append(changed, n)
+			}
+		}
+		if len(diff.References) > 0 {
+			changed = append(changed, "ref")
+		}
+		sort.Strings(changed)
+
+		if !reflect.DeepEqual(changed, test.expected) {
+			t.Errorf("Test: '%s' FAILED : expected %v, got %v", name, test.expected, changed)
+		}
+		if diff.HasChanges() != (len(test.expected) > 0) {
+			t.Errorf("Test: '%s' FAILED : expected HasChanges to be %v", name, len(test.expected) > 0)
+		}
+	}
+}
+
+// build resource maps containing query q1 with the given sql, control c1 and benchmark b1 (containing c1)
+// if controlQueryRef is set, c1 references it
+func testResourceMaps(sql, controlQueryRef string) *WorkspaceResourceMaps {
+	m := NewWorkspaceResourceMaps()
+	m.Queries["query.q1"] = &Query{ShortName: "q1", FullName: "query.q1", SQL: &sql}
+	control := &Control{ShortName: "c1", FullName: "control.c1"}
+	benchmark := &Benchmark{ShortName: "b1", FullName: "benchmark.b1", ChildNameStrings: []string{"control.c1"}}
+	if controlQueryRef != "" {
+		control.References = append(control.References, &ResourceReference{To: controlQueryRef, From: "control.c1", BlockType: BlockTypeControl, BlockName: "c1", Attribute: "query"})
+	}
+	benchmark.References = append(benchmark.References, &ResourceReference{To: "control.c1", From: "benchmark.b1", BlockType: BlockTypeBenchmark, BlockName: "b1", Attribute: "children"})
+	m.Controls["control.c1"] = control
+	m.Benchmarks["benchmark.b1"] = benchmark
+	m.PopulateReferences()
+	return m
+}
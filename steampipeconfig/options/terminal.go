@@ -17,6 +17,7 @@ type Terminal struct {
 	SearchPath       *string `hcl:"search_path"`
 	SearchPathPrefix *string `hcl:"search_path_prefix"`
 	Watch            *bool   `hcl:"watch"`
+	WorkspaceHistory *bool   `hcl:"workspace_history"`
 }
 
 // ConfigMap :: create a config map to pass to viper
@@ -49,6 +50,9 @@ func (t *Terminal) ConfigMap() map[string]interface{} {
 	if t.Watch != nil {
 		res[constants.ArgWatch] = t.Watch
 	}
+	if t.WorkspaceHistory != nil {
+		res[constants.ArgWorkspaceHistory] = t.WorkspaceHistory
+	}
 	return res
 }
 
@@ -81,6 +85,9 @@ func (t *Terminal) Merge(otherOptions Options) {
 		if o.Watch != nil {
 			t.Watch = o.Watch
 		}
+		if o.WorkspaceHistory != nil {
+			t.WorkspaceHistory = o.WorkspaceHistory
+		}
 	}
 }
 
@@ -129,6 +136,11 @@ func (t *Terminal) String() string {
 	} else {
 		str = append(str, fmt.Sprintf("  Watch: %v", *t.Watch))
 	}
+	if t.WorkspaceHistory == nil {
+		str = append(str, "  WorkspaceHistory: nil")
+	} else {
+		str = append(str, fmt.Sprintf("  WorkspaceHistory: %v", *t.WorkspaceHistory))
+	}
 	return strings.Join(str, "\n")
 }
 
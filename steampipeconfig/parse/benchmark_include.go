@@ -0,0 +1,319 @@
+package parse
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/turbot/go-kit/helpers"
+	"github.com/turbot/steampipe/steampipeconfig/hclhelpers"
+	"github.com/turbot/steampipe/steampipeconfig/modconfig"
+)
+
+// resolveBenchmarkIncludes adds the benchmarks and controls of dependency mods which are included by the
+// benchmarks of the current mod
+// the included resources are copied into the current mod (with their overrides applied) and added as
+// children of the including benchmark, so they are part of the resource tree built by the mod
+func resolveBenchmarkIncludes(runCtx *RunContext) hcl.Diagnostics {
+	var diags hcl.Diagnostics
+	mod := runCtx.CurrentMod
+
+	// find the benchmarks which include other resources - sort so the copies are always added in the same order
+	var names []string
+	for name, benchmark := range mod.Benchmarks {
+		if benchmark.Include != nil {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		includer := newBenchmarkIncluder(mod.Benchmarks[name], runCtx)
+		diags = append(diags, includer.resolve()...)
+	}
+	return diags
+}
+
+// benchmarkIncluder copies the resources included by a benchmark
+type benchmarkIncluder struct {
+	benchmark *modconfig.Benchmark
+	runCtx    *RunContext
+	// map of qualified name of each included resource to its copy
+	copies map[string]modconfig.ModTreeItem
+	// the copies, in the order they were made
+	ordered []modconfig.HclResource
+	// qualified names of all resources in the included trees, including those which are excluded
+	found map[string]bool
+	// qualified names of the excluded resources
+	excluded map[string]bool
+}
+
+func newBenchmarkIncluder(benchmark *modconfig.Benchmark, runCtx *RunContext) *benchmarkIncluder {
+	return &benchmarkIncluder{
+		benchmark: benchmark,
+		runCtx:    runCtx,
+		copies:    make(map[string]modconfig.ModTreeItem),
+		found:     make(map[string]bool),
+		excluded:  make(map[string]bool),
+	}
+}
+
+func (i *benchmarkIncluder) resolve() hcl.Diagnostics {
+	b := i.benchmark
+	included, diags := i.resolveReferences(b.Include, modconfig.BlockTypeBenchmark, modconfig.BlockTypeControl)
+	if b.Exclude != nil {
+		excluded, moreDiags := i.resolveReferences(b.Exclude, modconfig.BlockTypeBenchmark, modconfig.BlockTypeControl)
+		diags = append(diags, moreDiags...)
+		for _, r := range excluded {
+			i.excluded[r.name] = true
+		}
+	}
+	if diags.HasErrors() {
+		return diags
+	}
+
+	// copy the included trees
+	var childNames []string
+	for _, r := range included {
+		if name := i.copyItem(r.item, r.mod); name != "" {
+			childNames = append(childNames, name)
+		}
+	}
+	for _, r := range sortedKeys(i.excluded) {
+		if !i.found[r] {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  fmt.Sprintf("%s excludes '%s' which is not in any of its included benchmarks", b.Name(), r),
+				Subject:  &b.Exclude.Range,
+			})
+		}
+	}
+
+	diags = append(diags, i.checkCopyNames()...)
+	for _, override := range b.Overrides {
+		diags = append(diags, i.applyOverride(override)...)
+	}
+	if diags.HasErrors() {
+		return diags
+	}
+
+	// add the copies to the mod, and the top level copies to the benchmark
+	for _, resource := range i.ordered {
+		diags = append(diags, i.runCtx.CurrentMod.AddResource(resource)...)
+	}
+	for _, name := range childNames {
+		b.AddChildName(name)
+	}
+	return diags
+}
+
+// copy the item (and, for a benchmark, its children) into the current mod, returning the name of the copy
+// returns an empty string if the item is excluded
+func (i *benchmarkIncluder) copyItem(item modconfig.ModTreeItem, mod *modconfig.Mod) string {
+	qualifiedName := fmt.Sprintf("%s.%s", mod.ShortName, item.Name())
+	i.found[qualifiedName] = true
+	if i.excluded[qualifiedName] {
+		return ""
+	}
+	// if the item is in more than one included tree, it is only copied once
+	if existing, ok := i.copies[qualifiedName]; ok {
+		return existing.Name()
+	}
+
+	switch t := item.(type) {
+	case *modconfig.Control:
+		c := t.Clone(i.copyName(t.ShortName), i.runCtx.CurrentMod)
+		i.copies[qualifiedName] = c
+		i.ordered = append(i.ordered, c)
+		return c.Name()
+	case *modconfig.Benchmark:
+		c := t.Clone(i.copyName(t.ShortName), i.runCtx.CurrentMod)
+		i.copies[qualifiedName] = c
+		i.ordered = append(i.ordered, c)
+		for _, child := range t.GetChildren() {
+			if name := i.copyItem(child, mod); name != "" {
+				c.AddChildName(name)
+			}
+		}
+		return c.Name()
+	}
+	return ""
+}
+
+// the short name of a copy is prefixed with the short name of the including benchmark
+func (i *benchmarkIncluder) copyName(shortName string) string {
+	return fmt.Sprintf("%s_%s", i.benchmark.ShortName, shortName)
+}
+
+// check the names of the copies do not collide with each other or with the existing resources of the mod
+func (i *benchmarkIncluder) checkCopyNames() hcl.Diagnostics {
+	var diags hcl.Diagnostics
+	mod := i.runCtx.CurrentMod
+	// map of copy name to the qualified name of the resource it is a copy of
+	sources := make(map[string]string)
+	var qualifiedNames []string
+	for qualifiedName := range i.copies {
+		qualifiedNames = append(qualifiedNames, qualifiedName)
+	}
+	sort.Strings(qualifiedNames)
+
+	for _, qualifiedName := range qualifiedNames {
+		c := i.copies[qualifiedName]
+		name := c.Name()
+		if source, ok := sources[name]; ok {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  fmt.Sprintf("%s includes both '%s' and '%s', which would both be added as '%s'", i.benchmark.Name(), source, qualifiedName, name),
+				Subject:  &i.benchmark.Include.Range,
+			})
+			continue
+		}
+		sources[name] = qualifiedName
+
+		var exists bool
+		switch c.(type) {
+		case *modconfig.Control:
+			_, exists = mod.Controls[name]
+		case *modconfig.Benchmark:
+			_, exists = mod.Benchmarks[name]
+		}
+		if exists {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  fmt.Sprintf("%s includes '%s', which would be added as '%s' - the mod already has a resource with this name", i.benchmark.Name(), qualifiedName, name),
+				Subject:  &i.benchmark.Include.Range,
+			})
+		}
+	}
+	return diags
+}
+
+func (i *benchmarkIncluder) applyOverride(override *modconfig.BenchmarkOverride) hcl.Diagnostics {
+	var diags hcl.Diagnostics
+	var controls []*modconfig.Control
+	if override.Controls == nil {
+		// the override applies to all included controls
+		for _, resource := range i.ordered {
+			if control, ok := resource.(*modconfig.Control); ok {
+				controls = append(controls, control)
+			}
+		}
+	} else {
+		refs, moreDiags := i.resolveReferences(override.Controls, modconfig.BlockTypeControl)
+		diags = append(diags, moreDiags...)
+		for _, r := range refs {
+			control, ok := i.copies[r.name].(*modconfig.Control)
+			if !ok {
+				diags = append(diags, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  fmt.Sprintf("%s overrides '%s' which is not included by the benchmark", i.benchmark.Name(), r.name),
+					Subject:  &override.Controls.Range,
+				})
+				continue
+			}
+			controls = append(controls, control)
+		}
+	}
+
+	var args *modconfig.QueryArgs
+	if override.Args != nil {
+		var moreDiags hcl.Diagnostics
+		args, moreDiags = decodeControlArgs(override.Args, i.runCtx.EvalCtx, i.benchmark.Name())
+		diags = append(diags, moreDiags...)
+	}
+	if diags.HasErrors() {
+		return diags
+	}
+
+	for _, control := range controls {
+		override.Apply(control)
+		if args != nil {
+			override.ApplyArgs(control, args)
+		}
+	}
+	return diags
+}
+
+// a resource of a dependency mod referenced by a benchmark
+type dependencyResourceRef struct {
+	// qualified name of the resource
+	name string
+	item modconfig.ModTreeItem
+	mod  *modconfig.Mod
+}
+
+// resolve a list of references to resources of dependency mods, e.g. [aws_compliance.benchmark.cis_v140]
+// the resources must be of one of the given types
+func (i *benchmarkIncluder) resolveReferences(attr *hcl.Attribute, blockTypes ...string) ([]*dependencyResourceRef, hcl.Diagnostics) {
+	var res []*dependencyResourceRef
+	exprs, diags := hcl.ExprList(attr.Expr)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+	for _, expr := range exprs {
+		r, err := i.resolveReference(expr, blockTypes)
+		if err != nil {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  fmt.Sprintf("%s has an invalid '%s' property", i.benchmark.Name(), attr.Name),
+				Detail:   err.Error(),
+				Subject:  expr.Range().Ptr(),
+			})
+			continue
+		}
+		res = append(res, r)
+	}
+	return res, diags
+}
+
+func (i *benchmarkIncluder) resolveReference(expr hcl.Expression, blockTypes []string) (*dependencyResourceRef, error) {
+	traversal, diags := hcl.AbsTraversalForExpr(expr)
+	if diags.HasErrors() {
+		return nil, fmt.Errorf("expected a reference to a %s of a dependency mod", blockTypes[0])
+	}
+	name := hclhelpers.TraversalAsString(traversal)
+	parsedName, err := modconfig.ParseResourceName(name)
+	if err != nil || parsedName.Mod == "" || !helpers.StringSliceContains(blockTypes, parsedName.ItemType) {
+		return nil, fmt.Errorf("'%s' is not a reference to a %s of a dependency mod, e.g. my_dependency.%s.my_%s", name, blockTypes[0], blockTypes[0], blockTypes[0])
+	}
+
+	mod := i.dependencyMod(parsedName.Mod)
+	if mod == nil {
+		return nil, fmt.Errorf("'%s' is not a dependency of mod '%s'", parsedName.Mod, i.runCtx.CurrentMod.ShortName)
+	}
+	resourceName := modconfig.BuildModResourceName(parsedName.ItemType, parsedName.Name)
+	var item modconfig.ModTreeItem
+	switch parsedName.ItemType {
+	case modconfig.BlockTypeBenchmark:
+		if b, ok := mod.Benchmarks[resourceName]; ok {
+			item = b
+		}
+	case modconfig.BlockTypeControl:
+		if c, ok := mod.Controls[resourceName]; ok {
+			item = c
+		}
+	}
+	if item == nil {
+		return nil, fmt.Errorf("mod '%s' has no %s '%s'", parsedName.Mod, parsedName.ItemType, parsedName.Name)
+	}
+	return &dependencyResourceRef{name: name, item: item, mod: mod}, nil
+}
+
+// return the loaded dependency mod with the given short name
+func (i *benchmarkIncluder) dependencyMod(shortName string) *modconfig.Mod {
+	for _, mod := range i.runCtx.LoadedDependencyMods {
+		if mod.ShortName == shortName {
+			return mod
+		}
+	}
+	return nil
+}
+
+func sortedKeys(m map[string]bool) []string {
+	var res []string
+	for k := range m {
+		res = append(res, k)
+	}
+	sort.Strings(res)
+	return res
+}
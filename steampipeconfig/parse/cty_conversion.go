@@ -79,6 +79,23 @@ func ctyToPostgresString(v cty.Value) (valStr string, err error) {
 	return valStr, err
 }
 
+// convert a cty object (or map) into a map of strings - all values must be convertible to strings
+func ctyObjectToMapOfStrings(val cty.Value) (map[string]string, error) {
+	ty := val.Type()
+	if val.IsNull() || !(ty.IsObjectType() || ty.IsMapType()) {
+		return nil, fmt.Errorf("value is not an object")
+	}
+	res := make(map[string]string)
+	for key, v := range val.AsValueMap() {
+		var valStr string
+		if err := gocty.FromCtyValue(v, &valStr); err != nil {
+			return nil, fmt.Errorf("invalid value for '%s': %v", key, err)
+		}
+		res[key] = valStr
+	}
+	return res, nil
+}
+
 func ctyTupleToArrayOfPgStrings(val cty.Value) ([]string, error) {
 	var res []string
 	it := val.ElementIterator()
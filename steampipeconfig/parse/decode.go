@@ -2,14 +2,20 @@ package parse
 
 import (
 	"fmt"
+	"regexp"
+	"sort"
+	"strings"
 
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/gohcl"
 	"github.com/hashicorp/hcl/v2/hclsyntax"
 	"github.com/turbot/go-kit/helpers"
+	"github.com/turbot/steampipe/constants"
+	"github.com/turbot/steampipe/steampipeconfig/hclhelpers"
 	"github.com/turbot/steampipe/steampipeconfig/modconfig"
 	"github.com/turbot/steampipe/steampipeconfig/modconfig/var_config"
 	"github.com/turbot/steampipe/utils"
+	"github.com/zclconf/go-cty/cty"
 )
 
 // A consistent detail message for all "not a valid identifier" diagnostics.
@@ -94,6 +100,12 @@ func decode(runCtx *RunContext) hcl.Diagnostics {
 			if moreDiags.HasErrors() {
 				diags = append(diags, moreDiags...)
 			}
+		case modconfig.BlockTypeTest:
+			test, res := decodeControlTest(block, runCtx)
+			moreDiags = handleDecodeResult(test, res, block, runCtx)
+			if moreDiags.HasErrors() {
+				diags = append(diags, moreDiags...)
+			}
 		default:
 			// all other blocks are treated the same:
 			resource, res := decodeResource(block, runCtx)
@@ -303,10 +315,94 @@ func decodeParam(block *hcl.Block, runCtx *RunContext, parentName string) (*modc
 			})
 		}
 	}
+	if attr, exists := content.Attributes["type"]; exists {
+		def.Type, diags = decodeParamType(attr, parentName, diags)
+	}
+	for _, block := range content.Blocks {
+		if block.Type == modconfig.BlockTypeValidation {
+			validation, moreDiags := decodeParamValidation(block, runCtx)
+			// the elements of a map value are not validated, so only min and max may be used
+			if def.Type == modconfig.ParamTypeMap && !moreDiags.HasErrors() && (validation.Regex != nil || len(validation.AllowedValues) > 0) {
+				moreDiags = append(moreDiags, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  fmt.Sprintf("%s has invalid parameter validation", parentName),
+					Detail:   fmt.Sprintf("parameter '%s' is a map - only 'min' and 'max' may be used to validate a map, giving the number of keys", def.Name),
+					Subject:  &block.DefRange,
+				})
+			}
+			diags = append(diags, moreDiags...)
+			if !moreDiags.HasErrors() {
+				def.Validations = append(def.Validations, validation)
+			}
+		}
+	}
+	// check the default is valid for the param type and validation rules
+	if def.Default != nil && !diags.HasErrors() {
+		diags = append(diags, def.ValidateValue(*def.Default)...)
+	}
 	return def, diags
 
 }
 
+// the param type may be given either as a keyword, e.g. type = number, or as a string, e.g. type = "number"
+func decodeParamType(attr *hcl.Attribute, parentName string, diags hcl.Diagnostics) (string, hcl.Diagnostics) {
+	paramType := hcl.ExprAsKeyword(attr.Expr)
+	if paramType == "" {
+		valDiags := gohcl.DecodeExpression(attr.Expr, nil, &paramType)
+		diags = append(diags, valDiags...)
+		if valDiags.HasErrors() {
+			return "", diags
+		}
+	}
+	if !modconfig.IsValidParamType(paramType) {
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  fmt.Sprintf("%s has invalid parameter type", parentName),
+			Detail:   fmt.Sprintf("'%s' is not a valid parameter type - must be one of string, number, bool, list or map", paramType),
+			Subject:  &attr.Range,
+		})
+	}
+	return paramType, diags
+}
+
+func decodeParamValidation(block *hcl.Block, runCtx *RunContext) (*modconfig.ParamValidation, hcl.Diagnostics) {
+	validation := modconfig.NewParamValidation(block)
+
+	content, diags := block.Body.Content(ParamValidationBlockSchema)
+
+	if attr, exists := content.Attributes["allowed_values"]; exists {
+		valDiags := gohcl.DecodeExpression(attr.Expr, runCtx.EvalCtx, &validation.AllowedValues)
+		diags = append(diags, valDiags...)
+	}
+	if attr, exists := content.Attributes["regex"]; exists {
+		valDiags := gohcl.DecodeExpression(attr.Expr, runCtx.EvalCtx, &validation.Regex)
+		diags = append(diags, valDiags...)
+		if validation.Regex != nil {
+			if err := validation.CompileRegex(); err != nil {
+				diags = append(diags, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Invalid validation regex",
+					Detail:   err.Error(),
+					Subject:  &attr.Range,
+				})
+			}
+		}
+	}
+	if attr, exists := content.Attributes["min"]; exists {
+		valDiags := gohcl.DecodeExpression(attr.Expr, runCtx.EvalCtx, &validation.Min)
+		diags = append(diags, valDiags...)
+	}
+	if attr, exists := content.Attributes["max"]; exists {
+		valDiags := gohcl.DecodeExpression(attr.Expr, runCtx.EvalCtx, &validation.Max)
+		diags = append(diags, valDiags...)
+	}
+	if attr, exists := content.Attributes["error_message"]; exists {
+		valDiags := gohcl.DecodeExpression(attr.Expr, runCtx.EvalCtx, &validation.ErrorMessage)
+		diags = append(diags, valDiags...)
+	}
+	return validation, diags
+}
+
 func decodeControl(block *hcl.Block, runCtx *RunContext) (*modconfig.Control, *decodeResult) {
 	res := &decodeResult{}
 
@@ -450,6 +546,258 @@ func decodeControlArgs(attr *hcl.Attribute, evalCtx *hcl.EvalContext, controlNam
 	return params, diags
 }
 
+func decodeControlTest(block *hcl.Block, runCtx *RunContext) (*modconfig.ControlTest, *decodeResult) {
+	res := &decodeResult{}
+
+	t := modconfig.NewControlTest(block)
+
+	content, diags := block.Body.Content(TestBlockSchema)
+
+	diags = append(diags, decodeProperty(content, "title", &t.Title, runCtx)...)
+	diags = append(diags, decodeProperty(content, "description", &t.Description, runCtx)...)
+
+	if attr, exists := content.Attributes["control"]; exists {
+		var moreDiags hcl.Diagnostics
+		t.ControlName, moreDiags = decodeTestControl(attr, runCtx, t.FullName)
+		diags = append(diags, moreDiags...)
+	}
+	if attr, exists := content.Attributes["expect"]; exists {
+		var moreDiags hcl.Diagnostics
+		t.Expect, moreDiags = decodeTestExpectations(attr, runCtx, t.FullName)
+		diags = append(diags, moreDiags...)
+	}
+
+	fixtureTables := make(map[string]bool)
+	for _, block := range content.Blocks {
+		if block.Type != modconfig.BlockTypeFixture {
+			continue
+		}
+		fixture, moreDiags := decodeTestFixture(block, runCtx, t.FullName)
+		diags = append(diags, moreDiags...)
+		if moreDiags.HasErrors() {
+			continue
+		}
+		if fixtureTables[fixture.Table] {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  fmt.Sprintf("%s defines more than one fixture for table '%s'", t.FullName, fixture.Table),
+				Subject:  &block.DefRange,
+			})
+			continue
+		}
+		fixtureTables[fixture.Table] = true
+		t.Fixtures = append(t.Fixtures, fixture)
+	}
+
+	// handle any resulting diags, which may specify dependencies
+	res.handleDecodeDiags(diags)
+
+	// call post-decode hook
+	if res.Success() {
+		if diags := t.OnDecoded(block); diags.HasErrors() {
+			res.addDiags(diags)
+		}
+	}
+	return t, res
+}
+
+// decode the 'control' attribute of a test, returning the name of the control
+func decodeTestControl(attr *hcl.Attribute, runCtx *RunContext, testName string) (string, hcl.Diagnostics) {
+	// evaluate the reference - if the control has not been decoded yet, this returns a dependency error
+	var control modconfig.NamedItem
+	diags := gohcl.DecodeExpression(attr.Expr, runCtx.EvalCtx, &control)
+	if diags.HasErrors() {
+		return "", diags
+	}
+
+	// use the traversal rather than the evaluated name, so that controls in dependency mods are qualified by mod
+	traversal, diags := hcl.AbsTraversalForExpr(attr.Expr)
+	if !diags.HasErrors() {
+		name := hclhelpers.TraversalAsString(traversal)
+		if parsedName, err := modconfig.ParseResourceName(name); err == nil && parsedName.ItemType == modconfig.BlockTypeControl {
+			return name, nil
+		}
+	}
+	return "", hcl.Diagnostics{&hcl.Diagnostic{
+		Severity: hcl.DiagError,
+		Summary:  fmt.Sprintf("%s has an invalid 'control' property", testName),
+		Detail:   "'control' must be a reference to a control, e.g. control.my_control",
+		Subject:  &attr.Range,
+	}}
+}
+
+// decode the 'expect' attribute of a test - a list of objects with 'resource', 'status' and (optionally) 'reason'
+func decodeTestExpectations(attr *hcl.Attribute, runCtx *RunContext, testName string) ([]*modconfig.TestExpectation, hcl.Diagnostics) {
+	v, diags := attr.Expr.Value(runCtx.EvalCtx)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+	invalidDiag := func(detail string) hcl.Diagnostics {
+		return hcl.Diagnostics{&hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  fmt.Sprintf("%s has an invalid 'expect' property", testName),
+			Detail:   detail,
+			Subject:  &attr.Range,
+		}}
+	}
+
+	ty := v.Type()
+	if v.IsNull() || !(ty.IsTupleType() || ty.IsListType()) {
+		return nil, invalidDiag("'expect' must be a list of expected result rows")
+	}
+
+	// an empty list is valid - it asserts the control returns no rows
+	expectations := []*modconfig.TestExpectation{}
+	for it := v.ElementIterator(); it.Next(); {
+		_, row := it.Element()
+		values, err := ctyObjectToMapOfStrings(row)
+		if err != nil {
+			return nil, invalidDiag(fmt.Sprintf("each expected row must be an object with string values: %s", err.Error()))
+		}
+		expectation := &modconfig.TestExpectation{
+			Resource: values["resource"],
+			Status:   values["status"],
+		}
+		for k, v := range values {
+			switch k {
+			case "resource", "status":
+			case "reason":
+				expectation.Reason = utils.ToStringPointer(v)
+			default:
+				return nil, invalidDiag(fmt.Sprintf("unexpected key '%s' - expected rows may only set resource, status and reason", k))
+			}
+		}
+		if expectation.Resource == "" || expectation.Status == "" {
+			return nil, invalidDiag("each expected row must set both 'resource' and 'status'")
+		}
+		if !helpers.StringSliceContains(constants.ControlStatuses, expectation.Status) {
+			return nil, invalidDiag(fmt.Sprintf("invalid status '%s' - must be one of %s", expectation.Status, strings.Join(constants.ControlStatuses, ", ")))
+		}
+		expectations = append(expectations, expectation)
+	}
+	return expectations, nil
+}
+
+// decode a test 'fixture' block
+// the column types are inferred from the row values, unless specified in the 'columns' attribute
+func decodeTestFixture(block *hcl.Block, runCtx *RunContext, testName string) (*modconfig.TestFixture, hcl.Diagnostics) {
+	fixture := &modconfig.TestFixture{
+		Table:     block.Labels[0],
+		Columns:   make(map[string]string),
+		DeclRange: block.DefRange,
+	}
+
+	content, diags := block.Body.Content(TestFixtureBlockSchema)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	// the fixture table would shadow the introspection table, which steampipe itself reads
+	if helpers.StringSliceContains(constants.IntrospectionTableNames(), fixture.Table) {
+		return nil, hcl.Diagnostics{&hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  fmt.Sprintf("%s has a fixture for the introspection table '%s'", testName, fixture.Table),
+			Detail:   "Fixtures may not replace the steampipe introspection tables.",
+			Subject:  &block.DefRange,
+		}}
+	}
+
+	if attr, exists := content.Attributes["columns"]; exists {
+		diags = append(diags, gohcl.DecodeExpression(attr.Expr, runCtx.EvalCtx, &fixture.Columns)...)
+		if diags.HasErrors() {
+			return nil, diags
+		}
+		// the column types are used in the DDL of the fixture table, so must be plain type names
+		for _, name := range sortedColumnNames(fixture.Columns) {
+			if columnType := fixture.Columns[name]; !fixtureColumnTypeRegex.MatchString(columnType) {
+				diags = append(diags, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  fmt.Sprintf("%s has an invalid type '%s' for column '%s' of the fixture for table '%s'", testName, columnType, name, fixture.Table),
+					Detail:   "A column type must be a postgres type name, e.g. text, numeric(10,2), timestamp with time zone or text[].",
+					Subject:  &attr.Range,
+				})
+			}
+		}
+		if diags.HasErrors() {
+			return nil, diags
+		}
+	}
+
+	attr := content.Attributes["rows"]
+	v, diags := attr.Expr.Value(runCtx.EvalCtx)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+	invalidDiag := func(detail string) hcl.Diagnostics {
+		return hcl.Diagnostics{&hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  fmt.Sprintf("%s has an invalid fixture for table '%s'", testName, fixture.Table),
+			Detail:   detail,
+			Subject:  &attr.Range,
+		}}
+	}
+	ty := v.Type()
+	if v.IsNull() || !(ty.IsTupleType() || ty.IsListType()) {
+		return nil, invalidDiag("'rows' must be a list of objects")
+	}
+
+	columnNames := make(map[string]bool)
+	for name := range fixture.Columns {
+		columnNames[name] = true
+	}
+	for it := v.ElementIterator(); it.Next(); {
+		_, row := it.Element()
+		if row.IsNull() || !(row.Type().IsObjectType() || row.Type().IsMapType()) {
+			return nil, invalidDiag("each row must be an object of column values")
+		}
+		values := row.AsValueMap()
+		for name, value := range values {
+			columnNames[name] = true
+			if _, ok := fixture.Columns[name]; !ok && !value.IsNull() {
+				fixture.Columns[name] = fixtureColumnType(value)
+			}
+		}
+		fixture.Rows = append(fixture.Rows, values)
+	}
+
+	for name := range columnNames {
+		// a column with only null values
+		if _, ok := fixture.Columns[name]; !ok {
+			fixture.Columns[name] = "text"
+		}
+		fixture.ColumnNames = append(fixture.ColumnNames, name)
+	}
+	sort.Strings(fixture.ColumnNames)
+
+	return fixture, nil
+}
+
+// matches a postgres type name, with optional type modifiers and array bounds, e.g. varchar(20) or text[]
+var fixtureColumnTypeRegex = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*( [a-zA-Z_][a-zA-Z0-9_]*)*(\(\d+(, ?\d+)?\))?(\[\])*$`)
+
+func sortedColumnNames(columns map[string]string) []string {
+	var res []string
+	for name := range columns {
+		res = append(res, name)
+	}
+	sort.Strings(res)
+	return res
+}
+
+// infer the postgres column type for a fixture value
+func fixtureColumnType(v cty.Value) string {
+	switch v.Type() {
+	case cty.String:
+		return "text"
+	case cty.Number:
+		return "numeric"
+	case cty.Bool:
+		return "boolean"
+	default:
+		return "jsonb"
+	}
+}
+
 func decodePanel(block *hcl.Block, runCtx *RunContext) (*modconfig.Panel, *decodeResult) {
 	res := &decodeResult{}
 	content, diags := block.Body.Content(PanelBlockSchema)
@@ -479,6 +827,9 @@ func decodePanel(block *hcl.Block, runCtx *RunContext) (*modconfig.Panel, *decod
 	diags = decodeProperty(content, "sql", &panel.SQL, runCtx)
 	res.handleDecodeDiags(diags)
 
+	diags = decodeRefresh(content, &panel.Refresh, runCtx)
+	res.handleDecodeDiags(diags)
+
 	diags = decodeReportBlocks(panel, content, runCtx)
 	res.handleDecodeDiags(diags)
 
@@ -495,6 +846,9 @@ func decodeReport(block *hcl.Block, runCtx *RunContext) (*modconfig.Report, *dec
 	diags = decodeProperty(content, "title", &report.Title, runCtx)
 	res.handleDecodeDiags(diags)
 
+	diags = decodeRefresh(content, &report.Refresh, runCtx)
+	res.handleDecodeDiags(diags)
+
 	diags = decodeReportBlocks(report, content, runCtx)
 	res.handleDecodeDiags(diags)
 
@@ -533,6 +887,23 @@ func decodeProperty(content *hcl.BodyContent, property string, dest interface{},
 	return diags
 }
 
+// decode the 'refresh' property, validating it is a positive number of seconds
+func decodeRefresh(content *hcl.BodyContent, dest **int, runCtx *RunContext) hcl.Diagnostics {
+	diags := decodeProperty(content, "refresh", dest, runCtx)
+	if diags.HasErrors() || *dest == nil {
+		return diags
+	}
+	if **dest <= 0 {
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Invalid refresh interval",
+			Detail:   "'refresh' must be a positive number of seconds",
+			Subject:  &content.Attributes["refresh"].Range,
+		})
+	}
+	return diags
+}
+
 // handleDecodeResult
 // if decode was successful:
 // - generate and set resource metadata
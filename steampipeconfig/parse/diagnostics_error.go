@@ -0,0 +1,47 @@
+package parse
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/turbot/steampipe-plugin-sdk/plugin"
+	"github.com/turbot/steampipe/steampipeconfig/hclhelpers"
+)
+
+// DiagnosticsError is an error resulting from hcl diagnostics
+// the diagnostics are retained so calling code can report them individually (e.g. with their source ranges)
+type DiagnosticsError struct {
+	Diags   hcl.Diagnostics
+	message string
+}
+
+func newDiagnosticsError(prefix string, diags hcl.Diagnostics) error {
+	return &DiagnosticsError{
+		Diags:   diags,
+		message: plugin.DiagsToError(prefix, diags).Error(),
+	}
+}
+
+func (e *DiagnosticsError) Error() string {
+	return e.message
+}
+
+// build a diagnostic for each dependency of the unresolved blocks
+func unresolvedDependencyDiagnostics(runCtx *RunContext) hcl.Diagnostics {
+	var diags hcl.Diagnostics
+	for _, block := range runCtx.UnresolvedBlocks {
+		for _, dep := range block.Dependencies {
+			depRange := dep.Range
+			for _, traversal := range dep.Traversals {
+				name := hclhelpers.TraversalAsString(traversal)
+				diags = append(diags, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  fmt.Sprintf("Failed to resolve %s", name),
+					Detail:   fmt.Sprintf("%s depends on %s, which could not be resolved.", block.Name, name),
+					Subject:  &depRange,
+				})
+			}
+		}
+	}
+	return diags
+}
@@ -0,0 +1,73 @@
+package parse
+
+import (
+	"bytes"
+	"sync"
+
+	"github.com/hashicorp/hcl/v2"
+)
+
+// ParsedFileCache is a cache of parsed hcl files, keyed by file path
+// when a mod is reloaded, only the files whose contents have changed are re-parsed
+// NOTE: this caches the hcl syntax only - the blocks of every file are decoded each time the mod is loaded
+type ParsedFileCache struct {
+	files map[string]*parsedFile
+	mut   sync.Mutex
+}
+
+type parsedFile struct {
+	data []byte
+	file *hcl.File
+	// has the file been used since the cache was last pruned
+	used bool
+}
+
+func NewParsedFileCache() *ParsedFileCache {
+	return &ParsedFileCache{files: make(map[string]*parsedFile)}
+}
+
+// return the cached file for the given path, if the file data has not changed since it was parsed
+// NOTE: a nil cache is valid and never returns a file
+func (c *ParsedFileCache) get(path string, data []byte) (*hcl.File, bool) {
+	if c == nil {
+		return nil, false
+	}
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	cached, ok := c.files[path]
+	if !ok || !bytes.Equal(cached.data, data) {
+		return nil, false
+	}
+	cached.used = true
+	return cached.file, true
+}
+
+// add a successfully parsed file to the cache
+func (c *ParsedFileCache) set(path string, data []byte, file *hcl.File) {
+	if c == nil {
+		return
+	}
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	c.files[path] = &parsedFile{data: data, file: file, used: true}
+}
+
+// Prune removes all files which have not been used since the last prune
+// this is called after reloading a mod, so that deleted files are not retained
+func (c *ParsedFileCache) Prune() {
+	if c == nil {
+		return
+	}
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	for path, cached := range c.files {
+		if !cached.used {
+			delete(c.files, path)
+			continue
+		}
+		cached.used = false
+	}
+}
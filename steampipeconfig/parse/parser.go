@@ -40,10 +40,20 @@ func LoadFileData(paths ...string) (map[string][]byte, hcl.Diagnostics) {
 
 // ParseHclFiles parses hcl file data and returns the hcl body object
 func ParseHclFiles(fileData map[string][]byte) (hcl.Body, hcl.Diagnostics) {
+	return parseHclFiles(fileData, nil)
+}
+
+// parse hcl file data, using the cache (if provided) to avoid re-parsing files which have not changed
+func parseHclFiles(fileData map[string][]byte, cache *ParsedFileCache) (hcl.Body, hcl.Diagnostics) {
 	var parsedConfigFiles []*hcl.File
 	var diags hcl.Diagnostics
 	parser := hclparse.NewParser()
 	for configPath, data := range fileData {
+		if file, ok := cache.get(configPath, data); ok {
+			parsedConfigFiles = append(parsedConfigFiles, file)
+			continue
+		}
+
 		var file *hcl.File
 		var moreDiags hcl.Diagnostics
 		ext := filepath.Ext(configPath)
@@ -59,6 +69,7 @@ func ParseHclFiles(fileData map[string][]byte) (hcl.Body, hcl.Diagnostics) {
 			diags = append(diags, moreDiags...)
 			continue
 		}
+		cache.set(configPath, data, file)
 		parsedConfigFiles = append(parsedConfigFiles, file)
 	}
 
@@ -91,13 +102,13 @@ func ParseModDefinition(modPath string) (*modconfig.Mod, error) {
 
 	body, diags := ParseHclFiles(fileData)
 	if diags.HasErrors() {
-		return nil, plugin.DiagsToError("Failed to load all mod source files", diags)
+		return nil, newDiagnosticsError("Failed to load all mod source files", diags)
 	}
 
 	content, moreDiags := body.Content(ModBlockSchema)
 	if moreDiags.HasErrors() {
 		diags = append(diags, moreDiags...)
-		return nil, plugin.DiagsToError("Failed to load mod", diags)
+		return nil, newDiagnosticsError("Failed to load mod", diags)
 	}
 
 	// build an eval context containing functions
@@ -110,7 +121,7 @@ func ParseModDefinition(modPath string) (*modconfig.Mod, error) {
 			mod := modconfig.NewMod(block.Labels[0], modPath, block.DefRange)
 			diags := gohcl.DecodeBody(block.Body, evalCtx, mod)
 			if diags.HasErrors() {
-				return nil, plugin.DiagsToError("Failed to decode mod hcl file", diags)
+				return nil, newDiagnosticsError("Failed to decode mod hcl file", diags)
 			}
 			// call decode callback
 			if err := mod.OnDecoded(block); err != nil {
@@ -126,15 +137,15 @@ func ParseModDefinition(modPath string) (*modconfig.Mod, error) {
 // ParseMod parses all source hcl files for the mod path and associated resources, and returns the mod object
 // NOTE: the mod definition has already been parsed (or a default created) and is in opts.RunCtx.RootMod
 func ParseMod(modPath string, fileData map[string][]byte, pseudoResources []modconfig.MappableResource, runCtx *RunContext) (*modconfig.Mod, error) {
-	body, diags := ParseHclFiles(fileData)
+	body, diags := parseHclFiles(fileData, runCtx.ParsedFileCache)
 	if diags.HasErrors() {
-		return nil, plugin.DiagsToError("Failed to load all mod source files", diags)
+		return nil, newDiagnosticsError("Failed to load all mod source files", diags)
 	}
 
 	content, moreDiags := body.Content(ModBlockSchema)
 	if moreDiags.HasErrors() {
 		diags = append(diags, moreDiags...)
-		return nil, plugin.DiagsToError("Failed to load mod", diags)
+		return nil, newDiagnosticsError("Failed to load mod", diags)
 	}
 
 	mod := runCtx.CurrentMod
@@ -162,22 +173,32 @@ func ParseMod(modPath string, fileData map[string][]byte, pseudoResources []modc
 	// (if there are no dependencies, this is all that is needed)
 	diags = decode(runCtx)
 	if diags.HasErrors() {
-		return nil, plugin.DiagsToError("Failed to decode all mod hcl files", diags)
+		return nil, newDiagnosticsError("Failed to decode all mod hcl files", diags)
 	}
 
 	// if eval is not complete, there must be dependencies - run again in dependency order
 	if !runCtx.EvalComplete() {
 		diags = decode(runCtx)
 		if diags.HasErrors() {
-			return nil, plugin.DiagsToError("Failed to parse all mod hcl files", diags)
+			return nil, newDiagnosticsError("Failed to parse all mod hcl files", diags)
 		}
 
 		// we failed to resolve dependencies
 		if !runCtx.EvalComplete() {
-			return nil, fmt.Errorf("failed to resolve mod dependencies\nDependencies:\n%s", runCtx.FormatDependencies())
+			return nil, &DiagnosticsError{
+				Diags:   unresolvedDependencyDiagnostics(runCtx),
+				message: fmt.Sprintf("failed to resolve mod dependencies\nDependencies:\n%s", runCtx.FormatDependencies()),
+			}
 		}
 	}
 
+	// add the resources of dependency mods included by benchmarks
+	// NOTE: this must be done before the tree is built, as the included resources are added to the tree
+	diags = resolveBenchmarkIncludes(runCtx)
+	if diags.HasErrors() {
+		return nil, newDiagnosticsError("Failed to resolve benchmark includes", diags)
+	}
+
 	// now tell mod to build tree of controls.
 	// NOTE: this also builds the sorted benchmark list
 	if err := mod.BuildResourceTree(); err != nil {
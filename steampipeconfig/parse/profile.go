@@ -0,0 +1,60 @@
+package parse
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/gohcl"
+	"github.com/turbot/steampipe/steampipeconfig/modconfig"
+)
+
+// DecodeProfile decodes a workspace profile block
+func DecodeProfile(block *hcl.Block, fileData map[string][]byte) (*modconfig.WorkspaceProfile, hcl.Diagnostics) {
+	profile := modconfig.NewWorkspaceProfile(block)
+
+	content, diags := block.Body.Content(ProfileBlockSchema)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	if attr, exists := content.Attributes["var_files"]; exists {
+		var varFiles []string
+		valDiags := gohcl.DecodeExpression(attr.Expr, nil, &varFiles)
+		diags = append(diags, valDiags...)
+		// resolve var files relative to the file containing the profile
+		for _, varFile := range varFiles {
+			if !filepath.IsAbs(varFile) {
+				varFile = filepath.Join(filepath.Dir(block.DefRange.Filename), varFile)
+			}
+			profile.VarFiles = append(profile.VarFiles, varFile)
+		}
+	}
+	if attr, exists := content.Attributes["search_path"]; exists {
+		valDiags := gohcl.DecodeExpression(attr.Expr, nil, &profile.SearchPath)
+		diags = append(diags, valDiags...)
+	}
+	if attr, exists := content.Attributes["search_path_prefix"]; exists {
+		valDiags := gohcl.DecodeExpression(attr.Expr, nil, &profile.SearchPathPrefix)
+		diags = append(diags, valDiags...)
+	}
+
+	for _, connectionBlock := range content.Blocks {
+		connection, moreDiags := DecodeConnection(connectionBlock, fileData)
+		if moreDiags.HasErrors() {
+			diags = append(diags, moreDiags...)
+			continue
+		}
+		if _, alreadyThere := profile.Connections[connection.Name]; alreadyThere {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  fmt.Sprintf("duplicate connection name '%s' in profile '%s'", connection.Name, profile.Name),
+				Subject:  &connectionBlock.DefRange,
+			})
+			continue
+		}
+		profile.Connections[connection.Name] = connection
+	}
+
+	return profile, diags
+}
@@ -0,0 +1,99 @@
+package parse
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/turbot/steampipe/steampipeconfig/modconfig"
+)
+
+type decodeProfileTest struct {
+	source   string
+	expected interface{}
+}
+
+var testCasesDecodeProfile = map[string]decodeProfileTest{
+	"var files and search path": {
+		source: `
+profile "dev" {
+  var_files          = ["dev.spvars", "/abs/dev.spvars"]
+  search_path        = ["aws_dev", "gcp"]
+  search_path_prefix = ["aws_dev"]
+}`,
+		expected: &modconfig.WorkspaceProfile{
+			Name:             "dev",
+			VarFiles:         []string{"/workspace/dev.spvars", "/abs/dev.spvars"},
+			SearchPath:       []string{"aws_dev", "gcp"},
+			SearchPathPrefix: []string{"aws_dev"},
+		},
+	},
+	"connections": {
+		source: `
+profile "dev" {
+  connection "aws" {
+    plugin = "aws"
+  }
+}`,
+		expected: &modconfig.WorkspaceProfile{
+			Name:        "dev",
+			Connections: map[string]*modconfig.Connection{"aws": nil},
+		},
+	},
+	"duplicate connection": {
+		source: `
+profile "dev" {
+  connection "aws" {
+    plugin = "aws"
+  }
+  connection "aws" {
+    plugin = "aws"
+  }
+}`,
+		expected: "ERROR",
+	},
+	"unknown attribute": {
+		source: `
+profile "dev" {
+  search = ["aws"]
+}`,
+		expected: "ERROR",
+	},
+}
+
+func TestDecodeProfile(t *testing.T) {
+	for name, test := range testCasesDecodeProfile {
+		fileName := "/workspace/workspace.spc"
+		file, diags := hclsyntax.ParseConfig([]byte(test.source), fileName, hcl.InitialPos)
+		if diags.HasErrors() {
+			t.Fatalf("Test: '%s' FAILED : failed to parse source: %s", name, diags.Error())
+		}
+		content, _ := file.Body.Content(ConfigBlockSchema)
+		profile, diags := DecodeProfile(content.Blocks[0], map[string][]byte{fileName: []byte(test.source)})
+		if diags.HasErrors() {
+			if test.expected != "ERROR" {
+				t.Errorf("Test: '%s' FAILED with unexpected error: %s", name, diags.Error())
+			}
+			continue
+		}
+		if test.expected == "ERROR" {
+			t.Errorf("Test: '%s' FAILED - expected error", name)
+			continue
+		}
+		expected := test.expected.(*modconfig.WorkspaceProfile)
+		if profile.Name != expected.Name ||
+			!reflect.DeepEqual(profile.VarFiles, expected.VarFiles) ||
+			!reflect.DeepEqual(profile.SearchPath, expected.SearchPath) ||
+			!reflect.DeepEqual(profile.SearchPathPrefix, expected.SearchPathPrefix) ||
+			len(profile.Connections) != len(expected.Connections) {
+			t.Errorf("Test: '%s' FAILED : expected:\n%s\n\ngot:\n%s", name, expected, profile)
+			continue
+		}
+		for connectionName := range expected.Connections {
+			if _, ok := profile.Connections[connectionName]; !ok {
+				t.Errorf("Test: '%s' FAILED : expected connection '%s'", name, connectionName)
+			}
+		}
+	}
+}
@@ -50,6 +50,9 @@ type RunContext struct {
 	BlockTypes []string
 	// if set, exclude these block types
 	BlockTypeExclusions []string
+	// if set, the hcl syntax of parsed files is cached, so only changed files are re-parsed when the mod is reloaded
+	// NOTE: all blocks are still decoded on every load
+	ParsedFileCache *ParsedFileCache
 
 	dependencyGraph *topsort.Graph
 	// map of ReferenceTypeValueMaps keyed by mod
@@ -360,6 +363,16 @@ func (r *RunContext) buildEvalContext() {
 		variables[mod] = cty.ObjectVal(refTypeMap)
 	}
 
+	// if we are parsing a dependency mod, it may reference its own resources without a mod prefix,
+	// e.g. a benchmark of the dependency mod may have children [control.c1], just as when the mod is the workspace mod
+	// - these references resolve to the resources of the dependency mod, not those of the workspace mod
+	// (the resources of the dependency mod are also available with the mod prefix, e.g. dep.control.c1)
+	if modName := r.currentModName(); modName != "local" {
+		for refType, typeValueMap := range r.referenceValues[modName] {
+			variables[refType] = cty.ObjectVal(typeValueMap)
+		}
+	}
+
 	//create evaluation context
 	r.EvalCtx = &hcl.EvalContext{
 		Variables: variables,
@@ -392,6 +405,14 @@ func (r *RunContext) storeResourceInCtyMap(resource modconfig.HclResource) hcl.D
 	return nil
 }
 
+// return the key of the current mod in the reference values - values of the workspace mod are keyed by "local"
+func (r *RunContext) currentModName() string {
+	if r.CurrentMod == nil || r.CurrentMod.ModPath == r.WorkspacePath {
+		return "local"
+	}
+	return r.CurrentMod.ShortName
+}
+
 func (r *RunContext) addReferenceValue(resource modconfig.HclResource, value cty.Value) hcl.Diagnostics {
 	parsedName, err := modconfig.ParseResourceName(resource.Name())
 	if err != nil {
@@ -412,12 +433,7 @@ func (r *RunContext) addReferenceValue(resource modconfig.HclResource, value cty
 
 	// the resource name will not have a mod - but the run context knows which mod we are parsing
 
-	mod := r.CurrentMod
-
-	modName := mod.ShortName
-	if mod.ModPath == r.WorkspacePath {
-		modName = "local"
-	}
+	modName := r.currentModName()
 	variablesForMod, ok := r.referenceValues[modName]
 	// do we have a map of reference values for this dep mod?
 	if !ok {
@@ -0,0 +1,84 @@
+package parse
+
+import (
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/turbot/steampipe/steampipeconfig/modconfig"
+	"github.com/zclconf/go-cty/cty"
+)
+
+type buildEvalContextTest struct {
+	// the path of the mod being parsed
+	modPath  string
+	expr     string
+	expected cty.Value
+	// if set, the expression is expected to fail to evaluate
+	expectError bool
+}
+
+const testWorkspacePath = "/workspace"
+
+var testCasesBuildEvalContext = map[string]buildEvalContextTest{
+	"dependency mod references own resource without prefix": {
+		modPath:  "/workspace/.steampipe/mods/github.com/turbot/dep@v1.0.0",
+		expr:     "control.c1",
+		expected: cty.StringVal("dep c1"),
+	},
+	"dependency mod references own resource with prefix": {
+		modPath:  "/workspace/.steampipe/mods/github.com/turbot/dep@v1.0.0",
+		expr:     "dep.control.c1",
+		expected: cty.StringVal("dep c1"),
+	},
+	"workspace mod references own resource": {
+		modPath:  testWorkspacePath,
+		expr:     "control.c1",
+		expected: cty.StringVal("local c1"),
+	},
+	"workspace mod references dependency resource": {
+		modPath:  testWorkspacePath,
+		expr:     "dep.control.c1",
+		expected: cty.StringVal("dep c1"),
+	},
+	"workspace mod references dependency resource without prefix": {
+		modPath:     testWorkspacePath,
+		expr:        "control.c2",
+		expectError: true,
+	},
+}
+
+func TestBuildEvalContext(t *testing.T) {
+	for name, test := range testCasesBuildEvalContext {
+		runCtx := NewRunContext(testWorkspacePath, CreateDefaultMod, nil)
+		runCtx.referenceValues["local"]["control"] = map[string]cty.Value{"c1": cty.StringVal("local c1")}
+		runCtx.referenceValues["dep"] = ReferenceTypeValueMap{
+			"control": {"c1": cty.StringVal("dep c1"), "c2": cty.StringVal("dep c2")},
+		}
+		shortName := "dep"
+		if test.modPath == testWorkspacePath {
+			shortName = "local"
+		}
+		runCtx.CurrentMod = &modconfig.Mod{ShortName: shortName, ModPath: test.modPath}
+		runCtx.buildEvalContext()
+
+		expr, diags := hclsyntax.ParseExpression([]byte(test.expr), "test.sp", hcl.InitialPos)
+		if diags.HasErrors() {
+			t.Fatalf("failed to parse expression: %s", diags.Error())
+		}
+		val, diags := expr.Value(runCtx.EvalCtx)
+		if diags.HasErrors() {
+			if !test.expectError {
+				t.Errorf("Test: '%s' FAILED with unexpected error: %s", name, diags.Error())
+			}
+			continue
+		}
+		if test.expectError {
+			t.Errorf("Test: '%s' FAILED : expected error, got %v", name, val)
+			continue
+		}
+		if !val.RawEquals(test.expected) {
+			t.Errorf("Test: '%s' FAILED : expected %v, got %v", name, test.expected, val)
+		}
+	}
+}
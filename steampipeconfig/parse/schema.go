@@ -16,6 +16,24 @@ var ConfigBlockSchema = &hcl.BodySchema{
 			Type:       "options",
 			LabelNames: []string{"type"},
 		},
+		{
+			Type:       "profile",
+			LabelNames: []string{"name"},
+		},
+	},
+}
+
+var ProfileBlockSchema = &hcl.BodySchema{
+	Attributes: []hcl.AttributeSchema{
+		{Name: "var_files"},
+		{Name: "search_path"},
+		{Name: "search_path_prefix"},
+	},
+	Blocks: []hcl.BlockHeaderSchema{
+		{
+			Type:       "connection",
+			LabelNames: []string{"name"},
+		},
 	},
 }
 
@@ -75,6 +93,10 @@ var ModBlockSchema = &hcl.BodySchema{
 		{
 			Type: modconfig.BlockTypeLocals,
 		},
+		{
+			Type:       modconfig.BlockTypeTest,
+			LabelNames: []string{"name"},
+		},
 	},
 }
 
@@ -87,6 +109,7 @@ var PanelBlockSchema = &hcl.BodySchema{
 		{Name: "height"},
 		{Name: "source"},
 		{Name: "sql"},
+		{Name: "refresh"},
 	},
 	Blocks: []hcl.BlockHeaderSchema{
 		{
@@ -103,6 +126,7 @@ var PanelBlockSchema = &hcl.BodySchema{
 var ReportBlockSchema = &hcl.BodySchema{
 	Attributes: []hcl.AttributeSchema{
 		{Name: "title"},
+		{Name: "refresh"},
 	},
 	Blocks: []hcl.BlockHeaderSchema{
 		{
@@ -158,5 +182,43 @@ var ParamDefBlockSchema = &hcl.BodySchema{
 	Attributes: []hcl.AttributeSchema{
 		{Name: "description"},
 		{Name: "default"},
+		{Name: "type"},
+	},
+	Blocks: []hcl.BlockHeaderSchema{
+		{
+			Type: "validation",
+		},
+	},
+}
+
+var ParamValidationBlockSchema = &hcl.BodySchema{
+	Attributes: []hcl.AttributeSchema{
+		{Name: "allowed_values"},
+		{Name: "regex"},
+		{Name: "min"},
+		{Name: "max"},
+		{Name: "error_message"},
+	},
+}
+
+var TestBlockSchema = &hcl.BodySchema{
+	Attributes: []hcl.AttributeSchema{
+		{Name: "title"},
+		{Name: "description"},
+		{Name: "control", Required: true},
+		{Name: "expect", Required: true},
+	},
+	Blocks: []hcl.BlockHeaderSchema{
+		{
+			Type:       "fixture",
+			LabelNames: []string{"table"},
+		},
+	},
+}
+
+var TestFixtureBlockSchema = &hcl.BodySchema{
+	Attributes: []hcl.AttributeSchema{
+		{Name: "rows", Required: true},
+		{Name: "columns"},
 	},
 }
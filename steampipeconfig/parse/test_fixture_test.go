@@ -0,0 +1,89 @@
+package parse
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+)
+
+type decodeTestFixtureTest struct {
+	source string
+	// the expected column names and types, or "ERROR"
+	expected interface{}
+}
+
+var testCasesDecodeTestFixture = map[string]decodeTestFixtureTest{
+	"inferred column types": {
+		source: `
+fixture "aws_s3_bucket" {
+  rows = [
+    { name = "b1", versioning_enabled = true },
+    { name = "b2", size = 10, region = null },
+  ]
+}`,
+		expected: map[string]string{"name": "text", "region": "text", "size": "numeric", "versioning_enabled": "boolean"},
+	},
+	"explicit column types": {
+		source: `
+fixture "aws_s3_bucket" {
+  columns = { created = "timestamp with time zone", size = "numeric(10,2)", tags = "text[]" }
+  rows = [
+    { name = "b1" },
+  ]
+}`,
+		expected: map[string]string{"created": "timestamp with time zone", "name": "text", "size": "numeric(10,2)", "tags": "text[]"},
+	},
+	"invalid column type": {
+		source: `
+fixture "aws_s3_bucket" {
+  columns = { name = "text); drop table foo; --" }
+  rows = [
+    { name = "b1" },
+  ]
+}`,
+		expected: "ERROR",
+	},
+	"introspection table": {
+		source: `
+fixture "steampipe_control" {
+  rows = [
+    { resource_name = "c1" },
+  ]
+}`,
+		expected: "ERROR",
+	},
+}
+
+func TestDecodeTestFixture(t *testing.T) {
+	runCtx := &RunContext{EvalCtx: &hcl.EvalContext{}}
+	for name, test := range testCasesDecodeTestFixture {
+		file, diags := hclsyntax.ParseConfig([]byte(test.source), "test.sp", hcl.InitialPos)
+		if diags.HasErrors() {
+			t.Fatalf("Test: '%s' FAILED : failed to parse source: %s", name, diags.Error())
+		}
+		content, _ := file.Body.Content(&hcl.BodySchema{
+			Blocks: []hcl.BlockHeaderSchema{{Type: "fixture", LabelNames: []string{"table"}}},
+		})
+		fixture, diags := decodeTestFixture(content.Blocks[0], runCtx, "test.t1")
+		if diags.HasErrors() {
+			if test.expected != "ERROR" {
+				t.Errorf("Test: '%s' FAILED with unexpected error: %s", name, diags.Error())
+			}
+			continue
+		}
+		if test.expected == "ERROR" {
+			t.Errorf("Test: '%s' FAILED - expected error", name)
+			continue
+		}
+		expected := test.expected.(map[string]string)
+		if !reflect.DeepEqual(fixture.Columns, expected) {
+			t.Errorf("Test: '%s' FAILED : expected columns %v, got %v", name, expected, fixture.Columns)
+		}
+		// the column names are sorted
+		if expectedNames := sortedColumnNames(expected); !reflect.DeepEqual(fixture.ColumnNames, expectedNames) {
+			t.Errorf("Test: '%s' FAILED : expected column names %v, got %v", name, expectedNames, fixture.ColumnNames)
+		}
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"reflect"
 	"strings"
 
+	"github.com/turbot/steampipe/constants"
 	"github.com/turbot/steampipe/utils"
 
 	"github.com/turbot/steampipe/steampipeconfig/modconfig"
@@ -26,12 +27,18 @@ type SteampipeConfig struct {
 	DatabaseOptions          *options.Database
 	TerminalOptions          *options.Terminal
 	GeneralOptions           *options.General
-	commandName              string
+
+	// map of profile name to the workspace profiles defined in the workspace config
+	Profiles map[string]*modconfig.WorkspaceProfile
+	// the profile selected with the --profile flag (may be nil)
+	ActiveProfile *modconfig.WorkspaceProfile
+	commandName   string
 }
 
 func NewSteampipeConfig(commandName string) *SteampipeConfig {
 	return &SteampipeConfig{
 		Connections: make(map[string]*modconfig.Connection),
+		Profiles:    make(map[string]*modconfig.WorkspaceProfile),
 		commandName: commandName,
 	}
 }
@@ -71,10 +78,30 @@ func (c *SteampipeConfig) ConfigMap() map[string]interface{} {
 	if c.TerminalOptions != nil {
 		c.populateConfigMapForOptions(c.TerminalOptions, res)
 	}
+	// the search path settings of the active profile have precedence over all options
+	if c.ActiveProfile != nil {
+		if len(c.ActiveProfile.SearchPath) > 0 {
+			res[constants.ArgSearchPath] = c.ActiveProfile.SearchPath
+		}
+		if len(c.ActiveProfile.SearchPathPrefix) > 0 {
+			res[constants.ArgSearchPathPrefix] = c.ActiveProfile.SearchPathPrefix
+		}
+	}
 
 	return res
 }
 
+// ApplyProfile sets the active profile and overrides the connection config with any connections the profile defines
+func (c *SteampipeConfig) ApplyProfile(profile *modconfig.WorkspaceProfile) {
+	c.ActiveProfile = profile
+	if profile == nil {
+		return
+	}
+	for name, connection := range profile.Connections {
+		c.Connections[name] = connection
+	}
+}
+
 // populate the config map for a given options object
 // NOTE: this mutates configMap
 func (c *SteampipeConfig) populateConfigMapForOptions(o options.Options, configMap map[string]interface{}) {
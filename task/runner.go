@@ -88,8 +88,9 @@ func (r *Runner) shouldRun() bool {
 
 	cmd := viper.Get(constants.ConfigKeyActiveCommand).(*cobra.Command)
 	cmdArgs := viper.GetStringSlice(constants.ConfigKeyActiveCommandArgs)
-	if isServiceStopCmd(cmd) || isBatchQueryCmd(cmd, cmdArgs) || isCompletionCmd(cmd) {
+	if isServiceStopCmd(cmd) || isBatchQueryCmd(cmd, cmdArgs) || isCompletionCmd(cmd) || isLspCmd(cmd) {
 		// no scheduled tasks for `service stop` and `query <sql>`
+		// nor for `lsp`, as notifications would corrupt the protocol output
 		return false
 	}
 
@@ -114,6 +115,10 @@ func isCompletionCmd(cmd *cobra.Command) bool {
 	return cmd.Name() == "completion"
 }
 
+func isLspCmd(cmd *cobra.Command) bool {
+	return cmd.Name() == "lsp"
+}
+
 func isBatchQueryCmd(cmd *cobra.Command, cmdArgs []string) bool {
 	return cmd.Name() == "query" && len(cmdArgs) > 0
 }
@@ -0,0 +1,86 @@
+package utils
+
+import (
+	"io"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// SensitiveValueRedacted is displayed in place of the value of a sensitive variable
+const SensitiveValueRedacted = "(sensitive value)"
+
+// values shorter than this are not redacted - they are too likely to occur in unrelated output
+const minSensitiveValueLength = 4
+
+var (
+	sensitiveValues = map[string]struct{}{}
+	// the replacer used to redact the sensitive values - this is rebuilt whenever a value is added
+	sensitiveValueReplacer *strings.Replacer
+	sensitiveValuesLock    sync.RWMutex
+)
+
+// AddSensitiveValues registers values which must be redacted from any output written by a RedactingWriter
+func AddSensitiveValues(values ...string) {
+	sensitiveValuesLock.Lock()
+	defer sensitiveValuesLock.Unlock()
+	added := false
+	for _, value := range values {
+		// ignore short values - these would redact parts of unrelated output
+		if _, ok := sensitiveValues[value]; !ok && len(value) >= minSensitiveValueLength {
+			sensitiveValues[value] = struct{}{}
+			added = true
+		}
+	}
+	if added {
+		sensitiveValueReplacer = newSensitiveValueReplacer()
+	}
+}
+
+// build a replacer for all sensitive values
+// the replacer tries the values in order, so sort them longest first - if one value contains another,
+// the longer value is redacted in full
+func newSensitiveValueReplacer() *strings.Replacer {
+	sortedValues := make([]string, 0, len(sensitiveValues))
+	for value := range sensitiveValues {
+		sortedValues = append(sortedValues, value)
+	}
+	sort.Slice(sortedValues, func(i, j int) bool {
+		if len(sortedValues[i]) != len(sortedValues[j]) {
+			return len(sortedValues[i]) > len(sortedValues[j])
+		}
+		return sortedValues[i] < sortedValues[j]
+	})
+	replacements := make([]string, 0, 2*len(sortedValues))
+	for _, value := range sortedValues {
+		replacements = append(replacements, value, SensitiveValueRedacted)
+	}
+	return strings.NewReplacer(replacements...)
+}
+
+// Redact replaces all registered sensitive values in the string with SensitiveValueRedacted
+func Redact(s string) string {
+	sensitiveValuesLock.RLock()
+	defer sensitiveValuesLock.RUnlock()
+	if sensitiveValueReplacer == nil {
+		return s
+	}
+	return sensitiveValueReplacer.Replace(s)
+}
+
+// RedactingWriter wraps a writer, redacting all registered sensitive values from the data written to it
+type RedactingWriter struct {
+	w io.Writer
+}
+
+func NewRedactingWriter(w io.Writer) *RedactingWriter {
+	return &RedactingWriter{w: w}
+}
+
+func (r *RedactingWriter) Write(p []byte) (int, error) {
+	if _, err := io.WriteString(r.w, Redact(string(p))); err != nil {
+		return 0, err
+	}
+	// report the length of the unredacted data, as that is what the caller passed
+	return len(p), nil
+}
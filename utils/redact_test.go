@@ -0,0 +1,15 @@
+package utils
+
+import "testing"
+
+func TestRedact(t *testing.T) {
+	// register the shorter value first - the longer value which contains it must still be redacted in full
+	AddSensitiveValues("secret", "my-secret-value", "ab")
+
+	for i := 0; i < 20; i++ {
+		expected := "token=" + SensitiveValueRedacted + " other=" + SensitiveValueRedacted + " ab"
+		if redacted := Redact("token=my-secret-value other=secret ab"); redacted != expected {
+			t.Fatalf("Test: 'redact' FAILED : expected %s, got %s", expected, redacted)
+		}
+	}
+}
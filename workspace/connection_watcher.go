@@ -8,6 +8,7 @@ import (
 	"github.com/turbot/steampipe/steampipeconfig"
 
 	"github.com/fsnotify/fsnotify"
+	"github.com/spf13/viper"
 	filehelpers "github.com/turbot/go-kit/files"
 	"github.com/turbot/steampipe/constants"
 	"github.com/turbot/steampipe/db/db_common"
@@ -60,7 +61,13 @@ func NewConnectionWatcher(invoker constants.Invoker, errorHandler func(error)) (
 }
 
 func (w *ConnectionWatcher) handleFileWatcherEvent([]fsnotify.Event) {
-	config, err := steampipeconfig.LoadConnectionConfig()
+	// if a workspace profile is active, reload the workspace config as well,
+	// so the connection overrides of the profile are resolved from the reloaded config
+	workspacePath := ""
+	if steampipeconfig.Config != nil && steampipeconfig.Config.ActiveProfile != nil {
+		workspacePath = viper.GetString(constants.ArgWorkspace)
+	}
+	config, err := steampipeconfig.LoadSteampipeConfig(workspacePath, "")
 	if err != nil {
 		fmt.Println()
 		utils.ShowError(err)
@@ -42,6 +42,9 @@ type Workspace struct {
 	listFlag                filehelpers.ListFlag
 	fileWatcherErrorHandler func(error)
 	watcherError            error
+	// cache of parsed hcl files, so that only changed files are re-parsed when the workspace is reloaded
+	// (the blocks of all files are still decoded)
+	parsedFileCache *parse.ParsedFileCache
 	// event handlers
 	reportEventHandlers []reportevents.ReportEventHandler
 }
@@ -53,7 +56,8 @@ func Load(workspacePath string) (*Workspace, error) {
 
 	// create shell workspace
 	workspace := &Workspace{
-		Path: workspacePath,
+		Path:            workspacePath,
+		parsedFileCache: parse.NewParsedFileCache(),
 	}
 
 	// determine whether to load files recursively or just from the top level folder
@@ -93,6 +97,55 @@ func LoadResourceNames(workspacePath string) (*modconfig.WorkspaceResources, err
 	return workspace.loadWorkspaceResourceName()
 }
 
+// LoadVariableDefinitions loads the variables declared by the workspace mod, keyed by variable name
+// variable values are not resolved
+func LoadVariableDefinitions(workspacePath string) (map[string]*modconfig.Variable, error) {
+	// create shell workspace
+	workspace := &Workspace{
+		Path: workspacePath,
+	}
+
+	// determine whether to load files recursively or just from the top level folder
+	workspace.setListFlag()
+
+	// load the .steampipe ignore file
+	if err := workspace.loadExclusions(); err != nil {
+		return nil, err
+	}
+
+	// only load variables blocks
+	runCtx := workspace.getRunContext()
+	runCtx.BlockTypes = []string{modconfig.BlockTypeVariable}
+	mod, err := steampipeconfig.LoadMod(workspacePath, runCtx)
+	if err != nil {
+		return nil, err
+	}
+	return mod.Variables, nil
+}
+
+// ListModFiles returns the paths of all mod files in the workspace, i.e. all files with a mod file extension
+// the workspace mod is not loaded
+func ListModFiles(workspacePath string) ([]string, error) {
+	// create shell workspace
+	workspace := &Workspace{
+		Path: workspacePath,
+	}
+
+	// determine whether to list files recursively or just from the top level folder
+	workspace.setListFlag()
+
+	// load the .steampipe ignore file
+	if err := workspace.loadExclusions(); err != nil {
+		return nil, err
+	}
+
+	return filehelpers.ListFiles(workspacePath, &filehelpers.ListOptions{
+		Flags:   workspace.listFlag,
+		Exclude: workspace.exclusions,
+		Include: filehelpers.InclusionsFromExtensions(steampipeconfig.GetModFileExtensions()),
+	})
+}
+
 func (w *Workspace) SetupWatcher(client db_common.Client, errorHandler func(error)) error {
 	watcherOptions := &utils.WatcherOptions{
 		Directories: []string{w.Path},
@@ -276,6 +329,8 @@ func (w *Workspace) loadWorkspaceMod() error {
 	if err != nil {
 		return err
 	}
+	// remove any deleted files from the parsed file cache
+	w.parsedFileCache.Prune()
 
 	// now set workspace properties
 	w.Mod = m
@@ -295,7 +350,7 @@ func (w *Workspace) loadWorkspaceMod() error {
 // build options used to load workspace
 // set flags to create pseudo resources and a default mod if needed
 func (w *Workspace) getRunContext() *parse.RunContext {
-	return parse.NewRunContext(
+	runCtx := parse.NewRunContext(
 		w.Path,
 		parse.CreatePseudoResources|parse.CreateDefaultMod,
 		&filehelpers.ListOptions{
@@ -305,6 +360,14 @@ func (w *Workspace) getRunContext() *parse.RunContext {
 			// only load .sp files
 			Include: filehelpers.InclusionsFromExtensions([]string{constants.ModDataExtension}),
 		})
+	runCtx.ParsedFileCache = w.parsedFileCache
+	return runCtx
+}
+
+// SourceFiles returns the paths of the source files of the workspace mod (excluding dependency mods)
+func (w *Workspace) SourceFiles() ([]string, error) {
+	runCtx := w.getRunContext()
+	return filehelpers.ListFiles(w.Path, runCtx.ListOptions)
 }
 
 func (w *Workspace) loadWorkspaceResourceName() (*modconfig.WorkspaceResources, error) {
@@ -587,10 +650,19 @@ func (w *Workspace) ResolveQuery(sqlString string, args *modconfig.QueryArgs) (s
 func (w *Workspace) ResolveControlQuery(control *modconfig.Control) (string, error) {
 	log.Printf("[TRACE] ResolveControlQuery for %s", control.FullName)
 
+	source, err := w.ResolveControlQuerySource(control)
+	if err != nil {
+		return "", err
+	}
+	return modconfig.GetPreparedStatementExecuteSQL(source, control.Args)
+}
+
+// ResolveControlQuerySource returns the source of the control SQL - either the control itself, or the named query it refers to
+func (w *Workspace) ResolveControlQuerySource(control *modconfig.Control) (modconfig.PreparedStatementProvider, error) {
 	// verify we have either SQL or a Query defined
 	if control.SQL == nil && control.Query == nil {
 		// this should never happen as we should catch it in the parsing stage
-		return "", fmt.Errorf("%s must define either a 'sql' property or a 'query' property", control.FullName)
+		return nil, fmt.Errorf("%s must define either a 'sql' property or a 'query' property", control.FullName)
 	}
 
 	// set the source for the query - this will either be the control itself or any named query the control refers to
@@ -605,7 +677,7 @@ func (w *Workspace) ResolveControlQuery(control *modconfig.Control) (string, err
 		if namedQuery, ok := w.GetQuery(*control.SQL); ok {
 			// in this case, it is NOT valid for the control to define its own Param definitions
 			if control.Params != nil {
-				return "", fmt.Errorf("%s has an 'SQL' property which refers to %s, so it cannot define 'param' blocks", control.FullName, namedQuery.FullName)
+				return nil, fmt.Errorf("%s has an 'SQL' property which refers to %s, so it cannot define 'param' blocks", control.FullName, namedQuery.FullName)
 			}
 			source = namedQuery
 		} else {
@@ -614,7 +686,7 @@ func (w *Workspace) ResolveControlQuery(control *modconfig.Control) (string, err
 		}
 	}
 
-	return modconfig.GetPreparedStatementExecuteSQL(source, control.Args)
+	return source, nil
 }
 
 func (w *Workspace) getQueryFromFile(filename string) (string, bool, error) {
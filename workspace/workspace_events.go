@@ -47,12 +47,13 @@ func (w *Workspace) handleFileWatcherEvent(client db_common.Client, events []fsn
 	// clear watcher error
 	w.watcherError = nil
 	resourceMaps := w.GetResourceMaps()
-	// if resources have changed, update introspection tables and prepared statements
-	if !prevResourceMaps.Equals(resourceMaps) {
+	// if resources have changed, update the introspection tables and prepared statements for the changed resources
+	// (and any resources which reference them)
+	if diff := prevResourceMaps.Diff(resourceMaps); diff.HasChanges() {
 		// first update prepared statements
-		db_common.UpdatePreparedStatements(context.Background(), prevResourceMaps, resourceMaps, client)
+		db_common.UpdateChangedPreparedStatements(context.Background(), diff, client)
 		// then update the introspection tables
-		db_common.UpdateIntrospectionTables(resourceMaps, client)
+		db_common.UpdateChangedIntrospectionTables(diff, client)
 	}
 	w.raiseReportChangedEvents(w.getPanelMap(), prevPanels, w.getReportMap(), prevReports)
 }
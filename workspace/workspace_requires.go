@@ -17,7 +17,7 @@ import (
 func (w *Workspace) CheckRequiredPluginsInstalled() error {
 
 	// get the list of all installed plugins
-	installedPlugins, err := w.getInstalledPlugins()
+	installedPlugins, err := w.GetInstalledPlugins()
 	if err != nil {
 		return err
 	}
@@ -66,7 +66,8 @@ func (w *Workspace) getRequiredPlugins() map[string]*version.Version {
 	return nil
 }
 
-func (w *Workspace) getInstalledPlugins() (map[string]*version.Version, error) {
+// GetInstalledPlugins returns the versions of all installed plugins, keyed by plugin short name (i.e. org/name)
+func (w *Workspace) GetInstalledPlugins() (map[string]*version.Version, error) {
 	installedPlugins := make(map[string]*version.Version)
 	installedPluginsData, _ := plugin.List(nil)
 	for _, plugin := range installedPluginsData {
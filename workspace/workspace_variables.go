@@ -11,7 +11,9 @@ import (
 	"github.com/turbot/steampipe/steampipeconfig"
 	"github.com/turbot/steampipe/steampipeconfig/input_vars"
 	"github.com/turbot/steampipe/steampipeconfig/modconfig"
+	"github.com/turbot/steampipe/steampipeconfig/parse"
 	"github.com/turbot/steampipe/utils"
+	"github.com/zclconf/go-cty/cty"
 )
 
 func (w *Workspace) getAllVariables() (map[string]*modconfig.Variable, error) {
@@ -37,7 +39,7 @@ func (w *Workspace) getAllVariables() (map[string]*modconfig.Variable, error) {
 		return nil, err
 	}
 
-	if err := validateVariables(variableMap, inputVariables); err != nil {
+	if err := validateVariables(variableMap, inputVariables, w.Path); err != nil {
 		return nil, err
 	}
 
@@ -50,11 +52,30 @@ func (w *Workspace) getAllVariables() (map[string]*modconfig.Variable, error) {
 			inputValue.SourceRange)
 	}
 
+	// ensure the values of sensitive variables are not written to the logs
+	// - if no value was input, the default is used, so register that
+	for name, variable := range variableMap {
+		if !variable.Sensitive {
+			continue
+		}
+		value := variable.Default
+		if inputValue, ok := inputVariables[name]; ok {
+			value = inputValue.Value
+		}
+		if value != cty.NilVal {
+			utils.AddSensitiveValues(sensitiveValueStrings(value)...)
+		}
+	}
+
 	return variableMap, nil
 }
 
 func (w *Workspace) getInputVariables(variableMap map[string]*modconfig.Variable) (input_vars.InputValues, error) {
 	variableFileArgs := viper.GetStringSlice(constants.ArgVarFile)
+	// var files from the active profile are loaded first, so values from --var-file have precedence
+	if steampipeconfig.Config != nil && steampipeconfig.Config.ActiveProfile != nil {
+		variableFileArgs = append(steampipeconfig.Config.ActiveProfile.VarFiles, variableFileArgs...)
+	}
 	variableArgs := viper.GetStringSlice(constants.ArgVariable)
 
 	inputValuesUnparsed, diags := input_vars.CollectVariableValues(w.Path, variableFileArgs, variableArgs)
@@ -70,8 +91,12 @@ func (w *Workspace) getInputVariables(variableMap map[string]*modconfig.Variable
 	return parsedValues, diags.Err()
 }
 
-func validateVariables(variableMap map[string]*modconfig.Variable, variables input_vars.InputValues) error {
+func validateVariables(variableMap map[string]*modconfig.Variable, variables input_vars.InputValues, workspacePath string) error {
 	diags := input_vars.CheckInputVariables(variableMap, variables)
+	if !diags.HasErrors() {
+		// only evaluate the validation rules if all values are valid for their variable type
+		diags = diags.Append(input_vars.EvaluateVariableValidations(variableMap, variables, workspacePath))
+	}
 	if diags.HasErrors() {
 		displayValidationErrors(diags)
 		// return empty error
@@ -113,3 +138,24 @@ func identifyMissingVariables(existing map[string]input_vars.UnparsedVariableVal
 	return nil
 
 }
+
+// return the string representations of a sensitive variable value which should be redacted from the logs
+// for collections, the string representation of each element is also returned
+// bool and number values are skipped - redacting these would mangle unrelated output
+func sensitiveValueStrings(value cty.Value) []string {
+	var res []string
+	cty.Walk(value, func(_ cty.Path, v cty.Value) (bool, error) {
+		if v.IsNull() || !v.IsKnown() {
+			return false, nil
+		}
+		if v.Type() == cty.String {
+			res = append(res, v.AsString())
+		} else if v.Type().IsPrimitiveType() {
+			return true, nil
+		} else if jsonStr, err := parse.CtyToJSON(v); err == nil {
+			res = append(res, jsonStr)
+		}
+		return true, nil
+	})
+	return res
+}